@@ -12,6 +12,8 @@ import (
 
 	"github.com/usernamesalah/rh-pos/internal/config"
 	"github.com/usernamesalah/rh-pos/internal/handler"
+	"github.com/usernamesalah/rh-pos/internal/pkg/loginguard"
+	"github.com/usernamesalah/rh-pos/internal/pkg/notifier"
 	"github.com/usernamesalah/rh-pos/internal/pkg/storage/minio"
 	"github.com/usernamesalah/rh-pos/internal/repository"
 	"github.com/usernamesalah/rh-pos/internal/server"
@@ -73,13 +75,61 @@ func run(cfg *config.Config, appLogger *slog.Logger) error {
 	productRepo := repository.NewProductRepository(db, appLogger)
 	transactionRepo := repository.NewTransactionRepository(db, appLogger)
 	tenantRepo := repository.NewTenantRepository(db, appLogger)
+	stockMovementRepo := repository.NewStockMovementRepository(db, appLogger)
+	stockOpnameRepo := repository.NewStockOpnameRepository(db, appLogger)
+	supplierRepo := repository.NewSupplierRepository(db, appLogger)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db, appLogger)
+	categoryRepo := repository.NewCategoryRepository(db, appLogger)
+	tagRepo := repository.NewTagRepository(db, appLogger)
+	barcodeRepo := repository.NewProductBarcodeRepository(db, appLogger)
+	modifierGroupRepo := repository.NewModifierGroupRepository(db, appLogger)
+	promotionRepo := repository.NewPromotionRepository(db, appLogger)
+	voucherRepo := repository.NewVoucherRepository(db, appLogger)
+	customerRepo := repository.NewCustomerRepository(db, appLogger)
+	loyaltyRepo := repository.NewLoyaltyRepository(db, appLogger)
+	shiftRepo := repository.NewShiftRepository(db, appLogger)
+	roleRepo := repository.NewRoleRepository(db, appLogger)
+	sessionRepo := repository.NewSessionRepository(db, appLogger)
+	terminalRepo := repository.NewTerminalRepository(db, appLogger)
+	authAuditRepo := repository.NewAuthAuditRepository(db, appLogger)
+
+	// Initialize stock alert notifier
+	var stockNotifier notifier.Notifier = notifier.NewLogNotifier(appLogger)
+	if cfg.Notifier.WebhookURL != "" {
+		stockNotifier = notifier.NewWebhookNotifier(cfg.Notifier.WebhookURL, cfg.Notifier.Timeout)
+	}
+
+	// Initialize failed login tracking
+	var loginAttempts loginguard.Store = loginguard.NewMemoryStore(loginguard.DefaultUserPolicy.Window)
+	if cfg.Login.AttemptStore == "db" {
+		dbStore := loginguard.NewDBStore(db)
+		loginAttempts = dbStore
+
+		sweepCtx, stopSweep := context.WithCancel(context.Background())
+		defer stopSweep()
+		go sweepLoginAttempts(sweepCtx, dbStore, appLogger)
+	}
+	loginGuard := loginguard.NewGuard(loginAttempts, loginguard.DefaultUserPolicy, loginguard.DefaultIPPolicy)
 
 	// Initialize use cases
-	authUseCase := usecase.NewAuthService(userRepo, cfg.JWT.Secret, appLogger)
-	productUseCase := usecase.NewProductService(productRepo, minioClient, appLogger)
-	transactionUseCase := usecase.NewTransactionService(transactionRepo, productRepo, db, appLogger)
+	authUseCase := usecase.NewAuthService(userRepo, sessionRepo, terminalRepo, authAuditRepo, loginGuard, cfg.JWT.Secret, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL, appLogger)
+	productUseCase := usecase.NewProductService(productRepo, categoryRepo, tagRepo, barcodeRepo, stockMovementRepo, minioClient, db, appLogger)
+	stockAlertUseCase := usecase.NewStockAlertService(productRepo, stockNotifier, appLogger)
+	transactionUseCase := usecase.NewTransactionService(transactionRepo, productRepo, barcodeRepo, tenantRepo, stockAlertUseCase, db, appLogger)
 	reportUseCase := usecase.NewReportService(transactionRepo, appLogger)
 	tenantUseCase := usecase.NewTenantService(tenantRepo, appLogger)
+	stockOpnameUseCase := usecase.NewStockOpnameService(stockOpnameRepo, db, appLogger)
+	supplierUseCase := usecase.NewSupplierService(supplierRepo, appLogger)
+	purchaseOrderUseCase := usecase.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, productRepo, db, appLogger)
+	categoryUseCase := usecase.NewCategoryService(categoryRepo, appLogger)
+	modifierUseCase := usecase.NewModifierService(modifierGroupRepo, productRepo, appLogger)
+	promotionUseCase := usecase.NewPromotionService(promotionRepo, voucherRepo, productRepo, appLogger)
+	customerUseCase := usecase.NewCustomerService(customerRepo, transactionRepo, appLogger)
+	loyaltyUseCase := usecase.NewLoyaltyService(loyaltyRepo, appLogger)
+	shiftUseCase := usecase.NewShiftService(shiftRepo, db, appLogger)
+	roleUseCase := usecase.NewRoleService(roleRepo, appLogger)
+	userUseCase := usecase.NewUserService(userRepo, roleRepo, sessionRepo, authUseCase, appLogger)
+	terminalUseCase := usecase.NewTerminalService(terminalRepo, sessionRepo, appLogger)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authUseCase, tenantUseCase, appLogger)
@@ -87,15 +137,43 @@ func run(cfg *config.Config, appLogger *slog.Logger) error {
 	transactionHandler := handler.NewTransactionHandler(transactionUseCase, appLogger)
 	reportHandler := handler.NewReportHandler(reportUseCase, appLogger)
 	adminHandler := handler.NewAdminHandler(tenantUseCase, authUseCase)
+	stockOpnameHandler := handler.NewStockOpnameHandler(stockOpnameUseCase, appLogger)
+	supplierHandler := handler.NewSupplierHandler(supplierUseCase, appLogger)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderUseCase, appLogger)
+	categoryHandler := handler.NewCategoryHandler(categoryUseCase, appLogger)
+	modifierHandler := handler.NewModifierHandler(modifierUseCase, appLogger)
+	promotionHandler := handler.NewPromotionHandler(promotionUseCase, appLogger)
+	customerHandler := handler.NewCustomerHandler(customerUseCase, appLogger)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyUseCase, appLogger)
+	orderHandler := handler.NewOrderHandler(transactionUseCase, appLogger)
+	shiftHandler := handler.NewShiftHandler(shiftUseCase, appLogger)
+	roleHandler := handler.NewRoleHandler(roleUseCase, appLogger)
+	userHandler := handler.NewUserHandler(userUseCase, appLogger)
+	terminalHandler := handler.NewTerminalHandler(terminalUseCase, appLogger)
 
 	// Setup router
 	e := server.SetupRouter(
 		cfg,
+		authUseCase,
+		roleUseCase,
 		authHandler,
 		productHandler,
 		transactionHandler,
 		reportHandler,
 		adminHandler,
+		stockOpnameHandler,
+		supplierHandler,
+		purchaseOrderHandler,
+		categoryHandler,
+		modifierHandler,
+		promotionHandler,
+		customerHandler,
+		loyaltyHandler,
+		orderHandler,
+		shiftHandler,
+		roleHandler,
+		userHandler,
+		terminalHandler,
 	)
 
 	// Start server
@@ -148,3 +226,26 @@ func run(cfg *config.Config, appLogger *slog.Logger) error {
 
 	return nil
 }
+
+// sweepLoginAttempts deletes idle failed login counters every few minutes until ctx is done
+func sweepLoginAttempts(ctx context.Context, store *loginguard.DBStore, appLogger *slog.Logger) {
+	window := max(loginguard.DefaultUserPolicy.Window, loginguard.DefaultIPPolicy.Window)
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			deleted, err := store.Sweep(ctx, window, now)
+			if err != nil {
+				appLogger.Error("failed to sweep login attempts", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				appLogger.Info("swept login attempts", "deleted", deleted)
+			}
+		}
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,12 +18,15 @@ type Config struct {
 	Logger   LoggerConfig
 	Admin    AdminConfig
 	MinIO    MinIOConfig
+	Notifier NotifierConfig
+	Login    LoginConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port           string
+	Host           string
+	TrustedProxies []*net.IPNet // proxies whose X-Forwarded-For is trusted for the client IP; none means use the connection address
 }
 
 // DatabaseConfig holds database configuration
@@ -36,7 +41,9 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret string
+	Secret          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 // LoggerConfig holds logger configuration
@@ -61,6 +68,17 @@ type MinIOConfig struct {
 	DefaultExpiry   time.Duration
 }
 
+// NotifierConfig holds stock alert notifier configuration
+type NotifierConfig struct {
+	WebhookURL string
+	Timeout    time.Duration
+}
+
+// LoginConfig holds failed login tracking configuration
+type LoginConfig struct {
+	AttemptStore string // "memory", or "db" to share counters between instances
+}
+
 // Load loads configuration from .env file and environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists (ignore error if file doesn't exist)
@@ -82,7 +100,9 @@ func Load() (*Config, error) {
 			Name:     getEnv("DB_NAME", "rh_pos"),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
+			Secret:          getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
+			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
 		},
 		Logger: LoggerConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
@@ -100,6 +120,13 @@ func Load() (*Config, error) {
 			Bucket:          getEnv("MINIO_BUCKET", "rh-pos"),
 			DefaultExpiry:   time.Hour * 1, // 24 hours default expiry
 		},
+		Notifier: NotifierConfig{
+			WebhookURL: getEnv("STOCK_ALERT_WEBHOOK_URL", ""),
+			Timeout:    time.Second * 10,
+		},
+		Login: LoginConfig{
+			AttemptStore: getEnv("LOGIN_ATTEMPT_STORE", "memory"),
+		},
 	}
 
 	// Validate required fields
@@ -115,6 +142,16 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("ADMIN_USERNAME and ADMIN_PASSWORD are required")
 	}
 
+	if config.Login.AttemptStore != "memory" && config.Login.AttemptStore != "db" {
+		return nil, fmt.Errorf("LOGIN_ATTEMPT_STORE must be memory or db")
+	}
+
+	trustedProxies, err := parseCIDRs(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	config.Server.TrustedProxies = trustedProxies
+
 	// Validate MinIO configuration
 	if config.MinIO.AccessKeyID == "" || config.MinIO.SecretAccessKey == "" {
 		return nil, fmt.Errorf("MINIO_ACCESS_KEY and MINIO_SECRET_KEY are required")
@@ -140,3 +177,41 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration gets a duration environment variable such as "15m" with a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// parseCIDRs parses a comma-separated list of CIDR ranges such as "10.0.0.0/8,192.168.1.10/32".
+// A bare IP address is treated as a single-address range.
+func parseCIDRs(value string) ([]*net.IPNet, error) {
+	var ranges []*net.IPNet
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			ip := net.ParseIP(part)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", part)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR range %q: %w", part, err)
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges, nil
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// Category groups products; categories nest through ParentID within a tenant
+type Category struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	ParentID  *uint     `json:"parent_id" gorm:"index"`
+	Parent    *Category `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	TenantID  *uint     `json:"tenant_id" gorm:"index"`
+	Tenant    *Tenant   `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (Category) TableName() string {
+	return "categories"
+}
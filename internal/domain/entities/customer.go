@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Customer represents a tenant's customer. Phone is unique per tenant and is how
+// cashiers look customers up at the till.
+type Customer struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Phone     string    `json:"phone" gorm:"not null;uniqueIndex:idx_customers_tenant_phone"`
+	Email     string    `json:"email"`
+	Notes     string    `json:"notes" gorm:"type:text"`
+	TenantID  *uint     `json:"tenant_id" gorm:"uniqueIndex:idx_customers_tenant_phone"`
+	Tenant    *Tenant   `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (Customer) TableName() string {
+	return "customers"
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "errors"
+
+// Domain errors shared by services and handlers
+var (
+	ErrInvalidTransactionState   = errors.New("transaction cannot be modified in its current state")
+	ErrInvalidRefundQuantity     = errors.New("refund quantity exceeds remaining quantity")
+	ErrTransactionItemNotFound   = errors.New("transaction item not found")
+	ErrInsufficientStock         = errors.New("insufficient stock")
+	ErrStockOpnameClosed         = errors.New("stock opname session is not open")
+	ErrPurchaseOrderClosed       = errors.New("purchase order is no longer open")
+	ErrInvalidReceiveQuantity    = errors.New("received quantity exceeds outstanding quantity")
+	ErrPurchaseOrderItemNotFound = errors.New("purchase order item not found")
+	ErrCategoryCycle             = errors.New("category cannot be nested under itself")
+	ErrCategoryInUse             = errors.New("category still has subcategories or products")
+	ErrBarcodeTaken              = errors.New("barcode is already assigned to a product")
+	ErrProductHasVariants        = errors.New("product has variants; use a specific variant")
+	ErrNestedVariant             = errors.New("variants cannot have variants of their own")
+	ErrInvalidComponent          = errors.New("product cannot be used as a recipe component")
+	ErrInvalidModifierGroup      = errors.New("modifier group rules are not valid")
+	ErrInvalidModifier           = errors.New("modifier selection is not valid for the product")
+	ErrInvalidPromotion          = errors.New("promotion rules are not valid")
+	ErrInvalidVoucher            = errors.New("voucher is not valid for this sale")
+	ErrVoucherExhausted          = errors.New("voucher has no uses left")
+	ErrCustomerPhoneTaken        = errors.New("phone number is already registered to a customer")
+	ErrInvalidLoyaltyProgram     = errors.New("loyalty program settings are not valid")
+	ErrInvalidPointsPayment      = errors.New("loyalty points cannot pay for this sale")
+	ErrInsufficientPoints        = errors.New("not enough loyalty points")
+	ErrShiftAlreadyOpen          = errors.New("user already has an open shift")
+	ErrShiftClosed               = errors.New("shift is closed")
+	ErrForbidden                 = errors.New("permission denied")
+	ErrInvalidRole               = errors.New("role is not valid")
+	ErrRoleNameTaken             = errors.New("role name is already in use")
+	ErrRoleInUse                 = errors.New("role is still assigned to users")
+	ErrUsernameTaken             = errors.New("username is already in use")
+	ErrInvalidUser               = errors.New("user details are not valid")
+	ErrUserInactive              = errors.New("user account is deactivated")
+	ErrUserSelfChange            = errors.New("users cannot deactivate, delete or change the role of their own account")
+	ErrUserHasHistory            = errors.New("user has recorded activity; deactivate the account instead")
+	ErrInvalidRefreshToken       = errors.New("refresh token is invalid or expired")
+	ErrInvalidPIN                = errors.New("PIN must be 4 to 6 digits")
+	ErrPINLocked                 = errors.New("too many wrong PIN attempts; try again later or sign in with your password")
+	ErrInvalidTerminal           = errors.New("terminal is not registered or has been revoked")
+	ErrLoginThrottled            = errors.New("too many failed login attempts; try again later")
+	ErrAccountLocked             = errors.New("login is temporarily locked after repeated failed attempts")
+	ErrTenantRequired            = errors.New("username exists in more than one tenant; tenant_id is required")
+)
@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+// LoginAttempt counts failed password logins for one username or IP address
+// when login counters are shared through the database
+type LoginAttempt struct {
+	Key           string     `json:"key" gorm:"column:attempt_key;primaryKey;size:320"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt *time.Time `json:"last_failure_at" gorm:"index"`
+	BlockedUntil  *time.Time `json:"blocked_until"`
+	Locked        bool       `json:"locked" gorm:"not null;default:false"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// Authentication audit events
+const (
+	AuthEventLoginSucceeded = "login_succeeded"
+	AuthEventLoginFailed    = "login_failed"
+	AuthEventLoginBlocked   = "login_blocked" // refused during a backoff delay or lockout
+	AuthEventAccountLocked  = "account_locked"
+	AuthEventLoginUnlocked  = "login_unlocked"
+)
+
+// AuthAuditLog records a login attempt or a change to login lockouts.
+// UserID and TenantID are set when the username matched a user.
+type AuthAuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Event     string    `json:"event" gorm:"size:32;not null;index"`
+	Username  string    `json:"username" gorm:"size:255;index"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	IPAddress string    `json:"ip_address" gorm:"size:45"`
+	UserAgent string    `json:"user_agent" gorm:"size:255"`
+	Detail    string    `json:"detail,omitempty" gorm:"size:255"`
+	TenantID  *uint     `json:"tenant_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName sets the table name for GORM
+func (AuthAuditLog) TableName() string {
+	return "auth_audit_logs"
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Loyalty ledger entry types
+const (
+	LoyaltyEntryEarn          = "earn"
+	LoyaltyEntryRedeem        = "redeem"
+	LoyaltyEntryReverseEarn   = "reverse_earn"
+	LoyaltyEntryReverseRedeem = "reverse_redeem"
+)
+
+// LoyaltyProgram is a tenant's points program. A sale earns one point per EarnSpend of its
+// total, multiplied by the customer's tier; a point pays RedeemValue when used as a tender.
+// Points expire ExpiryDays after they were earned, or never when ExpiryDays is 0.
+type LoyaltyProgram struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	Enabled     bool          `json:"enabled" gorm:"not null;default:false"`
+	EarnSpend   money.Money   `json:"earn_spend" gorm:"not null"`
+	RedeemValue money.Money   `json:"redeem_value" gorm:"not null"`
+	ExpiryDays  int           `json:"expiry_days" gorm:"not null;default:0"`
+	Tiers       []LoyaltyTier `json:"tiers,omitempty" gorm:"foreignKey:ProgramID"`
+	TenantID    *uint         `json:"tenant_id" gorm:"uniqueIndex"`
+	Tenant      *Tenant       `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// LoyaltyTier raises the earn rate of customers who earned at least MinPoints in the last year
+type LoyaltyTier struct {
+	ID             uint    `json:"id" gorm:"primaryKey"`
+	ProgramID      uint    `json:"program_id" gorm:"not null;index"`
+	Name           string  `json:"name" gorm:"not null"`
+	MinPoints      int     `json:"min_points" gorm:"not null;default:0"`
+	EarnMultiplier float64 `json:"earn_multiplier" gorm:"not null;default:1"`
+}
+
+// LoyaltyLedgerEntry is one movement of a customer's points. The ledger is append-only:
+// reversals are recorded as new entries and balances are derived from the entries.
+type LoyaltyLedgerEntry struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Phone         string     `json:"phone" gorm:"not null;index:idx_loyalty_ledger_tenant_phone,priority:2"`
+	CustomerID    *uint      `json:"customer_id,omitempty"`
+	TransactionID *uint      `json:"transaction_id,omitempty" gorm:"index"`
+	Type          string     `json:"type" gorm:"not null"`
+	Points        int        `json:"points" gorm:"not null"` // positive credits, negative debits
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`   // set on credits when points expire
+	TenantID      *uint      `json:"tenant_id" gorm:"index:idx_loyalty_ledger_tenant_phone,priority:1"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (LoyaltyProgram) TableName() string {
+	return "loyalty_programs"
+}
+
+// TableName sets the table name for GORM
+func (LoyaltyTier) TableName() string {
+	return "loyalty_tiers"
+}
+
+// TableName sets the table name for GORM
+func (LoyaltyLedgerEntry) TableName() string {
+	return "loyalty_ledger_entries"
+}
+
+// LoyaltyBalance returns the points available at now from ledger entries in the order they
+// were written. Debits use the oldest credits first, and credits left over past their
+// expiry no longer count.
+func LoyaltyBalance(entries []LoyaltyLedgerEntry, now time.Time) int {
+	type lot struct {
+		points    int
+		expiresAt *time.Time
+	}
+	var lots []lot
+	debt := 0 // debits that found no unexpired credits, such as a reversal after the points were spent
+
+	live := func(l lot, at time.Time) bool {
+		return l.points > 0 && (l.expiresAt == nil || at.Before(*l.expiresAt))
+	}
+
+	for _, e := range entries {
+		if e.Points > 0 {
+			points := e.Points
+			settled := min(points, debt)
+			debt -= settled
+			if points -= settled; points > 0 {
+				lots = append(lots, lot{points: points, expiresAt: e.ExpiresAt})
+			}
+			continue
+		}
+
+		owed := -e.Points
+		for i := range lots {
+			if owed == 0 {
+				break
+			}
+			if !live(lots[i], e.CreatedAt) {
+				continue
+			}
+			used := min(owed, lots[i].points)
+			lots[i].points -= used
+			owed -= used
+		}
+		debt += owed
+	}
+
+	balance := -debt
+	for _, l := range lots {
+		if live(l, now) {
+			balance += l.points
+		}
+	}
+	return balance
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// ModifierGroup is a set of add-ons or preferences offered on products, e.g. "Sugar level".
+// A sale must select between MinSelect and MaxSelect options of every group on the product;
+// MaxSelect zero means any number of options may be selected.
+type ModifierGroup struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	Name      string           `json:"name" gorm:"not null"`
+	MinSelect int              `json:"min_select" gorm:"not null;default:0"`
+	MaxSelect int              `json:"max_select" gorm:"not null;default:0"`
+	Options   []ModifierOption `json:"options" gorm:"foreignKey:GroupID"`
+	TenantID  *uint            `json:"tenant_id" gorm:"index"`
+	Tenant    *Tenant          `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ModifierOption is one selectable choice of a modifier group. Price is added to the unit price.
+type ModifierOption struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	GroupID   uint        `json:"group_id" gorm:"not null;index"`
+	Name      string      `json:"name" gorm:"not null"`
+	Price     money.Money `json:"price" gorm:"not null;default:0"`
+	Position  int         `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TransactionItemModifier is a snapshot of a modifier selected on a transaction item,
+// so receipts and reports keep the name and price charged even if the option changes later
+type TransactionItemModifier struct {
+	ID                uint        `json:"id" gorm:"primaryKey"`
+	TransactionItemID uint        `json:"transaction_item_id" gorm:"not null;index"`
+	ModifierOptionID  uint        `json:"modifier_option_id" gorm:"not null;index"`
+	GroupName         string      `json:"group_name" gorm:"not null"`
+	Name              string      `json:"name" gorm:"not null"`
+	Price             money.Money `json:"price" gorm:"not null;default:0"`
+}
+
+// TableName sets the table name for GORM
+func (ModifierGroup) TableName() string {
+	return "modifier_groups"
+}
+
+// TableName sets the table name for GORM
+func (ModifierOption) TableName() string {
+	return "modifier_options"
+}
+
+// TableName sets the table name for GORM
+func (TransactionItemModifier) TableName() string {
+	return "transaction_item_modifiers"
+}
@@ -2,24 +2,84 @@ package entities
 
 import (
 	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 )
 
-// Product represents a product in the system
+// Product represents a product in the system.
+// A product with options is a parent whose Variants are products of their own,
+// each with its own SKU, prices, stock and image; ParentID is set on variants.
 type Product struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	Image      string    `json:"image"`
-	Name       string    `json:"name" gorm:"not null"`
-	SKU        string    `json:"sku" gorm:"uniqueIndex;not null"`
-	HargaModal float64   `json:"harga_modal" gorm:"not null"`
-	HargaJual  float64   `json:"harga_jual" gorm:"not null"`
-	Stock      int       `json:"stock" gorm:"not null;default:0"`
-	TenantID   *uint     `json:"tenant_id" gorm:"index"`
-	Tenant     *Tenant   `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID             uint               `json:"id" gorm:"primaryKey"`
+	Image          string             `json:"image"`
+	Name           string             `json:"name" gorm:"not null"`
+	SKU            string             `json:"sku" gorm:"uniqueIndex;not null"`
+	HargaModal     money.Money        `json:"harga_modal" gorm:"not null"`
+	HargaJual      money.Money        `json:"harga_jual" gorm:"not null"`
+	Stock          int                `json:"stock" gorm:"not null;default:0"`
+	ReorderPoint   int                `json:"reorder_point" gorm:"not null;default:0"` // zero disables low-stock alerts
+	ReorderQty     int                `json:"reorder_qty" gorm:"not null;default:0"`
+	CategoryID     *uint              `json:"category_id" gorm:"index"`
+	Category       *Category          `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Tags           []Tag              `json:"tags" gorm:"many2many:product_tags"`
+	Barcodes       []ProductBarcode   `json:"barcodes" gorm:"foreignKey:ProductID"`
+	ParentID       *uint              `json:"parent_id" gorm:"index"`
+	VariantName    string             `json:"variant_name,omitempty"` // option values joined with " / ", e.g. "M / Red"
+	Options        []ProductOption    `json:"options,omitempty" gorm:"foreignKey:ProductID"`
+	Variants       []Product          `json:"variants,omitempty" gorm:"foreignKey:ParentID"`
+	Components     []ProductComponent `json:"components,omitempty" gorm:"foreignKey:ProductID"`
+	TaxExempt      bool               `json:"tax_exempt" gorm:"not null;default:false"`
+	DeductOwnStock bool               `json:"deduct_own_stock" gorm:"not null;default:false"`                     // composite products also deduct their own stock
+	ModifierGroups []ModifierGroup    `json:"modifier_groups,omitempty" gorm:"many2many:product_modifier_groups"` // variants also offer their parent's groups
+	TenantID       *uint              `json:"tenant_id" gorm:"index"`
+	Tenant         *Tenant            `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
 }
 
 // TableName sets the table name for GORM
 func (Product) TableName() string {
 	return "products"
 }
+
+// HasVariants reports whether the product is a parent that is sold through its variants
+func (p *Product) HasVariants() bool {
+	return len(p.Variants) > 0
+}
+
+// TotalStock returns the stock rolled up over all variants, or the product's own stock
+func (p *Product) TotalStock() int {
+	if !p.HasVariants() {
+		return p.Stock
+	}
+	total := 0
+	for _, v := range p.Variants {
+		total += v.Stock
+	}
+	return total
+}
+
+// IsComposite reports whether selling the product consumes component stock
+func (p *Product) IsComposite() bool {
+	return len(p.Components) > 0
+}
+
+// UnitCost returns the cost of one unit: the summed component HargaModal for composite
+// products, otherwise HargaModal. Components must be loaded with their Component.
+func (p *Product) UnitCost() money.Money {
+	if !p.IsComposite() {
+		return p.HargaModal
+	}
+	var cost money.Money
+	for _, c := range p.Components {
+		if c.Component != nil {
+			cost += c.Component.HargaModal.Mul(c.Quantity)
+		}
+	}
+	return cost
+}
+
+// IsLowStock reports whether stock has fallen to the reorder point
+func (p *Product) IsLowStock() bool {
+	return p.ReorderPoint > 0 && p.Stock <= p.ReorderPoint
+}
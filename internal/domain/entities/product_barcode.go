@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ProductBarcode is a scannable code for a product, unique within a tenant.
+// Pack-size barcodes carry a Multiplier so one scan sells several units.
+type ProductBarcode struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProductID  uint      `json:"product_id" gorm:"not null;index"`
+	Code       string    `json:"code" gorm:"not null;uniqueIndex:idx_product_barcodes_tenant_code"`
+	Multiplier int       `json:"multiplier" gorm:"not null;default:1"`
+	TenantID   *uint     `json:"tenant_id" gorm:"uniqueIndex:idx_product_barcodes_tenant_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (ProductBarcode) TableName() string {
+	return "product_barcodes"
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ProductComponent is one line of a composite product's recipe (bill of materials).
+// Quantity is how many units of the component one unit of the product consumes.
+type ProductComponent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_product_components_product_component"`
+	ComponentID uint      `json:"component_id" gorm:"not null;uniqueIndex:idx_product_components_product_component"`
+	Component   *Product  `json:"component,omitempty" gorm:"foreignKey:ComponentID"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (ProductComponent) TableName() string {
+	return "product_components"
+}
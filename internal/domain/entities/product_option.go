@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// ProductOption is a variant dimension of a parent product, such as size or colour
+type ProductOption struct {
+	ID        uint                 `json:"id" gorm:"primaryKey"`
+	ProductID uint                 `json:"product_id" gorm:"not null;index"`
+	Name      string               `json:"name" gorm:"not null"`
+	Position  int                  `json:"position" gorm:"not null;default:0"`
+	Values    []ProductOptionValue `json:"values" gorm:"foreignKey:OptionID"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (ProductOption) TableName() string {
+	return "product_options"
+}
+
+// ProductOptionValue is one choice of a product option, such as "M" for size
+type ProductOptionValue struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	OptionID uint   `json:"option_id" gorm:"not null;index"`
+	Value    string `json:"value" gorm:"not null"`
+	Position int    `json:"position" gorm:"not null;default:0"`
+}
+
+// TableName sets the table name for GORM
+func (ProductOptionValue) TableName() string {
+	return "product_option_values"
+}
@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Promotion types
+const (
+	PromotionTypeBuyXGetY     = "buy_x_get_y"   // every BuyQty units, GetQty more are free or DiscountPercent off
+	PromotionTypeBundle       = "bundle"        // BuyQty units of the products together cost BundlePrice
+	PromotionTypeItemDiscount = "item_discount" // DiscountAmount or DiscountPercent off every unit
+	PromotionTypeMinSpend     = "min_spend"     // DiscountAmount or DiscountPercent off baskets of at least MinSpend
+)
+
+// Promotion is a tenant's discount rule, evaluated server-side when a sale is created.
+// Products limits the rule to those products and their variants; an empty list matches every product.
+// Promotions with a Code are vouchers and only apply when the code is entered at checkout;
+// VoucherOnly promotions only apply when one of their generated vouchers is redeemed.
+// StartsAt/EndsAt bound the campaign; DailyStart/DailyEnd ("15:04") and Days (weekday numbers,
+// 0 is Sunday, e.g. "1,2,3,4,5") restrict it to recurring windows such as happy hours.
+type Promotion struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	Name            string      `json:"name" gorm:"not null"`
+	Type            string      `json:"type" gorm:"not null"`
+	Code            string      `json:"code,omitempty" gorm:"index"`
+	Active          bool        `json:"active" gorm:"not null"`
+	VoucherOnly     bool        `json:"voucher_only" gorm:"not null;default:false"`
+	Products        []Product   `json:"products,omitempty" gorm:"many2many:promotion_products"`
+	BuyQty          int         `json:"buy_qty" gorm:"not null;default:0"`
+	GetQty          int         `json:"get_qty" gorm:"not null;default:0"`
+	BundlePrice     money.Money `json:"bundle_price" gorm:"not null;default:0"`
+	DiscountAmount  money.Money `json:"discount_amount" gorm:"not null;default:0"`
+	DiscountPercent float64     `json:"discount_percent" gorm:"not null;default:0"`
+	MinSpend        money.Money `json:"min_spend" gorm:"not null;default:0"`
+	StartsAt        *time.Time  `json:"starts_at"`
+	EndsAt          *time.Time  `json:"ends_at"`
+	DailyStart      string      `json:"daily_start,omitempty"`
+	DailyEnd        string      `json:"daily_end,omitempty"`
+	Days            string      `json:"days,omitempty"`
+	TenantID        *uint       `json:"tenant_id" gorm:"index"`
+	Tenant          *Tenant     `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// TransactionItemPromotion records the discount a promotion gave on a transaction item,
+// so reports can measure each campaign
+type TransactionItemPromotion struct {
+	ID                uint        `json:"id" gorm:"primaryKey"`
+	TransactionItemID uint        `json:"transaction_item_id" gorm:"not null;index"`
+	PromotionID       uint        `json:"promotion_id" gorm:"not null;index"`
+	Name              string      `json:"name" gorm:"not null"`
+	Amount            money.Money `json:"amount" gorm:"not null"` // discount on the whole line
+}
+
+// RequiresVoucher reports whether the promotion only applies when a voucher is entered
+func (p *Promotion) RequiresVoucher() bool {
+	return p.Code != "" || p.VoucherOnly
+}
+
+// TableName sets the table name for GORM
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+// TableName sets the table name for GORM
+func (TransactionItemPromotion) TableName() string {
+	return "transaction_item_promotions"
+}
+
+// ActiveAt reports whether the promotion runs at t
+func (p *Promotion) ActiveAt(t time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	if p.Days != "" && !strings.Contains(","+p.Days+",", ","+strconv.Itoa(int(t.Weekday()))+",") {
+		return false
+	}
+	if p.DailyStart != "" && p.DailyEnd != "" {
+		now := t.Format("15:04")
+		if p.DailyStart <= p.DailyEnd {
+			return now >= p.DailyStart && now < p.DailyEnd
+		}
+		// Windows such as 22:00-02:00 run past midnight
+		return now >= p.DailyStart || now < p.DailyEnd
+	}
+	return true
+}
+
+// AppliesTo reports whether the promotion covers the product; variants are covered through their parent
+func (p *Promotion) AppliesTo(product *Product) bool {
+	if len(p.Products) == 0 {
+		return true
+	}
+	for _, pp := range p.Products {
+		if pp.ID == product.ID || (product.ParentID != nil && pp.ID == *product.ParentID) {
+			return true
+		}
+	}
+	return false
+}
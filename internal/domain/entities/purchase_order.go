@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Purchase order statuses
+const (
+	PurchaseOrderStatusOpen              = "open"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// PurchaseOrder represents stock ordered from a supplier
+type PurchaseOrder struct {
+	ID         uint                `json:"id" gorm:"primaryKey"`
+	SupplierID uint                `json:"supplier_id" gorm:"not null;index"`
+	Supplier   Supplier            `json:"supplier" gorm:"foreignKey:SupplierID"`
+	Status     string              `json:"status" gorm:"not null;default:'open'"`
+	ExpectedAt *time.Time          `json:"expected_at"`
+	Notes      string              `json:"notes"`
+	TotalCost  money.Money         `json:"total_cost" gorm:"not null"`
+	CreatedBy  uint                `json:"created_by" gorm:"not null"`
+	Items      []PurchaseOrderItem `json:"items" gorm:"foreignKey:PurchaseOrderID"`
+	Receipts   []GoodsReceipt      `json:"receipts" gorm:"foreignKey:PurchaseOrderID"`
+	TenantID   *uint               `json:"tenant_id" gorm:"index"`
+	Tenant     *Tenant             `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+// PurchaseOrderItem represents an ordered product and how much of it has arrived
+type PurchaseOrderItem struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	PurchaseOrderID uint        `json:"purchase_order_id" gorm:"not null;index"`
+	ProductID       uint        `json:"product_id" gorm:"not null"`
+	Product         Product     `json:"product" gorm:"foreignKey:ProductID"`
+	Quantity        int         `json:"quantity" gorm:"not null"`
+	ReceivedQty     int         `json:"received_qty" gorm:"not null;default:0"`
+	UnitCost        money.Money `json:"unit_cost" gorm:"not null"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (PurchaseOrderItem) TableName() string {
+	return "purchase_order_items"
+}
+
+// OutstandingQty returns the quantity still to be received
+func (i *PurchaseOrderItem) OutstandingQty() int {
+	if i.ReceivedQty >= i.Quantity {
+		return 0
+	}
+	return i.Quantity - i.ReceivedQty
+}
+
+// GoodsReceipt records one delivery against a purchase order, keeping the cost paid per unit
+type GoodsReceipt struct {
+	ID              uint               `json:"id" gorm:"primaryKey"`
+	PurchaseOrderID uint               `json:"purchase_order_id" gorm:"not null;index"`
+	Notes           string             `json:"notes"`
+	UserID          uint               `json:"user_id" gorm:"not null"`
+	Items           []GoodsReceiptItem `json:"items" gorm:"foreignKey:ReceiptID"`
+	TenantID        *uint              `json:"tenant_id" gorm:"index"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (GoodsReceipt) TableName() string {
+	return "goods_receipts"
+}
+
+// GoodsReceiptItem represents a received quantity of a purchase order item
+type GoodsReceiptItem struct {
+	ID                  uint        `json:"id" gorm:"primaryKey"`
+	ReceiptID           uint        `json:"receipt_id" gorm:"not null;index"`
+	PurchaseOrderItemID uint        `json:"purchase_order_item_id" gorm:"not null"`
+	ProductID           uint        `json:"product_id" gorm:"not null"`
+	Quantity            int         `json:"quantity" gorm:"not null"`
+	UnitCost            money.Money `json:"unit_cost" gorm:"not null"`
+	PreviousHargaModal  money.Money `json:"previous_harga_modal" gorm:"not null"`
+	NewHargaModal       money.Money `json:"new_harga_modal" gorm:"not null"`
+}
+
+// TableName sets the table name for GORM
+func (GoodsReceiptItem) TableName() string {
+	return "goods_receipt_items"
+}
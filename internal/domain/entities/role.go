@@ -0,0 +1,134 @@
+package entities
+
+import "time"
+
+// Permissions checked by the API routes and services
+const (
+	PermProductsManage   = "products.manage"    // products, prices, categories and modifier groups
+	PermProductsViewCost = "products.view_cost" // harga_modal and other cost figures
+	PermStockManage      = "stock.manage"       // stock adjustments and stock opname
+	PermPurchasingManage = "purchasing.manage"  // suppliers and purchase orders
+	PermSalesCreate      = "sales.create"       // checkout and open orders
+	PermSalesView        = "sales.view"         // transaction history
+	PermSalesVoid        = "sales.void"         // voids and refunds
+	PermReportsView      = "reports.view"
+	PermPromotionsManage = "promotions.manage"
+	PermCustomersManage  = "customers.manage" // deleting customers
+	PermLoyaltyManage    = "loyalty.manage"
+	PermShiftsManage     = "shifts.manage" // other cashiers' shifts
+	PermRolesManage      = "roles.manage"
+	PermUsersManage      = "users.manage"
+	PermTerminalsManage  = "terminals.manage" // registering and revoking PIN login terminals
+)
+
+// AllPermissions lists every permission a role can be granted
+var AllPermissions = []string{
+	PermProductsManage,
+	PermProductsViewCost,
+	PermStockManage,
+	PermPurchasingManage,
+	PermSalesCreate,
+	PermSalesView,
+	PermSalesVoid,
+	PermReportsView,
+	PermPromotionsManage,
+	PermCustomersManage,
+	PermLoyaltyManage,
+	PermShiftsManage,
+	PermRolesManage,
+	PermUsersManage,
+	PermTerminalsManage,
+}
+
+// Built-in roles available to every tenant
+const (
+	RoleOwner      = "owner"
+	RoleManager    = "manager"
+	RoleCashier    = "cashier"
+	RoleStockClerk = "stock_clerk"
+)
+
+// BuiltInRoles maps the built-in roles to their permissions
+var BuiltInRoles = map[string][]string{
+	RoleOwner: AllPermissions,
+	RoleManager: {
+		PermProductsManage,
+		PermProductsViewCost,
+		PermStockManage,
+		PermPurchasingManage,
+		PermSalesCreate,
+		PermSalesView,
+		PermSalesVoid,
+		PermReportsView,
+		PermPromotionsManage,
+		PermCustomersManage,
+		PermLoyaltyManage,
+		PermShiftsManage,
+		PermTerminalsManage,
+	},
+	RoleCashier: {
+		PermSalesCreate,
+		PermSalesView,
+	},
+	RoleStockClerk: {
+		PermProductsViewCost,
+		PermStockManage,
+		PermPurchasingManage,
+	},
+}
+
+// PermissionSet is the set of permissions granted to the caller's role
+type PermissionSet map[string]bool
+
+// NewPermissionSet builds a permission set from a list of permissions
+func NewPermissionSet(permissions []string) PermissionSet {
+	set := make(PermissionSet, len(permissions))
+	for _, p := range permissions {
+		set[p] = true
+	}
+	return set
+}
+
+// Has reports whether the set grants permission
+func (s PermissionSet) Has(permission string) bool {
+	return s[permission]
+}
+
+// Role is a custom role defined by a tenant in addition to the built-in roles.
+// Users refer to roles by name.
+type Role struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	Name        string           `json:"name" gorm:"not null;uniqueIndex:idx_roles_tenant_name"`
+	Description string           `json:"description"`
+	Permissions []RolePermission `json:"permissions" gorm:"foreignKey:RoleID"`
+	TenantID    *uint            `json:"tenant_id" gorm:"uniqueIndex:idx_roles_tenant_name"`
+	Tenant      *Tenant          `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// RolePermission grants one permission to a custom role
+type RolePermission struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	RoleID     uint   `json:"role_id" gorm:"not null;uniqueIndex:idx_role_permissions_role_permission"`
+	Permission string `json:"permission" gorm:"not null;uniqueIndex:idx_role_permissions_role_permission"`
+}
+
+// PermissionNames returns the permissions granted to the role
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		names[i] = p.Permission
+	}
+	return names
+}
+
+// TableName sets the table name for GORM
+func (Role) TableName() string {
+	return "roles"
+}
+
+// TableName sets the table name for GORM
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+// Session is a login on one device. It stores a hash of the current refresh token,
+// which is replaced each time the session is refreshed, and the ID of the latest
+// access token issued for it so that revoking the session also revokes that token.
+// Sessions started by PIN on a registered terminal are tied to that terminal.
+type Session struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	UserID            uint       `json:"user_id" gorm:"not null;index"`
+	User              *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	RefreshTokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	PreviousTokenHash string     `json:"-" gorm:"size:64;index"` // detects reuse of a rotated refresh token
+	AccessTokenID     string     `json:"-" gorm:"size:64;not null"`
+	AccessExpiresAt   time.Time  `json:"-" gorm:"not null"`
+	TerminalID        *uint      `json:"terminal_id" gorm:"index"`
+	Terminal          *Terminal  `json:"terminal,omitempty" gorm:"foreignKey:TerminalID"`
+	UserAgent         string     `json:"user_agent" gorm:"size:255"`
+	IPAddress         string     `json:"ip_address" gorm:"size:45"`
+	ExpiresAt         time.Time  `json:"expires_at" gorm:"not null"`
+	LastUsedAt        time.Time  `json:"last_used_at" gorm:"not null"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	TenantID          *uint      `json:"tenant_id" gorm:"index"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the session can still be refreshed at now
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RevokedToken denylists an access token by its jti until the token would have expired
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey;size:64"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// TableName sets the table name for GORM
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
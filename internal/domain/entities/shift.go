@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Shift statuses
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+)
+
+// Cash drawer event types
+const (
+	ShiftCashIn  = "cash_in"
+	ShiftCashOut = "cash_out"
+)
+
+// Shift is a cashier's session at the till, from the starting float to the closing count.
+// Sales and refunds the cashier makes while it is open are linked to it.
+// ExpectedCash, CountedCash and CashDifference are snapshotted when the shift is closed.
+type Shift struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	UserID         uint             `json:"user_id" gorm:"not null;index"`
+	User           *User            `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Status         string           `json:"status" gorm:"not null;default:'open'"`
+	OpeningFloat   money.Money      `json:"opening_float" gorm:"not null;default:0"`
+	ExpectedCash   money.Money      `json:"expected_cash" gorm:"not null;default:0"`
+	CountedCash    money.Money      `json:"counted_cash" gorm:"not null;default:0"`
+	CashDifference money.Money      `json:"cash_difference" gorm:"not null;default:0"` // counted minus expected
+	Notes          string           `json:"notes,omitempty" gorm:"type:text"`
+	ClosedBy       *uint            `json:"closed_by"`
+	ClosedAt       *time.Time       `json:"closed_at"`
+	CashEvents     []ShiftCashEvent `json:"cash_events" gorm:"foreignKey:ShiftID"`
+	Counts         []ShiftCount     `json:"counts" gorm:"foreignKey:ShiftID"`
+	TenantID       *uint            `json:"tenant_id" gorm:"index"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// ShiftCashEvent records cash put into or taken out of the drawer outside of sales,
+// such as change top-ups, petty cash or safe drops
+type ShiftCashEvent struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	ShiftID   uint        `json:"shift_id" gorm:"not null;index"`
+	Type      string      `json:"type" gorm:"not null"`
+	Amount    money.Money `json:"amount" gorm:"not null"`
+	Reason    string      `json:"reason" gorm:"type:text;not null"`
+	UserID    uint        `json:"user_id" gorm:"not null"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ShiftCount is the amount counted for one tender when the shift was closed
+type ShiftCount struct {
+	ID      uint        `json:"id" gorm:"primaryKey"`
+	ShiftID uint        `json:"shift_id" gorm:"not null;uniqueIndex:idx_shift_counts_shift_method"`
+	Method  string      `json:"method" gorm:"not null;uniqueIndex:idx_shift_counts_shift_method"`
+	Amount  money.Money `json:"amount" gorm:"not null"`
+}
+
+// TableName sets the table name for GORM
+func (Shift) TableName() string {
+	return "shifts"
+}
+
+// TableName sets the table name for GORM
+func (ShiftCashEvent) TableName() string {
+	return "shift_cash_events"
+}
+
+// TableName sets the table name for GORM
+func (ShiftCount) TableName() string {
+	return "shift_counts"
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// Stock movement reasons
+const (
+	StockReasonSale        = "sale"
+	StockReasonRefund      = "refund"
+	StockReasonAdjustment  = "adjustment"
+	StockReasonReceiving   = "receiving"
+	StockReasonTransfer    = "transfer"
+	StockReasonStockOpname = "stock_opname"
+)
+
+// StockMovement is an append-only ledger entry for every change to a product's stock
+type StockMovement struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	Delta       int       `json:"delta" gorm:"not null"`
+	Balance     int       `json:"balance" gorm:"not null"`
+	Reason      string    `json:"reason" gorm:"not null"`
+	ReferenceID *uint     `json:"reference_id"`
+	UserID      *uint     `json:"user_id"`
+	TenantID    *uint     `json:"tenant_id" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
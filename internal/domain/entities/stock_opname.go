@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Stock opname statuses
+const (
+	StockOpnameStatusOpen      = "open"
+	StockOpnameStatusCommitted = "committed"
+	StockOpnameStatusCancelled = "cancelled"
+)
+
+// StockOpname represents a physical stock count session
+type StockOpname struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Status      string            `json:"status" gorm:"not null;default:'open'"`
+	Notes       string            `json:"notes,omitempty" gorm:"type:text"`
+	OpenedBy    uint              `json:"opened_by" gorm:"not null"`
+	CommittedBy *uint             `json:"committed_by"`
+	CommittedAt *time.Time        `json:"committed_at"`
+	Items       []StockOpnameItem `json:"items" gorm:"foreignKey:OpnameID"`
+	TenantID    *uint             `json:"tenant_id" gorm:"index"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// StockOpnameItem holds the counted quantity of one product in a session.
+// SystemQty, Variance and VarianceValue are snapshotted when the session is committed.
+type StockOpnameItem struct {
+	ID            uint        `json:"id" gorm:"primaryKey"`
+	OpnameID      uint        `json:"opname_id" gorm:"not null;uniqueIndex:idx_stock_opname_items_opname_product"`
+	ProductID     uint        `json:"product_id" gorm:"not null;uniqueIndex:idx_stock_opname_items_opname_product"`
+	Product       Product     `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	CountedQty    int         `json:"counted_qty" gorm:"not null;default:0"`
+	SystemQty     int         `json:"system_qty" gorm:"not null;default:0"`
+	Variance      int         `json:"variance" gorm:"not null;default:0"`
+	VarianceValue money.Money `json:"variance_value" gorm:"not null;default:0"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (StockOpname) TableName() string {
+	return "stock_opnames"
+}
+
+// TableName sets the table name for GORM
+func (StockOpnameItem) TableName() string {
+	return "stock_opname_items"
+}
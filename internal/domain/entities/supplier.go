@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// Supplier represents a vendor that products are purchased from
+type Supplier struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null"`
+	ContactName string    `json:"contact_name"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	Address     string    `json:"address"`
+	Notes       string    `json:"notes"`
+	TenantID    *uint     `json:"tenant_id" gorm:"index"`
+	Tenant      *Tenant   `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (Supplier) TableName() string {
+	return "suppliers"
+}
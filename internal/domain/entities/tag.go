@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Tag is a free-form product label, unique by name within a tenant
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null;uniqueIndex:idx_tags_tenant_name"`
+	TenantID  *uint     `json:"tenant_id" gorm:"uniqueIndex:idx_tags_tenant_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (Tag) TableName() string {
+	return "tags"
+}
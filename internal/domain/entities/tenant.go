@@ -1,17 +1,26 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
 
 // Tenant represents a tenant in the system
 type Tenant struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	About       string    `json:"about"`
-	Address     string    `json:"address"`
-	PhoneNumber string    `json:"phone_number"`
-	Logo        string    `json:"logo"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uint               `json:"id"`
+	Name              string             `json:"name"`
+	About             string             `json:"about"`
+	Address           string             `json:"address"`
+	PhoneNumber       string             `json:"phone_number"`
+	Logo              string             `json:"logo"`
+	RoundingIncrement money.Money        `json:"rounding_increment"` // zero disables total rounding
+	RoundingMode      money.RoundingMode `json:"rounding_mode"`
+	TaxRate           float64            `json:"tax_rate"`            // PPN percentage, zero disables tax
+	TaxInclusive      bool               `json:"tax_inclusive"`       // prices already include PPN
+	ServiceChargeRate float64            `json:"service_charge_rate"` // percentage of the net sale, zero disables it
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
 }
 
 // TenantRepository defines the interface for tenant data operations
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Terminal is a shared till registered to a tenant. It authenticates with a device
+// token, stored hashed, and lets the tenant's users sign in on it with their PIN.
+type Terminal struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"not null"`
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	TenantID   *uint      `json:"tenant_id" gorm:"not null;index"`
+	Tenant     *Tenant    `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (Terminal) TableName() string {
+	return "terminals"
+}
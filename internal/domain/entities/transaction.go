@@ -2,33 +2,157 @@ package entities
 
 import (
 	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Transaction statuses
+const (
+	TransactionStatusCompleted         = "completed"
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+	TransactionStatusRefunded          = "refunded"
+	TransactionStatusVoided            = "voided"
+
+	// Open orders are unpaid transactions whose lines can still change; no stock moves until they are settled
+	TransactionStatusOpen      = "open"
+	TransactionStatusHeld      = "held" // parked; resume it before changing or settling
+	TransactionStatusCancelled = "cancelled"
+)
+
+// OrderStatuses are the statuses of transactions that were never sold
+var OrderStatuses = []string{TransactionStatusOpen, TransactionStatusHeld, TransactionStatusCancelled}
+
+// Payment methods with special handling. Any other method string is accepted as a non-cash tender.
+const (
+	PaymentMethodCash   = "cash"
+	PaymentMethodSplit  = "split"
+	PaymentMethodPoints = "points" // paid with loyalty points; needs the customer phone
+)
+
+// Transaction reversal types
+const (
+	RefundTypeVoid   = "void"
+	RefundTypeRefund = "refund"
 )
 
 // Transaction represents a sales transaction
 type Transaction struct {
-	ID            uint              `json:"id" gorm:"primaryKey"`
-	Items         []TransactionItem `json:"items" gorm:"foreignKey:TransactionID"`
-	User          string            `json:"user" gorm:"not null"`
-	PaymentMethod string            `json:"payment_method" gorm:"not null"`
-	Discount      float64           `json:"discount" gorm:"default:0"`
-	TotalPrice    float64           `json:"total_price" gorm:"not null"`
-	TenantID      *uint             `json:"tenant_id" gorm:"index"`
-	Tenant        *Tenant           `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
-	Notes         string            `json:"notes,omitempty" gorm:"type:text"`
+	ID                uint                 `json:"id" gorm:"primaryKey"`
+	Items             []TransactionItem    `json:"items" gorm:"foreignKey:TransactionID"`
+	Refunds           []TransactionRefund  `json:"refunds,omitempty" gorm:"foreignKey:TransactionID"`
+	Payments          []TransactionPayment `json:"payments" gorm:"foreignKey:TransactionID"`
+	User              string               `json:"user" gorm:"not null"`
+	PaymentMethod     string               `json:"payment_method" gorm:"not null"`
+	Discount          float64              `json:"discount" gorm:"default:0"`          // percentage of the subtotal
+	Subtotal          money.Money          `json:"subtotal" gorm:"not null;default:0"` // sum of item prices before discount, service and tax
+	PromotionDiscount money.Money          `json:"promotion_discount" gorm:"not null;default:0"`
+	VoucherCode       string               `json:"voucher_code,omitempty"`
+	VoucherNotApplied string               `json:"voucher_not_applied,omitempty" gorm:"-"` // entered voucher that lost to a bigger promotion; set on checkout only
+	CustomerID        *uint                `json:"customer_id,omitempty" gorm:"index"`
+	Customer          *Customer            `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
+	CustomerPhone     string               `json:"customer_phone,omitempty" gorm:"index"`
+	PointsEarned      int                  `json:"points_earned" gorm:"not null;default:0"`
+	PointsRedeemed    int                  `json:"points_redeemed" gorm:"not null;default:0"`
+	ServiceCharge     money.Money          `json:"service_charge" gorm:"not null;default:0"`
+	TaxBase           money.Money          `json:"tax_base" gorm:"not null;default:0"` // DPP: net taxable sales plus their service charge
+	Tax               money.Money          `json:"tax" gorm:"not null;default:0"`
+	TaxRate           float64              `json:"tax_rate" gorm:"not null;default:0"` // tenant tax settings at the time of sale
+	TaxInclusive      bool                 `json:"tax_inclusive" gorm:"not null;default:false"`
+	ServiceChargeRate float64              `json:"service_charge_rate" gorm:"not null;default:0"`
+	TotalPrice        money.Money          `json:"total_price" gorm:"not null"`
+	ChangeDue         money.Money          `json:"change_due" gorm:"not null;default:0"`
+	Status            string               `json:"status" gorm:"not null;default:'completed';index"`
+	Label             string               `json:"label,omitempty"`                 // table number or name of an open order
+	ShiftID           *uint                `json:"shift_id,omitempty" gorm:"index"` // cashier shift the sale was paid in
+	TenantID          *uint                `json:"tenant_id" gorm:"index"`
+	Tenant            *Tenant              `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+	Notes             string               `json:"notes,omitempty" gorm:"type:text"`
 }
 
 // TransactionItem represents an item in a transaction
 type TransactionItem struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	TransactionID uint      `json:"transaction_id" gorm:"not null"`
-	ProductID     uint      `json:"product_id" gorm:"not null"`
-	Product       Product   `json:"product,omitempty" gorm:"foreignKey:ProductID"`
-	Quantity      int       `json:"quantity" gorm:"not null"`
-	Price         float64   `json:"price" gorm:"not null"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uint                       `json:"id" gorm:"primaryKey"`
+	TransactionID uint                       `json:"transaction_id" gorm:"not null"`
+	ProductID     uint                       `json:"product_id" gorm:"not null"`
+	Product       Product                    `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Quantity      int                        `json:"quantity" gorm:"not null"`
+	Price         money.Money                `json:"price" gorm:"not null"`          // unit price including selected modifiers
+	Cost          money.Money                `json:"cost" gorm:"not null;default:0"` // unit cost at the time of sale
+	TaxExempt     bool                       `json:"tax_exempt" gorm:"not null;default:false"`
+	Discount      money.Money                `json:"discount" gorm:"not null;default:0"` // promotion discount on the whole line
+	Promotions    []TransactionItemPromotion `json:"promotions,omitempty" gorm:"foreignKey:TransactionItemID"`
+	Deductions    []TransactionItemDeduction `json:"deductions,omitempty" gorm:"foreignKey:TransactionItemID"` // set for composite items
+	Modifiers     []TransactionItemModifier  `json:"modifiers,omitempty" gorm:"foreignKey:TransactionItemID"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+}
+
+// TransactionItemDeduction is a snapshot of the stock a composite item consumed per unit sold,
+// so voids and refunds restore exactly what was taken even if the recipe changes later
+type TransactionItemDeduction struct {
+	ID                uint `json:"id" gorm:"primaryKey"`
+	TransactionItemID uint `json:"transaction_item_id" gorm:"not null;index"`
+	ProductID         uint `json:"product_id" gorm:"not null"`
+	Quantity          int  `json:"quantity" gorm:"not null"`
+}
+
+// TransactionPayment represents a single tender used to pay for a transaction.
+// Amount is what the customer handed over; ChangeAmount is the part of it given back.
+type TransactionPayment struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	TransactionID   uint        `json:"transaction_id" gorm:"not null;index"`
+	Method          string      `json:"method" gorm:"not null"`
+	Amount          money.Money `json:"amount" gorm:"not null"`
+	ChangeAmount    money.Money `json:"change_amount" gorm:"not null;default:0"`
+	ReferenceNumber string      `json:"reference_number,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// TransactionRefund records a void or refund against a transaction.
+// The original transaction and its items are never modified; only its status changes.
+type TransactionRefund struct {
+	ID            uint                    `json:"id" gorm:"primaryKey"`
+	TransactionID uint                    `json:"transaction_id" gorm:"not null;index"`
+	Type          string                  `json:"type" gorm:"not null"`
+	Reason        string                  `json:"reason" gorm:"type:text;not null"`
+	UserID        uint                    `json:"user_id" gorm:"not null"`
+	ShiftID       *uint                   `json:"shift_id,omitempty" gorm:"index"` // cashier shift the money was paid back in
+	Amount        money.Money             `json:"amount" gorm:"not null"`
+	Items         []TransactionRefundItem `json:"items" gorm:"foreignKey:RefundID"`
+	TenantID      *uint                   `json:"tenant_id" gorm:"index"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// TransactionRefundItem represents a returned quantity of a transaction item
+type TransactionRefundItem struct {
+	ID                uint        `json:"id" gorm:"primaryKey"`
+	RefundID          uint        `json:"refund_id" gorm:"not null;index"`
+	TransactionItemID uint        `json:"transaction_item_id" gorm:"not null;index"`
+	ProductID         uint        `json:"product_id" gorm:"not null"`
+	Quantity          int         `json:"quantity" gorm:"not null"`
+	Amount            money.Money `json:"amount" gorm:"not null"` // includes the returned share of service charge and tax
+	ServiceCharge     money.Money `json:"service_charge" gorm:"not null;default:0"`
+	TaxBase           money.Money `json:"tax_base" gorm:"not null;default:0"`
+	Tax               money.Money `json:"tax" gorm:"not null;default:0"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// RefundedQuantity returns how many units of the given transaction item have been returned
+func (t *Transaction) RefundedQuantity(itemID uint) int {
+	var qty int
+	for _, refund := range t.Refunds {
+		for _, item := range refund.Items {
+			if item.TransactionItemID == itemID {
+				qty += item.Quantity
+			}
+		}
+	}
+	return qty
 }
 
 // TableName sets the table name for GORM
@@ -41,4 +165,24 @@ func (TransactionItem) TableName() string {
 	return "transaction_items"
 }
 
+// TableName sets the table name for GORM
+func (TransactionItemDeduction) TableName() string {
+	return "transaction_item_deductions"
+}
+
+// TableName sets the table name for GORM
+func (TransactionPayment) TableName() string {
+	return "transaction_payments"
+}
+
+// TableName sets the table name for GORM
+func (TransactionRefund) TableName() string {
+	return "transaction_refunds"
+}
+
+// TableName sets the table name for GORM
+func (TransactionRefundItem) TableName() string {
+	return "transaction_refund_items"
+}
+
 // Add a migration to add the 'notes' column to the transactions table if not present.
@@ -4,16 +4,27 @@ import (
 	"time"
 )
 
-// User represents a user in the system
+// User represents a user in the system. Usernames are unique per tenant;
+// deactivated users cannot log in or use existing tokens. The optional PIN signs
+// the user in on the tenant's registered terminals and locks after repeated failures.
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Username  string    `json:"username" gorm:"uniqueIndex;not null"`
-	Password  string    `json:"-" gorm:"not null"`
-	Role      string    `json:"role" gorm:"not null;default:'user'"`
-	TenantID  *uint     `json:"tenant_id" gorm:"index"`
-	Tenant    *Tenant   `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	Username          string     `json:"username" gorm:"not null;uniqueIndex:idx_users_tenant_username"`
+	Password          string     `json:"-" gorm:"not null"`
+	PINHash           string     `json:"-" gorm:"column:pin_hash;not null;default:''"`
+	PINFailedAttempts int        `json:"-" gorm:"column:pin_failed_attempts;not null;default:0"`
+	PINLockedUntil    *time.Time `json:"-" gorm:"column:pin_locked_until"`
+	Role              string     `json:"role" gorm:"not null;default:'cashier'"`
+	Active            bool       `json:"active" gorm:"not null;default:true"`
+	TenantID          *uint      `json:"tenant_id" gorm:"uniqueIndex:idx_users_tenant_username"`
+	Tenant            *Tenant    `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// HasPIN reports whether the user can sign in on terminals
+func (u *User) HasPIN() bool {
+	return u.PINHash != ""
 }
 
 // TableName sets the table name for GORM
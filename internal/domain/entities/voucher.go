@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// Voucher is a generated code that redeems a voucher-only promotion.
+// MaxUses of 1 makes it single-use and 0 unlimited; MaxUsesPerCustomer limits redemptions per
+// customer phone number, 0 meaning no limit. MinBasket is compared to the subtotal before discounts.
+type Voucher struct {
+	ID                 uint        `json:"id" gorm:"primaryKey"`
+	PromotionID        uint        `json:"promotion_id" gorm:"not null;index"`
+	Promotion          *Promotion  `json:"promotion,omitempty" gorm:"foreignKey:PromotionID"`
+	Code               string      `json:"code" gorm:"not null;uniqueIndex:idx_vouchers_tenant_code"`
+	MaxUses            int         `json:"max_uses" gorm:"not null;default:1"`
+	MaxUsesPerCustomer int         `json:"max_uses_per_customer" gorm:"not null;default:0"`
+	UsedCount          int         `json:"used_count" gorm:"not null;default:0"`
+	MinBasket          money.Money `json:"min_basket" gorm:"not null;default:0"`
+	ExpiresAt          *time.Time  `json:"expires_at"`
+	Active             bool        `json:"active" gorm:"not null"`
+	TenantID           *uint       `json:"tenant_id" gorm:"uniqueIndex:idx_vouchers_tenant_code"`
+	Tenant             *Tenant     `json:"tenant,omitempty" gorm:"foreignKey:TenantID"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// VoucherRedemption records a voucher used on a sale. Voiding the sale removes it and
+// gives the use back.
+type VoucherRedemption struct {
+	ID            uint        `json:"id" gorm:"primaryKey"`
+	VoucherID     uint        `json:"voucher_id" gorm:"not null;index"`
+	TransactionID uint        `json:"transaction_id" gorm:"not null;index"`
+	CustomerPhone string      `json:"customer_phone,omitempty" gorm:"index"`
+	Discount      money.Money `json:"discount" gorm:"not null;default:0"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (Voucher) TableName() string {
+	return "vouchers"
+}
+
+// TableName sets the table name for GORM
+func (VoucherRedemption) TableName() string {
+	return "voucher_redemptions"
+}
+
+// Exhausted reports whether the voucher has no uses left
+func (v *Voucher) Exhausted() bool {
+	return v.MaxUses > 0 && v.UsedCount >= v.MaxUses
+}
@@ -5,35 +5,203 @@ import (
 	"time"
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 )
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*entities.User, error)
+	ListByUsername(ctx context.Context, username string) ([]entities.User, error)
 	GetByID(ctx context.Context, id uint) (*entities.User, error)
 	Create(ctx context.Context, user *entities.User) error
-	List(ctx context.Context) ([]*entities.User, error)
+	List(ctx context.Context, page, limit int) ([]entities.User, int64, error)
 	Update(ctx context.Context, user *entities.User) error
 	Delete(ctx context.Context, id uint) error
+	HasHistory(ctx context.Context, id uint) (bool, error)
+	UpdatePIN(ctx context.Context, user *entities.User) error
+	RecordPINFailure(ctx context.Context, id uint, maxAttempts int, lockout time.Duration) (*time.Time, error)
+	ClearPINFailures(ctx context.Context, id uint) error
+	ListPINUsers(ctx context.Context) ([]entities.User, error)
 }
 
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
 	GetByID(ctx context.Context, id uint) (*entities.Product, error)
-	List(ctx context.Context, page, limit int) ([]entities.Product, int64, error)
+	List(ctx context.Context, query ProductListQuery) ([]entities.Product, int64, error)
 	Update(ctx context.Context, product *entities.Product) error
-	UpdateStock(ctx context.Context, id uint, stock int) error
+	ReplaceTags(ctx context.Context, product *entities.Product, tags []entities.Tag) error
+	ReplaceModifierGroups(ctx context.Context, product *entities.Product, groups []entities.ModifierGroup) error
 	Create(ctx context.Context, product *entities.Product) error
 	GetBySKU(ctx context.Context, sku string) (*entities.Product, error)
+	ListLowStock(ctx context.Context, page, limit int) ([]entities.Product, int64, error)
+	GetLowStockByIDs(ctx context.Context, ids []uint) ([]entities.Product, error)
 	Delete(ctx context.Context, id uint) error
 }
 
+// ProductBarcodeRepository defines the interface for product barcode data operations
+type ProductBarcodeRepository interface {
+	Create(ctx context.Context, barcode *entities.ProductBarcode) error
+	GetByCode(ctx context.Context, code string) (*entities.ProductBarcode, error)
+	Delete(ctx context.Context, productID, id uint) error
+}
+
+// CategoryRepository defines the interface for category data operations
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) error
+	GetByID(ctx context.Context, id uint) (*entities.Category, error)
+	List(ctx context.Context) ([]entities.Category, error)
+	Update(ctx context.Context, category *entities.Category) error
+	Delete(ctx context.Context, id uint) error
+	CountProducts(ctx context.Context, id uint) (int64, error)
+}
+
+// ModifierGroupRepository defines the interface for modifier group data operations
+type ModifierGroupRepository interface {
+	Create(ctx context.Context, group *entities.ModifierGroup) error
+	GetByID(ctx context.Context, id uint) (*entities.ModifierGroup, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]entities.ModifierGroup, error)
+	List(ctx context.Context) ([]entities.ModifierGroup, error)
+	Update(ctx context.Context, group *entities.ModifierGroup) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// PromotionRepository defines the interface for promotion data operations
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *entities.Promotion) error
+	GetByID(ctx context.Context, id uint) (*entities.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*entities.Promotion, error)
+	List(ctx context.Context, page, limit int) ([]entities.Promotion, int64, error)
+	Update(ctx context.Context, promotion *entities.Promotion) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// VoucherRepository defines the interface for generated voucher data operations
+type VoucherRepository interface {
+	CreateBatch(ctx context.Context, vouchers []entities.Voucher) error
+	ListByPromotion(ctx context.Context, promotionID uint, page, limit int) ([]entities.Voucher, int64, error)
+	ListAllByPromotion(ctx context.Context, promotionID uint) ([]entities.Voucher, error)
+	ExistingCodes(ctx context.Context, codes []string) ([]string, error)
+}
+
+// CustomerRepository defines the interface for customer data operations
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *entities.Customer) error
+	GetByID(ctx context.Context, id uint) (*entities.Customer, error)
+	GetByPhone(ctx context.Context, phone string) (*entities.Customer, error)
+	List(ctx context.Context, search string, page, limit int) ([]entities.Customer, int64, error)
+	Update(ctx context.Context, customer *entities.Customer) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// LoyaltyRepository defines the interface for loyalty program and points ledger data operations
+type LoyaltyRepository interface {
+	GetProgram(ctx context.Context) (*entities.LoyaltyProgram, error)
+	SaveProgram(ctx context.Context, program *entities.LoyaltyProgram) error
+	ListEntries(ctx context.Context, phone string, page, limit int) ([]entities.LoyaltyLedgerEntry, int64, error)
+	AllEntries(ctx context.Context, phone string) ([]entities.LoyaltyLedgerEntry, error)
+}
+
+// ShiftRepository defines the interface for cashier shift data operations
+type ShiftRepository interface {
+	GetByID(ctx context.Context, id uint) (*entities.Shift, error)
+	GetOpenByUser(ctx context.Context, userID uint) (*entities.Shift, error)
+	List(ctx context.Context, page, limit int) ([]entities.Shift, int64, error)
+	GetTenderSales(ctx context.Context, shiftID uint) ([]ShiftTenderTotal, error)
+	GetTenderRefunds(ctx context.Context, shiftID uint) ([]ShiftTenderTotal, error)
+}
+
+// RoleRepository defines the interface for custom role data operations
+type RoleRepository interface {
+	Create(ctx context.Context, role *entities.Role) error
+	GetByID(ctx context.Context, id uint) (*entities.Role, error)
+	GetByName(ctx context.Context, name string) (*entities.Role, error)
+	List(ctx context.Context) ([]entities.Role, error)
+	Update(ctx context.Context, role *entities.Role) error
+	Delete(ctx context.Context, id uint) error
+	CountUsers(ctx context.Context, name string) (int64, error)
+}
+
+// TerminalRepository defines the interface for registered terminal data operations
+type TerminalRepository interface {
+	Create(ctx context.Context, terminal *entities.Terminal) error
+	GetByID(ctx context.Context, id uint) (*entities.Terminal, error)
+	GetByTokenHash(ctx context.Context, hash string) (*entities.Terminal, error)
+	List(ctx context.Context) ([]entities.Terminal, error)
+	Update(ctx context.Context, terminal *entities.Terminal) error
+}
+
+// SessionRepository defines the interface for login session and revoked token data operations
+type SessionRepository interface {
+	Create(ctx context.Context, session *entities.Session) error
+	GetByRefreshTokenHash(ctx context.Context, hash string) (*entities.Session, error)
+	GetByPreviousTokenHash(ctx context.Context, hash string) (*entities.Session, error)
+	Rotate(ctx context.Context, session *entities.Session, replaced entities.RevokedToken) error
+	Revoke(ctx context.Context, id uint) error
+	RevokeAllForUser(ctx context.Context, userID uint) (int64, error)
+	RevokeAllForTerminal(ctx context.Context, terminalID uint) (int64, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// AuthAuditRepository defines the interface for authentication audit log operations
+type AuthAuditRepository interface {
+	Create(ctx context.Context, entry *entities.AuthAuditLog) error
+}
+
+// ShiftTenderTotal represents money taken or paid back through one tender during a shift
+type ShiftTenderTotal struct {
+	Method       string      `json:"method"`
+	Transactions int         `json:"transactions"`
+	Amount       money.Money `json:"amount"`
+}
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	FindOrCreate(ctx context.Context, names []string) ([]entities.Tag, error)
+	List(ctx context.Context) ([]entities.Tag, error)
+}
+
+// StockMovementRepository defines the interface for stock ledger queries.
+// Movements are written by the services inside the DB transaction that changes stock.
+type StockMovementRepository interface {
+	ListByProduct(ctx context.Context, productID uint, page, limit int) ([]entities.StockMovement, int64, error)
+}
+
+// StockOpnameRepository defines the interface for stock count session data operations
+type StockOpnameRepository interface {
+	Create(ctx context.Context, opname *entities.StockOpname) error
+	GetByID(ctx context.Context, id uint) (*entities.StockOpname, error)
+	List(ctx context.Context, page, limit int) ([]entities.StockOpname, int64, error)
+}
+
+// SupplierRepository defines the interface for supplier data operations
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *entities.Supplier) error
+	GetByID(ctx context.Context, id uint) (*entities.Supplier, error)
+	List(ctx context.Context, page, limit int) ([]entities.Supplier, int64, error)
+	Update(ctx context.Context, supplier *entities.Supplier) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// PurchaseOrderRepository defines the interface for purchase order data operations
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, order *entities.PurchaseOrder) error
+	GetByID(ctx context.Context, id uint) (*entities.PurchaseOrder, error)
+	List(ctx context.Context, page, limit int) ([]entities.PurchaseOrder, int64, error)
+	GetOutstandingBySupplier(ctx context.Context) ([]OutstandingPurchaseOrders, error)
+}
+
 // TransactionRepository defines the interface for transaction data operations
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entities.Transaction) error
 	GetByID(ctx context.Context, id uint) (*entities.Transaction, error)
 	List(ctx context.Context, page, limit int) ([]entities.Transaction, int64, error)
+	ListByCustomer(ctx context.Context, customerID uint, page, limit int) ([]entities.Transaction, int64, error)
+	ListOrders(ctx context.Context, statuses []string, page, limit int) ([]entities.Transaction, int64, error)
 	GetReportData(ctx context.Context, startDate, endDate time.Time) ([]ReportDetail, error)
+	GetPaymentBreakdown(ctx context.Context, startDate, endDate time.Time) ([]PaymentBreakdown, error)
+	GetModifierBreakdown(ctx context.Context, startDate, endDate time.Time) ([]ModifierBreakdown, error)
+	GetTaxSummary(ctx context.Context, startDate, endDate time.Time) ([]TaxSummary, error)
+	GetPromotionBreakdown(ctx context.Context, startDate, endDate time.Time) ([]PromotionBreakdown, error)
 	Update(ctx context.Context, transaction *entities.Transaction) error
 	Delete(ctx context.Context, id uint) error
 }
@@ -47,11 +215,72 @@ type TenantRepository interface {
 	Delete(ctx context.Context, id uint) error
 }
 
-// ReportDetail represents report data structure
+// ProductListQuery filters, sorts and paginates product listings.
+// CategoryID matches the category and all of its subcategories; the service expands it into CategoryIDs.
+type ProductListQuery struct {
+	Page        int          `json:"page"`
+	Limit       int          `json:"limit"`
+	Search      string       `json:"search"`
+	CategoryID  *uint        `json:"category_id"`
+	CategoryIDs []uint       `json:"-"`
+	Tag         string       `json:"tag"`
+	MinPrice    *money.Money `json:"min_price"`
+	MaxPrice    *money.Money `json:"max_price"`
+	InStockOnly bool         `json:"in_stock_only"`
+	SortBy      string       `json:"sort_by"`
+	SortDir     string       `json:"sort_dir"`
+}
+
+// ReportDetail represents report data structure.
+// Details are per sold product or variant; ParentProductID is set for variants.
 type ReportDetail struct {
-	ID          uint    `json:"id"`
-	ProductID   uint    `json:"product_id"`
-	ProductName string  `json:"product_name"`
-	Total       int     `json:"total"`
-	TotalPrice  float64 `json:"total_price"`
+	ID                uint        `json:"id"`
+	ProductID         uint        `json:"product_id"`
+	ProductName       string      `json:"product_name"`
+	ParentProductID   *uint       `json:"parent_product_id"`
+	ParentProductName string      `json:"parent_product_name,omitempty"`
+	Total             int         `json:"total"`
+	TotalPrice        money.Money `json:"total_price"`
+	TotalCost         money.Money `json:"total_cost"`
+}
+
+// PaymentBreakdown represents revenue collected per tender type
+type PaymentBreakdown struct {
+	Method       string      `json:"method"`
+	Transactions int         `json:"transactions"`
+	Amount       money.Money `json:"amount"`
+}
+
+// TaxSummary represents service charge and PPN collected at one tax rate, net of refunds
+type TaxSummary struct {
+	TaxRate       float64     `json:"tax_rate"`
+	TaxBase       money.Money `json:"tax_base"`
+	Tax           money.Money `json:"tax"`
+	ServiceCharge money.Money `json:"service_charge"`
+}
+
+// PromotionBreakdown represents what one promotion gave away and the sales it touched, net of refunds
+type PromotionBreakdown struct {
+	PromotionID  uint        `json:"promotion_id"`
+	Name         string      `json:"name"`
+	Transactions int         `json:"transactions"`
+	Discount     money.Money `json:"discount"`
+	Revenue      money.Money `json:"revenue"` // net sales of the discounted lines
+}
+
+// ModifierBreakdown represents how often a modifier was sold and the revenue it added
+type ModifierBreakdown struct {
+	GroupName string      `json:"group_name"`
+	Name      string      `json:"name"`
+	Total     int         `json:"total"`
+	Revenue   money.Money `json:"revenue"`
+}
+
+// OutstandingPurchaseOrders represents ordered stock not yet received from one supplier
+type OutstandingPurchaseOrders struct {
+	SupplierID       uint        `json:"supplier_id"`
+	SupplierName     string      `json:"supplier_name"`
+	OpenOrders       int         `json:"open_orders"`
+	OutstandingQty   int         `json:"outstanding_qty"`
+	OutstandingValue money.Money `json:"outstanding_value"`
 }
@@ -5,11 +5,20 @@ import (
 	"time"
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 )
 
 // AuthService defines authentication operations
 type AuthService interface {
-	Login(ctx context.Context, username, password string) (string, *entities.User, error)
+	Login(ctx context.Context, username, password string, client ClientInfo) (*AuthTokens, *entities.User, error)
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*AuthTokens, *entities.User, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uint) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PINLogin(ctx context.Context, deviceToken, username, pin string, client ClientInfo) (*AuthTokens, *entities.User, error)
+	TerminalUsers(ctx context.Context, deviceToken string) ([]entities.User, error)
+	SetPIN(ctx context.Context, userID uint, password, pin string) error
+	UnlockLogin(ctx context.Context, username, ipAddress string) error
 	ValidateToken(tokenString string) (*entities.User, error)
 	HashPassword(password string) (string, error)
 	GetUserByID(ctx context.Context, id uint) (*entities.User, error)
@@ -17,10 +26,31 @@ type AuthService interface {
 	UpdatePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
 }
 
+// TerminalService defines registered terminal business operations
+type TerminalService interface {
+	RegisterTerminal(ctx context.Context, name string) (*entities.Terminal, string, error)
+	ListTerminals(ctx context.Context) ([]entities.Terminal, error)
+	RevokeTerminal(ctx context.Context, id uint) error
+}
+
+// ClientInfo identifies the device a session was started from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// AuthTokens is a short-lived access token with the refresh token that renews it
+type AuthTokens struct {
+	AccessToken           string    `json:"token"`
+	AccessTokenExpiresAt  time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
 // ProductService defines product business operations
 type ProductService interface {
 	GetProduct(ctx context.Context, id uint) (*entities.Product, error)
-	ListProducts(ctx context.Context, page, limit int) ([]entities.Product, int64, error)
+	ListProducts(ctx context.Context, query ProductListQuery) ([]entities.Product, int64, error)
 	UpdateProduct(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Product, error)
 	UpdateStock(ctx context.Context, id uint, stock int) (*entities.Product, error)
 	CreateProduct(ctx context.Context, product *entities.Product) error
@@ -28,6 +58,214 @@ type ProductService interface {
 	GetProductUploadURL(ctx context.Context, product *entities.Product, ext string) (string, error)
 	UploadProductImage(ctx context.Context, productID uint, fileData []byte, contentType string) (*entities.Product, error)
 	GetProductImageBytes(ctx context.Context, productID uint) ([]byte, string, error)
+	GetStockHistory(ctx context.Context, productID uint, page, limit int) ([]entities.StockMovement, int64, error)
+	ListLowStockProducts(ctx context.Context, page, limit int) ([]entities.Product, int64, error)
+	ListTags(ctx context.Context) ([]entities.Tag, error)
+	LookupBarcode(ctx context.Context, code string) (*entities.Product, int, error)
+	AddBarcode(ctx context.Context, productID uint, barcode *entities.ProductBarcode) error
+	RemoveBarcode(ctx context.Context, productID, barcodeID uint) error
+	GenerateVariants(ctx context.Context, productID uint, options []ProductOptionInput) (*entities.Product, error)
+	SetRecipe(ctx context.Context, productID uint, components []ProductComponentInput, deductOwnStock bool) (*entities.Product, error)
+}
+
+// ProductComponentInput is one recipe line: a component product and the units one sale consumes
+type ProductComponentInput struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// ProductOptionInput defines one variant dimension of a parent product and its values
+type ProductOptionInput struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// CategoryService defines product category operations
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *entities.Category) error
+	GetCategory(ctx context.Context, id uint) (*entities.Category, error)
+	ListCategories(ctx context.Context) ([]entities.Category, error)
+	UpdateCategory(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Category, error)
+	DeleteCategory(ctx context.Context, id uint) error
+}
+
+// ModifierService defines modifier group operations
+type ModifierService interface {
+	CreateModifierGroup(ctx context.Context, group *entities.ModifierGroup) error
+	GetModifierGroup(ctx context.Context, id uint) (*entities.ModifierGroup, error)
+	ListModifierGroups(ctx context.Context) ([]entities.ModifierGroup, error)
+	UpdateModifierGroup(ctx context.Context, id uint, group *entities.ModifierGroup) (*entities.ModifierGroup, error)
+	DeleteModifierGroup(ctx context.Context, id uint) error
+	SetProductModifierGroups(ctx context.Context, productID uint, groupIDs []uint) (*entities.Product, error)
+}
+
+// CustomerService defines customer operations
+type CustomerService interface {
+	CreateCustomer(ctx context.Context, customer *entities.Customer) error
+	GetCustomer(ctx context.Context, id uint) (*entities.Customer, error)
+	GetCustomerByPhone(ctx context.Context, phone string) (*entities.Customer, error)
+	ListCustomers(ctx context.Context, search string, page, limit int) ([]entities.Customer, int64, error)
+	UpdateCustomer(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Customer, error)
+	DeleteCustomer(ctx context.Context, id uint) error
+	ListCustomerTransactions(ctx context.Context, id uint, page, limit int) ([]entities.Transaction, int64, error)
+}
+
+// LoyaltyService defines loyalty program operations
+type LoyaltyService interface {
+	GetProgram(ctx context.Context) (*entities.LoyaltyProgram, error)
+	UpdateProgram(ctx context.Context, program *entities.LoyaltyProgram) (*entities.LoyaltyProgram, error)
+	GetAccount(ctx context.Context, phone string) (*LoyaltyAccount, error)
+	ListEntries(ctx context.Context, phone string, page, limit int) ([]entities.LoyaltyLedgerEntry, int64, error)
+}
+
+// LoyaltyAccount summarises a customer's points, derived from their ledger
+type LoyaltyAccount struct {
+	Phone        string      `json:"phone"`
+	Balance      int         `json:"balance"`
+	BalanceValue money.Money `json:"balance_value"` // what the balance pays as a tender
+	Tier         string      `json:"tier,omitempty"`
+	TierPoints   int         `json:"tier_points"` // points earned in the last year, which decide the tier
+}
+
+// UserService defines tenant user management
+type UserService interface {
+	ListUsers(ctx context.Context, page, limit int) ([]entities.User, int64, error)
+	GetUser(ctx context.Context, id uint) (*entities.User, error)
+	CreateUser(ctx context.Context, req CreateUserRequest) (*entities.User, error)
+	UpdateUser(ctx context.Context, id uint, req UpdateUserRequest) (*entities.User, error)
+	ResetPassword(ctx context.Context, id uint, password string) error
+	UnlockUser(ctx context.Context, id uint) error
+	DeleteUser(ctx context.Context, id uint) error
+}
+
+// CreateUserRequest represents the request to create a user in the current tenant
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UpdateUserRequest represents the request to update a user; nil fields are left unchanged
+type UpdateUserRequest struct {
+	Username *string `json:"username,omitempty"`
+	Role     *string `json:"role,omitempty"`
+	Active   *bool   `json:"active,omitempty"`
+}
+
+// RoleService defines role management and permission resolution
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]RoleSummary, error)
+	GetRole(ctx context.Context, id uint) (*entities.Role, error)
+	CreateRole(ctx context.Context, req RoleRequest) (*entities.Role, error)
+	UpdateRole(ctx context.Context, id uint, req RoleRequest) (*entities.Role, error)
+	DeleteRole(ctx context.Context, id uint) error
+	ResolvePermissions(ctx context.Context, role string) (entities.PermissionSet, error)
+}
+
+// RoleRequest represents the request to create or update a custom role
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleSummary describes a built-in or custom role available to the tenant.
+// ID is nil for built-in roles.
+type RoleSummary struct {
+	ID          *uint    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	BuiltIn     bool     `json:"built_in"`
+	Permissions []string `json:"permissions"`
+}
+
+// ShiftService defines cashier shift and cash drawer operations
+type ShiftService interface {
+	OpenShift(ctx context.Context, req OpenShiftRequest) (*entities.Shift, error)
+	GetCurrentShift(ctx context.Context) (*entities.Shift, error)
+	GetShift(ctx context.Context, id uint) (*entities.Shift, error)
+	ListShifts(ctx context.Context, page, limit int) ([]entities.Shift, int64, error)
+	RecordCashEvent(ctx context.Context, id uint, req CashEventRequest) (*entities.Shift, error)
+	CloseShift(ctx context.Context, id uint, req CloseShiftRequest) (*entities.Shift, error)
+	GetShiftReport(ctx context.Context, id uint) (*ShiftReport, error)
+}
+
+// OpenShiftRequest represents the request to open a shift for the logged-in user
+type OpenShiftRequest struct {
+	OpeningFloat money.Money `json:"opening_float"`
+	Notes        string      `json:"notes"`
+}
+
+// CashEventRequest represents cash put into or taken out of the drawer
+type CashEventRequest struct {
+	Type   string      `json:"type"`
+	Amount money.Money `json:"amount"`
+	Reason string      `json:"reason"`
+}
+
+// CloseShiftRequest represents the closing count of a shift.
+// Counts may add totals for other tenders, such as card terminal settlements.
+type CloseShiftRequest struct {
+	CountedCash money.Money   `json:"counted_cash"`
+	Counts      []TenderCount `json:"counts"`
+	Notes       string        `json:"notes"`
+}
+
+// TenderCount represents the amount counted for one tender
+type TenderCount struct {
+	Method string      `json:"method"`
+	Amount money.Money `json:"amount"`
+}
+
+// ShiftReport compares what each tender should hold with what was counted
+type ShiftReport struct {
+	Shift   *entities.Shift     `json:"shift"`
+	Tenders []ShiftTenderReport `json:"tenders"`
+}
+
+// ShiftTenderReport represents the expected and counted amount of one tender.
+// Expected is the float plus sales less refunds, plus cash in less cash out; only cash has a float and cash events.
+type ShiftTenderReport struct {
+	Method       string       `json:"method"`
+	Transactions int          `json:"transactions"`
+	Float        money.Money  `json:"float"`
+	Sales        money.Money  `json:"sales"`
+	Refunds      money.Money  `json:"refunds"`
+	CashIn       money.Money  `json:"cash_in"`
+	CashOut      money.Money  `json:"cash_out"`
+	Expected     money.Money  `json:"expected"`
+	Counted      *money.Money `json:"counted"`    // nil when the tender was not counted
+	Difference   *money.Money `json:"difference"` // counted minus expected
+}
+
+// PromotionService defines promotion operations
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, promotion *entities.Promotion) error
+	GetPromotion(ctx context.Context, id uint) (*entities.Promotion, error)
+	ListPromotions(ctx context.Context, page, limit int) ([]entities.Promotion, int64, error)
+	UpdatePromotion(ctx context.Context, id uint, promotion *entities.Promotion) (*entities.Promotion, error)
+	DeletePromotion(ctx context.Context, id uint) error
+	GenerateVouchers(ctx context.Context, promotionID uint, req GenerateVouchersRequest) ([]entities.Voucher, error)
+	ListVouchers(ctx context.Context, promotionID uint, page, limit int) ([]entities.Voucher, int64, error)
+	ExportVouchers(ctx context.Context, promotionID uint) ([]entities.Voucher, error)
+}
+
+// GenerateVouchersRequest describes a batch of voucher codes for a voucher-only promotion.
+// Codes are Prefix followed by Length random characters.
+type GenerateVouchersRequest struct {
+	Count              int         `json:"count"`
+	Prefix             string      `json:"prefix"`
+	Length             int         `json:"length"`
+	MaxUses            int         `json:"max_uses"`
+	MaxUsesPerCustomer int         `json:"max_uses_per_customer"`
+	MinBasket          money.Money `json:"min_basket"`
+	ExpiresAt          *time.Time  `json:"expires_at"`
+}
+
+// StockAlertService evaluates reorder points and notifies staff
+type StockAlertService interface {
+	// CheckAfterSale runs in the background and alerts for sold products that crossed their reorder point
+	CheckAfterSale(ctx context.Context, sold map[uint]int)
 }
 
 // TransactionService defines transaction business operations
@@ -35,6 +273,19 @@ type TransactionService interface {
 	CreateTransaction(ctx context.Context, req CreateTransactionRequest) (*entities.Transaction, error)
 	GetTransaction(ctx context.Context, id uint) (*entities.Transaction, error)
 	ListTransactions(ctx context.Context, page, limit int) ([]entities.Transaction, int64, error)
+	VoidTransaction(ctx context.Context, id uint, req VoidTransactionRequest) (*entities.Transaction, error)
+	RefundTransaction(ctx context.Context, id uint, req RefundTransactionRequest) (*entities.Transaction, error)
+
+	// Open orders are transactions that collect lines before they are paid
+	OpenOrder(ctx context.Context, req OpenOrderRequest) (*entities.Transaction, error)
+	ListOrders(ctx context.Context, status string, page, limit int) ([]entities.Transaction, int64, error)
+	UpdateOrder(ctx context.Context, id uint, req UpdateOrderRequest) (*entities.Transaction, error)
+	AddOrderItem(ctx context.Context, id uint, item TransactionItemRequest) (*entities.Transaction, error)
+	RemoveOrderItem(ctx context.Context, id, itemID uint) (*entities.Transaction, error)
+	HoldOrder(ctx context.Context, id uint) (*entities.Transaction, error)
+	ResumeOrder(ctx context.Context, id uint) (*entities.Transaction, error)
+	SettleOrder(ctx context.Context, id uint, req SettleOrderRequest) (*entities.Transaction, error)
+	CancelOrder(ctx context.Context, id uint) (*entities.Transaction, error)
 }
 
 // ReportService defines reporting operations
@@ -42,6 +293,36 @@ type ReportService interface {
 	GetSalesReport(ctx context.Context, startDate, endDate time.Time) (*ReportResponse, error)
 }
 
+// StockOpnameService defines stock count session operations
+type StockOpnameService interface {
+	OpenSession(ctx context.Context, notes string) (*entities.StockOpname, error)
+	GetSession(ctx context.Context, id uint) (*entities.StockOpname, error)
+	ListSessions(ctx context.Context, page, limit int) ([]entities.StockOpname, int64, error)
+	RecordCounts(ctx context.Context, id uint, req RecordStockCountRequest) (*entities.StockOpname, error)
+	GetVariance(ctx context.Context, id uint) (*StockOpnameVariance, error)
+	CommitSession(ctx context.Context, id uint) (*entities.StockOpname, error)
+	CancelSession(ctx context.Context, id uint) (*entities.StockOpname, error)
+}
+
+// SupplierService defines supplier business operations
+type SupplierService interface {
+	CreateSupplier(ctx context.Context, supplier *entities.Supplier) error
+	GetSupplier(ctx context.Context, id uint) (*entities.Supplier, error)
+	ListSuppliers(ctx context.Context, page, limit int) ([]entities.Supplier, int64, error)
+	UpdateSupplier(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Supplier, error)
+	DeleteSupplier(ctx context.Context, id uint) error
+}
+
+// PurchaseOrderService defines purchasing and goods receiving operations
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(ctx context.Context, req CreatePurchaseOrderRequest) (*entities.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id uint) (*entities.PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, page, limit int) ([]entities.PurchaseOrder, int64, error)
+	ReceiveGoods(ctx context.Context, id uint, req ReceiveGoodsRequest) (*entities.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id uint) (*entities.PurchaseOrder, error)
+	GetOutstandingReport(ctx context.Context) ([]OutstandingPurchaseOrders, error)
+}
+
 // TenantService defines tenant business operations
 type TenantService interface {
 	CreateTenant(ctx context.Context, tenant *entities.Tenant) error
@@ -57,20 +338,154 @@ type CreateTransactionRequest struct {
 	User          string                   `json:"user"`
 	PaymentMethod string                   `json:"payment_method"`
 	Discount      float64                  `json:"discount"`
-	TotalPrice    float64                  `json:"total_price"`
+	TotalPrice    money.Money              `json:"total_price"`
 	Notes         string                   `json:"notes"`
+	Payments      []PaymentRequest         `json:"payments"`
+	VoucherCode   string                   `json:"voucher_code"`
+	CustomerID    *uint                    `json:"customer_id"`
+	CustomerPhone string                   `json:"customer_phone"`
 }
 
-// TransactionItemRequest represents an item in transaction request
+// PaymentRequest represents a tender in transaction request
+type PaymentRequest struct {
+	Method          string      `json:"method"`
+	Amount          money.Money `json:"amount"`
+	ReferenceNumber string      `json:"reference_number"`
+}
+
+// TransactionItemRequest represents an item in transaction request.
+// Barcode may be given instead of ProductID; pack barcodes multiply Quantity.
 type TransactionItemRequest struct {
+	ProductID uint   `json:"product_id"`
+	Barcode   string `json:"barcode"`
+	Quantity  int    `json:"quantity"`
+	Modifiers []uint `json:"modifiers"` // selected modifier option IDs
+}
+
+// OpenOrderRequest represents the request to open an order, optionally with its first lines
+type OpenOrderRequest struct {
+	User  string                   `json:"user"`
+	Label string                   `json:"label"`
+	Notes string                   `json:"notes"`
+	Items []TransactionItemRequest `json:"items"`
+}
+
+// UpdateOrderRequest represents the request to assign an order to a table or label
+type UpdateOrderRequest struct {
+	Label string `json:"label"`
+	Notes string `json:"notes"`
+}
+
+// SettleOrderRequest represents the payment that closes an open order.
+// The order's lines are priced and checked out like a new transaction.
+type SettleOrderRequest struct {
+	PaymentMethod string           `json:"payment_method"`
+	Discount      float64          `json:"discount"`
+	TotalPrice    money.Money      `json:"total_price"`
+	Payments      []PaymentRequest `json:"payments"`
+	VoucherCode   string           `json:"voucher_code"`
+	CustomerID    *uint            `json:"customer_id"`
+	CustomerPhone string           `json:"customer_phone"`
+}
+
+// VoidTransactionRequest represents the request to void a transaction
+type VoidTransactionRequest struct {
+	Reason string `json:"reason"`
+	UserID uint   `json:"user_id"`
+}
+
+// RefundTransactionRequest represents the request to refund a transaction.
+// An empty Items list refunds every remaining quantity.
+type RefundTransactionRequest struct {
+	Reason string              `json:"reason"`
+	UserID uint                `json:"user_id"`
+	Items  []RefundItemRequest `json:"items"`
+}
+
+// RefundItemRequest represents a returned quantity of a transaction item
+type RefundItemRequest struct {
+	TransactionItemID uint `json:"transaction_item_id"`
+	Quantity          int  `json:"quantity"`
+}
+
+// RecordStockCountRequest represents counted quantities submitted by one device.
+// Counts are added to what other devices already submitted unless Replace is set.
+type RecordStockCountRequest struct {
+	Items   []StockCountItem `json:"items"`
+	Replace bool             `json:"replace"`
+}
+
+// StockCountItem represents the counted quantity of one product
+type StockCountItem struct {
 	ProductID uint `json:"product_id"`
 	Quantity  int  `json:"quantity"`
 }
 
+// StockOpnameVariance represents counted versus system stock for a session
+type StockOpnameVariance struct {
+	OpnameID           uint                      `json:"opname_id"`
+	Status             string                    `json:"status"`
+	Items              []StockOpnameVarianceItem `json:"items"`
+	TotalVariance      int                       `json:"total_variance"`
+	TotalVarianceValue money.Money               `json:"total_variance_value"`
+}
+
+// StockOpnameVarianceItem represents the variance of one counted product, valued at HargaModal
+type StockOpnameVarianceItem struct {
+	ProductID     uint        `json:"product_id"`
+	ProductName   string      `json:"product_name"`
+	SKU           string      `json:"sku"`
+	SystemQty     int         `json:"system_qty"`
+	CountedQty    int         `json:"counted_qty"`
+	Variance      int         `json:"variance"`
+	HargaModal    money.Money `json:"harga_modal"`
+	VarianceValue money.Money `json:"variance_value"`
+}
+
+// CreatePurchaseOrderRequest represents the request to create a purchase order
+type CreatePurchaseOrderRequest struct {
+	SupplierID uint                       `json:"supplier_id"`
+	ExpectedAt *time.Time                 `json:"expected_at"`
+	Notes      string                     `json:"notes"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+}
+
+// PurchaseOrderItemRequest represents an ordered product
+type PurchaseOrderItemRequest struct {
+	ProductID uint        `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	UnitCost  money.Money `json:"unit_cost"`
+}
+
+// ReceiveGoodsRequest represents a delivery received against a purchase order.
+// With UpdateCost, HargaModal of each product becomes the weighted average of stock on hand and the received goods.
+type ReceiveGoodsRequest struct {
+	Notes      string               `json:"notes"`
+	UpdateCost bool                 `json:"update_cost"`
+	Items      []ReceiveItemRequest `json:"items"`
+}
+
+// ReceiveItemRequest represents a received quantity of a purchase order item.
+// UnitCost overrides the ordered cost when the invoice differs.
+type ReceiveItemRequest struct {
+	PurchaseOrderItemID uint         `json:"purchase_order_item_id"`
+	Quantity            int          `json:"quantity"`
+	UnitCost            *money.Money `json:"unit_cost"`
+}
+
 // ReportResponse represents the sales report response
 type ReportResponse struct {
-	TotalRevenue       float64        `json:"total_revenue"`
-	ItemsSold          int            `json:"items_sold"`
-	AverageTransaction float64        `json:"average_transaction"`
-	Details            []ReportDetail `json:"details"`
+	TotalRevenue       money.Money          `json:"total_revenue"`
+	TotalCost          money.Money          `json:"total_cost"` // composite items are costed at their summed component harga_modal
+	GrossMargin        money.Money          `json:"gross_margin"`
+	TotalTax           money.Money          `json:"total_tax"`
+	TotalServiceCharge money.Money          `json:"total_service_charge"`
+	ItemsSold          int                  `json:"items_sold"`
+	AverageTransaction money.Money          `json:"average_transaction"`
+	Details            []ReportDetail       `json:"details"`
+	Products           []ReportDetail       `json:"products"` // details with variants rolled up into their parent
+	Payments           []PaymentBreakdown   `json:"payments"`
+	Modifiers          []ModifierBreakdown  `json:"modifiers"`
+	Taxes              []TaxSummary         `json:"taxes"`
+	Promotions         []PromotionBreakdown `json:"promotions"`
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
@@ -97,6 +98,11 @@ func (h *AdminHandler) CreateUser(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Tenant ID is required for user creation"})
 	}
 
+	// Tenant accounts are provisioned here, so they default to the owner role
+	if user.Role == "" {
+		user.Role = entities.RoleOwner
+	}
+
 	// Hash the password before creating the user
 	hashedPassword, err := h.userService.HashPassword(user.Password)
 	if err != nil {
@@ -111,3 +117,35 @@ func (h *AdminHandler) CreateUser(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, user)
 }
+
+// UnlockLoginRequest represents the admin unlock login request.
+// TenantID limits the unlock to the user with the username in that tenant; without it
+// every user with the username is unlocked.
+type UnlockLoginRequest struct {
+	Username  string `json:"username"`
+	TenantID  *uint  `json:"tenant_id"`
+	IPAddress string `json:"ip_address"`
+}
+
+// UnlockLogin handles clearing the failed login lockout of a username and/or an IP address
+func (h *AdminHandler) UnlockLogin(c echo.Context) error {
+	var req UnlockLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.Username == "" && req.IPAddress == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Username or IP address is required"})
+	}
+
+	ctx := c.Request().Context()
+	if req.TenantID != nil {
+		ctx = context.WithValue(ctx, "tenant_id", *req.TenantID)
+	}
+
+	if err := h.userService.UnlockLogin(ctx, req.Username, req.IPAddress); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Login unlocked successfully"})
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/loginguard"
 	"gorm.io/gorm"
 )
 
@@ -25,17 +32,43 @@ func NewAuthHandler(authService interfaces.AuthService, tenantService interfaces
 	}
 }
 
-// LoginRequest represents the login request payload
+// LoginRequest represents the login request payload.
+// TenantID is only needed when the username exists in more than one tenant.
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	TenantID string `json:"tenant_id,omitempty"`
 }
 
-// LoginResponse represents the login response payload
+// LoginResponse represents the login and refresh response payload.
+// Token is the short-lived access token; RefreshToken renews it via /auth/refresh.
 type LoginResponse struct {
-	Token    string `json:"token"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Token            string `json:"token"`
+	ExpiresAt        string `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
+	Username         string `json:"username"`
+	Role             string `json:"role"`
+}
+
+// PINLoginRequest represents the terminal PIN login request payload
+type PINLoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	PIN      string `json:"pin" validate:"required"`
+}
+
+// SetPINRequest represents the set PIN request payload
+type SetPINRequest struct {
+	Password string `json:"password" validate:"required"`
+	PIN      string `json:"pin" validate:"required"`
+}
+
+// terminalTokenHeader carries the device token of a registered terminal
+const terminalTokenHeader = "X-Terminal-Token"
+
+// RefreshTokenRequest represents the refresh and logout request payload
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 // ProfileResponse represents the profile response payload
@@ -52,7 +85,7 @@ type UpdatePasswordRequest struct {
 
 // Login handles user authentication
 // @Summary Login to the system
-// @Description Authenticate user with username and password
+// @Description Authenticate user with username and password and start a session. Returns a short-lived access token and a refresh token. Send tenant_id when the username and password match users in more than one tenant. Repeated failures for a username or from an address are answered with 429 and then 423, with a Retry-After header.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -60,6 +93,9 @@ type UpdatePasswordRequest struct {
 // @Success 200 {object} Response{data=HashIDResponse}
 // @Failure 400 {object} Response
 // @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 423 {object} Response
+// @Failure 429 {object} Response
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req LoginRequest
@@ -67,23 +103,273 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
-	token, user, err := h.authService.Login(c.Request().Context(), req.Username, req.Password)
+	ctx := c.Request().Context()
+	if req.TenantID != "" {
+		tenantID, err := hash.DecodeHashID(req.TenantID)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format")
+		}
+		ctx = context.WithValue(ctx, "tenant_id", tenantID)
+	}
+
+	tokens, user, err := h.authService.Login(ctx, req.Username, req.Password, clientInfo(c))
 	if err != nil {
+		var blocked *loginguard.BlockedError
+		if errors.As(err, &blocked) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(blocked.RetryAfter(time.Now()).Seconds())))
+			if blocked.Locked {
+				return ErrorResponse(c, http.StatusLocked, entities.ErrAccountLocked.Error())
+			}
+			return ErrorResponse(c, http.StatusTooManyRequests, entities.ErrLoginThrottled.Error())
+		}
+
+		switch {
+		case errors.Is(err, entities.ErrTenantRequired):
+			return ErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entities.ErrUserInactive):
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
 		return ErrorResponse(c, http.StatusUnauthorized, "Invalid credentials")
 	}
 
-	response := WithHashID(
+	return SuccessResponse(c, http.StatusOK, "Login successful", authTokensResponse(user, tokens))
+}
+
+// PINLogin handles signing in on a registered terminal
+// @Summary Sign in on a terminal with a PIN
+// @Description Sign a user of the terminal's tenant in with their PIN, switching the terminal to that user. The previous user's session on the terminal ends. The PIN locks for 15 minutes after 5 wrong attempts in a row.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Token header string true "Terminal device token"
+// @Param request body PINLoginRequest true "PIN login credentials"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 423 {object} Response
+// @Router /auth/pin-login [post]
+func (h *AuthHandler) PINLogin(c echo.Context) error {
+	var req PINLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	deviceToken := c.Request().Header.Get(terminalTokenHeader)
+	tokens, user, err := h.authService.PINLogin(c.Request().Context(), deviceToken, req.Username, req.PIN, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidTerminal):
+			return ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, entities.ErrPINLocked):
+			return ErrorResponse(c, http.StatusLocked, err.Error())
+		case errors.Is(err, entities.ErrUserInactive):
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
+		return ErrorResponse(c, http.StatusUnauthorized, "Invalid credentials")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Login successful", authTokensResponse(user, tokens))
+}
+
+// TerminalUsers handles listing the users who can sign in on a terminal
+// @Summary List terminal users
+// @Description Get the active users of the terminal's tenant that have a PIN, for the terminal's switch-user screen
+// @Tags Authentication
+// @Produce json
+// @Param X-Terminal-Token header string true "Terminal device token"
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /auth/terminal/users [get]
+func (h *AuthHandler) TerminalUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	users, err := h.authService.TerminalUsers(ctx, c.Request().Header.Get(terminalTokenHeader))
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidTerminal) {
+			return ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		}
+		h.logger.ErrorContext(ctx, "failed to list terminal users", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list terminal users")
+	}
+
+	items := make([]HashIDResponse, len(users))
+	for i, user := range users {
+		items[i] = WithHashID(
+			user.ID,
+			user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			map[string]interface{}{
+				"username": user.Username,
+				"role":     user.Role,
+			},
+		)
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Terminal users retrieved successfully", items)
+}
+
+// SetPIN handles setting the current user's terminal PIN
+// @Summary Set terminal PIN
+// @Description Set the 4 to 6 digit PIN the current user signs in with on terminals. The current password confirms the change; setting a PIN also clears a PIN lockout.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body SetPINRequest true "Set PIN request"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Router /api/pin [put]
+func (h *AuthHandler) SetPIN(c echo.Context) error {
+	var req SetPINRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	userID := c.Get("user_id").(uint)
+	if err := h.authService.SetPIN(c.Request().Context(), userID, req.Password, req.PIN); err != nil {
+		h.logger.ErrorContext(c.Request().Context(), "failed to set PIN", "error", err, "user_id", userID)
+
+		if errors.Is(err, entities.ErrInvalidPIN) {
+			return ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		if err.Error() == "invalid current password" {
+			return ErrorResponse(c, http.StatusUnauthorized, "Invalid current password")
+		}
+
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to set PIN")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "PIN set successfully", nil)
+}
+
+// Refresh handles renewing an access token
+// @Summary Refresh the access token
+// @Description Exchange a refresh token for a new access token and a new refresh token. The presented refresh token stops working; reusing it ends the session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	tokens, user, err := h.authService.Refresh(ctx, req.RefreshToken, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidRefreshToken):
+			return ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, entities.ErrUserInactive):
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
+		h.logger.ErrorContext(ctx, "failed to refresh token", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh token")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Token refreshed successfully", authTokensResponse(user, tokens))
+}
+
+// Logout handles ending the current session
+// @Summary Log out
+// @Description End the session a refresh token belongs to. Its refresh token and latest access token stop working immediately.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	if err := h.authService.Logout(ctx, req.RefreshToken); err != nil {
+		h.logger.ErrorContext(ctx, "failed to log out", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to log out")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// LogoutAll handles ending all of the current user's sessions
+// @Summary Log out all devices
+// @Description End every session of the current user, including this one. All of the user's refresh tokens and access tokens stop working immediately.
+// @Tags Authentication
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response
+// @Failure 401 {object} Response
+// @Router /api/logout-all [post]
+func (h *AuthHandler) LogoutAll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Get("user_id").(uint)
+
+	if err := h.authService.LogoutAll(ctx, userID); err != nil {
+		h.logger.ErrorContext(ctx, "failed to log out all devices", "error", err, "user_id", userID)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to log out all devices")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Logged out of all devices successfully", nil)
+}
+
+// clientInfo describes the device making a login or refresh request.
+// The user agent is cut to fit sessions.user_agent.
+func clientInfo(c echo.Context) interfaces.ClientInfo {
+	userAgent := c.Request().UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return interfaces.ClientInfo{
+		IPAddress: c.RealIP(),
+		UserAgent: userAgent,
+	}
+}
+
+// authTokensResponse flattens a user and its new tokens for login and refresh responses
+func authTokensResponse(user *entities.User, tokens *interfaces.AuthTokens) HashIDResponse {
+	return WithHashID(
 		user.ID,
 		user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"token":    token,
-			"username": user.Username,
-			"role":     user.Role,
+			"token":              tokens.AccessToken,
+			"expires_at":         tokens.AccessTokenExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+			"refresh_token":      tokens.RefreshToken,
+			"refresh_expires_at": tokens.RefreshTokenExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+			"username":           user.Username,
+			"role":               user.Role,
 		},
 	)
-
-	return SuccessResponse(c, http.StatusOK, "Login successful", response)
 }
 
 // GetProfile handles getting user profile
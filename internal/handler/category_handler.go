@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type CategoryHandler struct {
+	categoryService interfaces.CategoryService
+	logger          *slog.Logger
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService interfaces.CategoryService, logger *slog.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+// CreateCategoryRequest represents the create category request
+type CreateCategoryRequest struct {
+	Name     string  `json:"name" validate:"required"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// UpdateCategoryRequest represents the update category request.
+// An empty parent_id moves the category to the top level.
+type UpdateCategoryRequest struct {
+	Name     *string `json:"name,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// CreateCategory handles creating a category
+// @Summary Create a category
+// @Description Create a new product category, optionally nested under a parent category
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body CreateCategoryRequest true "Create category request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	category := &entities.Category{Name: req.Name}
+	if req.ParentID != nil && *req.ParentID != "" {
+		parentID, err := hash.DecodeHashID(*req.ParentID)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid parent category ID format")
+		}
+		category.ParentID = &parentID
+	}
+
+	if err := h.categoryService.CreateCategory(ctx, category); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create category", "error", err)
+		return categoryErrorResponse(c, err, "Failed to create category")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Category created successfully", categoryResponse(category))
+}
+
+// ListCategories handles listing categories
+// @Summary List categories
+// @Description Get all product categories of the tenant; nesting is expressed through parent_id
+// @Tags Categories
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	categories, err := h.categoryService.ListCategories(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list categories", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list categories")
+	}
+
+	items := make([]HashIDResponse, len(categories))
+	for i := range categories {
+		items[i] = categoryResponse(&categories[i])
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Categories retrieved successfully", items)
+}
+
+// GetCategory handles getting a category by ID
+// @Summary Get a category
+// @Description Get a category by ID
+// @Tags Categories
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+	}
+
+	category, err := h.categoryService.GetCategory(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get category", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Category not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Category retrieved successfully", categoryResponse(category))
+}
+
+// UpdateCategory handles updating a category
+// @Summary Update a category
+// @Description Rename a category or move it under another parent
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Category ID"
+// @Param request body UpdateCategoryRequest true "Update category request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		if *req.Name == "" {
+			return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+		}
+		updates["name"] = *req.Name
+	}
+	if req.ParentID != nil {
+		var parentID *uint
+		if *req.ParentID != "" {
+			decoded, err := hash.DecodeHashID(*req.ParentID)
+			if err != nil {
+				return ErrorResponse(c, http.StatusBadRequest, "Invalid parent category ID format")
+			}
+			parentID = &decoded
+		}
+		updates["parent_id"] = parentID
+	}
+
+	category, err := h.categoryService.UpdateCategory(ctx, id, updates)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update category", "error", err, "id", id)
+		return categoryErrorResponse(c, err, "Failed to update category")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Category updated successfully", categoryResponse(category))
+}
+
+// DeleteCategory handles deleting a category
+// @Summary Delete a category
+// @Description Delete a category that has no subcategories or products
+// @Tags Categories
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+	}
+
+	if err := h.categoryService.DeleteCategory(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete category", "error", err, "id", id)
+		return categoryErrorResponse(c, err, "Failed to delete category")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
+}
+
+// decodeID decodes the hashed category ID from the URL
+func (h *CategoryHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid category ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// categoryErrorResponse maps category errors to HTTP status codes
+func categoryErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Category not found")
+	case errors.Is(err, entities.ErrCategoryCycle):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entities.ErrCategoryInUse):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// categoryResponse flattens a category with hashed IDs for API responses
+func categoryResponse(cat *entities.Category) HashIDResponse {
+	var parentID interface{}
+	if cat.ParentID != nil {
+		parentID = hash.HashID(*cat.ParentID)
+	}
+
+	return WithHashID(
+		cat.ID,
+		cat.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		cat.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":      cat.Name,
+			"parent_id": parentID,
+		},
+	)
+}
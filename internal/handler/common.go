@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 
 	"github.com/labstack/echo/v4"
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
@@ -25,6 +27,13 @@ type PaginatedResponse struct {
 
 // SuccessResponse returns a success response
 func SuccessResponse(c echo.Context, code int, message string, data interface{}) error {
+	if !canViewCost(c) {
+		redacted, err := hideCost(data)
+		if err != nil {
+			return err
+		}
+		data = redacted
+	}
 	return c.JSON(code, Response{
 		Status:  "success",
 		Message: message,
@@ -34,6 +43,13 @@ func SuccessResponse(c echo.Context, code int, message string, data interface{})
 
 // SuccessPaginatedResponse returns a success response with pagination
 func SuccessPaginatedResponse(c echo.Context, code int, message string, data interface{}, total int64, page, limit int) error {
+	if !canViewCost(c) {
+		redacted, err := hideCost(data)
+		if err != nil {
+			return err
+		}
+		data = redacted
+	}
 	return c.JSON(code, Response{
 		Status:  "success",
 		Message: message,
@@ -54,6 +70,67 @@ func ErrorResponse(c echo.Context, code int, message string) error {
 	})
 }
 
+// costFields are response keys that reveal product cost
+var costFields = []string{
+	"harga_modal",
+	"cost",
+	"unit_cost",
+	"total_cost",
+	"gross_margin",
+	"outstanding_value",
+	"previous_harga_modal",
+	"new_harga_modal",
+	"variance_value",
+	"total_variance_value",
+}
+
+// canViewCost reports whether the caller's role may see product cost
+func canViewCost(c echo.Context) bool {
+	permissions, _ := c.Get("permissions").(entities.PermissionSet)
+	return permissions.Has(entities.PermProductsViewCost)
+}
+
+// hideCost returns data without cost fields at any depth. Data is converted to its JSON form
+// first, so typed structs and slices are redacted the same way as response maps.
+func hideCost(data interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	// Numbers are kept as written so amounts such as 12000.50 are not reformatted
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	stripCost(generic)
+	return generic, nil
+}
+
+// stripCost deletes cost fields from decoded JSON objects, including nested objects and arrays
+func stripCost(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range costFields {
+			delete(v, key)
+		}
+		for _, nested := range v {
+			stripCost(nested)
+		}
+	case []interface{}:
+		for _, item := range v {
+			stripCost(item)
+		}
+	}
+}
+
 // HashIDResponse wraps the response data with hashed IDs
 type HashIDResponse map[string]interface{}
 
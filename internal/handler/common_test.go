@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// costResponses are the shapes in which handlers return cost, with the cost fields each carries
+func costResponses() []struct {
+	name   string
+	data   interface{}
+	fields []string
+} {
+	cost := money.FromInt(12000) + 50
+	product := entities.Product{ID: 1, Name: "Kopi", HargaJual: money.FromInt(20000), HargaModal: cost}
+
+	return []struct {
+		name   string
+		data   interface{}
+		fields []string
+	}{
+		{"product map", WithHashID(product.ID, "", "", product), []string{"harga_modal"}},
+		{"product map list", []HashIDResponse{WithHashID(product.ID, "", "", product)}, []string{"harga_modal"}},
+		{"product", &product, []string{"harga_modal"}},
+		{"product list", []entities.Product{product}, []string{"harga_modal"}},
+		{"transaction", &entities.Transaction{
+			Items: []entities.TransactionItem{{Product: product, Cost: cost}},
+		}, []string{"cost", "harga_modal"}},
+		{"purchase order", &entities.PurchaseOrder{
+			TotalCost: cost,
+			Items:     []entities.PurchaseOrderItem{{Product: product, UnitCost: cost}},
+			Receipts: []entities.GoodsReceipt{{Items: []entities.GoodsReceiptItem{{
+				UnitCost: cost, PreviousHargaModal: cost, NewHargaModal: cost,
+			}}}},
+		}, []string{"total_cost", "unit_cost", "harga_modal", "previous_harga_modal", "new_harga_modal"}},
+		{"outstanding purchase orders", []interfaces.OutstandingPurchaseOrders{{SupplierID: 1, OutstandingValue: cost}}, []string{"outstanding_value"}},
+		{"stock opname variance", &interfaces.StockOpnameVariance{
+			TotalVarianceValue: cost,
+			Items:              []interfaces.StockOpnameVarianceItem{{HargaModal: cost, VarianceValue: cost}},
+		}, []string{"total_variance_value", "harga_modal", "variance_value"}},
+		{"stock opname items", []entities.StockOpnameItem{{Product: product, VarianceValue: cost}}, []string{"variance_value", "harga_modal"}},
+		{"sales report", &interfaces.ReportResponse{
+			TotalCost:   cost,
+			GrossMargin: cost,
+			Details:     []interfaces.ReportDetail{{TotalCost: cost}},
+		}, []string{"total_cost", "gross_margin"}},
+	}
+}
+
+func TestSuccessResponseHidesCost(t *testing.T) {
+	for _, tt := range costResponses() {
+		t.Run(tt.name, func(t *testing.T) {
+			body := respond(t, entities.PermissionSet{}, tt.data)
+			if found := costKeys(body["data"]); len(found) > 0 {
+				t.Errorf("response reveals %v", found)
+			}
+		})
+	}
+}
+
+func TestSuccessResponseShowsCostWithPermission(t *testing.T) {
+	permissions := entities.NewPermissionSet([]string{entities.PermProductsViewCost})
+
+	for _, tt := range costResponses() {
+		t.Run(tt.name, func(t *testing.T) {
+			body := respond(t, permissions, tt.data)
+			found := costKeys(body["data"])
+			for _, field := range tt.fields {
+				if !slices.Contains(found, field) {
+					t.Errorf("response is missing %s, has %v", field, found)
+				}
+			}
+		})
+	}
+}
+
+func TestHideCostKeepsAmountFormat(t *testing.T) {
+	report := &interfaces.ReportResponse{TotalRevenue: money.FromInt(12000) + 50, TotalCost: money.FromInt(1)}
+
+	redacted, err := hideCost(report)
+	if err != nil {
+		t.Fatalf("hideCost returned error: %v", err)
+	}
+	out, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if !strings.Contains(string(out), `"total_revenue":12000.50`) {
+		t.Errorf("amount reformatted: %s", out)
+	}
+}
+
+// respond sends data through SuccessResponse for a caller with permissions and decodes the body
+func respond(t *testing.T, permissions entities.PermissionSet, data interface{}) map[string]interface{} {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.Set("permissions", permissions)
+
+	if err := SuccessResponse(c, http.StatusOK, "ok", data); err != nil {
+		t.Fatalf("SuccessResponse returned error: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return body
+}
+
+// costKeys returns the cost fields found at any depth of a decoded response
+func costKeys(value interface{}) []string {
+	var found []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if slices.Contains(costFields, key) {
+				found = append(found, key)
+			}
+			found = append(found, costKeys(nested)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			found = append(found, costKeys(item)...)
+		}
+	}
+	return found
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type CustomerHandler struct {
+	customerService interfaces.CustomerService
+	logger          *slog.Logger
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(customerService interfaces.CustomerService, logger *slog.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		logger:          logger,
+	}
+}
+
+// CreateCustomerRequest represents the create customer request
+type CreateCustomerRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Phone string `json:"phone" validate:"required"`
+	Email string `json:"email" validate:"omitempty,email"`
+	Notes string `json:"notes"`
+}
+
+// UpdateCustomerRequest represents the update customer request
+type UpdateCustomerRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Phone *string `json:"phone,omitempty" validate:"omitempty,min=1"`
+	Email *string `json:"email,omitempty" validate:"omitempty,email"`
+	Notes *string `json:"notes,omitempty"`
+}
+
+// CreateCustomer handles creating a customer
+// @Summary Create a customer
+// @Description Register a customer for the current tenant; the phone number must be unique
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body CreateCustomerRequest true "Create customer request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /customers [post]
+func (h *CustomerHandler) CreateCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateCustomerRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	customer := &entities.Customer{
+		Name:  req.Name,
+		Phone: req.Phone,
+		Email: req.Email,
+		Notes: req.Notes,
+	}
+
+	if err := h.customerService.CreateCustomer(ctx, customer); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create customer", "error", err)
+		return customerErrorResponse(c, err, "Failed to create customer")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Customer created successfully", customerResponse(customer))
+}
+
+// ListCustomers handles listing customers
+// @Summary List customers
+// @Description Get a paginated list of customers, optionally searched by name or phone
+// @Tags Customers
+// @Produce json
+// @Security bearerAuth
+// @Param q query string false "Search by name or phone"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /customers [get]
+func (h *CustomerHandler) ListCustomers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	customers, total, err := h.customerService.ListCustomers(ctx, c.QueryParam("q"), page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list customers", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list customers")
+	}
+
+	items := make([]HashIDResponse, len(customers))
+	for i := range customers {
+		items[i] = customerResponse(&customers[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Customers retrieved successfully", items, total, page, limit)
+}
+
+// GetCustomer handles getting a customer by ID
+// @Summary Get a customer
+// @Description Get a customer by ID
+// @Tags Customers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /customers/{id} [get]
+func (h *CustomerHandler) GetCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
+	}
+
+	customer, err := h.customerService.GetCustomer(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get customer", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Customer not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Customer retrieved successfully", customerResponse(customer))
+}
+
+// LookupPhone handles finding a customer by phone number
+// @Summary Look up a customer by phone
+// @Description Find the customer registered with a phone number; spaces and dashes are ignored
+// @Tags Customers
+// @Produce json
+// @Security bearerAuth
+// @Param phone path string true "Phone number"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Router /customers/phone/{phone} [get]
+func (h *CustomerHandler) LookupPhone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	customer, err := h.customerService.GetCustomerByPhone(ctx, c.Param("phone"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "customer phone lookup failed", "error", err)
+		return ErrorResponse(c, http.StatusNotFound, "Customer not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Customer retrieved successfully", customerResponse(customer))
+}
+
+// UpdateCustomer handles updating a customer
+// @Summary Update a customer
+// @Description Update the provided fields of a customer
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Customer ID"
+// @Param request body UpdateCustomerRequest true "Update customer request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /customers/{id} [put]
+func (h *CustomerHandler) UpdateCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
+	}
+
+	var req UpdateCustomerRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	customer, err := h.customerService.UpdateCustomer(ctx, id, updates)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update customer", "error", err, "id", id)
+		return customerErrorResponse(c, err, "Failed to update customer")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Customer updated successfully", customerResponse(customer))
+}
+
+// DeleteCustomer handles deleting a customer
+// @Summary Delete a customer
+// @Description Delete a customer. Their past sales are kept without the customer link.
+// @Tags Customers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /customers/{id} [delete]
+func (h *CustomerHandler) DeleteCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
+	}
+
+	if err := h.customerService.DeleteCustomer(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete customer", "error", err, "id", id)
+		return customerErrorResponse(c, err, "Failed to delete customer")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Customer deleted successfully", nil)
+}
+
+// ListCustomerTransactions handles listing a customer's purchase history
+// @Summary List customer transactions
+// @Description Get a customer's transactions, newest first
+// @Tags Customers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /customers/{id}/transactions [get]
+func (h *CustomerHandler) ListCustomerTransactions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	transactions, total, err := h.customerService.ListCustomerTransactions(ctx, id, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list customer transactions", "error", err, "id", id)
+		return customerErrorResponse(c, err, "Failed to list customer transactions")
+	}
+
+	items := make([]HashIDResponse, len(transactions))
+	for i := range transactions {
+		items[i] = transactionResponse(&transactions[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Customer transactions retrieved successfully", items, total, page, limit)
+}
+
+// decodeID decodes the hashed customer ID from the URL
+func (h *CustomerHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid customer ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// customerErrorResponse maps customer errors to HTTP status codes
+func customerErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Customer not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrCustomerPhoneTaken):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// customerResponse flattens a customer with hashed IDs for API responses
+func customerResponse(cu *entities.Customer) HashIDResponse {
+	return WithHashID(
+		cu.ID,
+		cu.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		cu.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":  cu.Name,
+			"phone": cu.Phone,
+			"email": cu.Email,
+			"notes": cu.Notes,
+		},
+	)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type LoyaltyHandler struct {
+	loyaltyService interfaces.LoyaltyService
+	logger         *slog.Logger
+}
+
+// NewLoyaltyHandler creates a new loyalty handler
+func NewLoyaltyHandler(loyaltyService interfaces.LoyaltyService, logger *slog.Logger) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+		logger:         logger,
+	}
+}
+
+// LoyaltyProgramRequest represents the loyalty program settings.
+// A sale earns one point per earn_spend of its total, multiplied by the customer's tier;
+// a point pays redeem_value when the customer pays with the "points" tender.
+type LoyaltyProgramRequest struct {
+	Enabled     bool                 `json:"enabled"`
+	EarnSpend   money.Money          `json:"earn_spend" validate:"required,min=1"`
+	RedeemValue money.Money          `json:"redeem_value" validate:"required,min=1"`
+	ExpiryDays  int                  `json:"expiry_days" validate:"min=0"`
+	Tiers       []LoyaltyTierRequest `json:"tiers" validate:"dive"`
+}
+
+// LoyaltyTierRequest represents a tier reached by earning min_points within a year
+type LoyaltyTierRequest struct {
+	Name           string  `json:"name" validate:"required"`
+	MinPoints      int     `json:"min_points" validate:"min=0"`
+	EarnMultiplier float64 `json:"earn_multiplier" validate:"required,gt=0"`
+}
+
+// GetProgram handles getting the loyalty program
+// @Summary Get the loyalty program
+// @Description Get the tenant's loyalty program settings and tiers
+// @Tags Loyalty
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Router /loyalty/program [get]
+func (h *LoyaltyHandler) GetProgram(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	program, err := h.loyaltyService.GetProgram(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get loyalty program", "error", err)
+		return loyaltyErrorResponse(c, err, "Failed to get loyalty program")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Loyalty program retrieved successfully", loyaltyProgramResponse(program))
+}
+
+// UpdateProgram handles setting up the loyalty program
+// @Summary Update the loyalty program
+// @Description Create or replace the tenant's loyalty program settings and tiers. Points already earned keep their expiry.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body LoyaltyProgramRequest true "Loyalty program request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /loyalty/program [put]
+func (h *LoyaltyHandler) UpdateProgram(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req LoyaltyProgramRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	program := &entities.LoyaltyProgram{
+		Enabled:     req.Enabled,
+		EarnSpend:   req.EarnSpend,
+		RedeemValue: req.RedeemValue,
+		ExpiryDays:  req.ExpiryDays,
+		Tiers:       make([]entities.LoyaltyTier, len(req.Tiers)),
+	}
+	for i, tier := range req.Tiers {
+		program.Tiers[i] = entities.LoyaltyTier{
+			Name:           tier.Name,
+			MinPoints:      tier.MinPoints,
+			EarnMultiplier: tier.EarnMultiplier,
+		}
+	}
+
+	updated, err := h.loyaltyService.UpdateProgram(ctx, program)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update loyalty program", "error", err)
+		return loyaltyErrorResponse(c, err, "Failed to update loyalty program")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Loyalty program updated successfully", loyaltyProgramResponse(updated))
+}
+
+// GetAccount handles getting a customer's points balance
+// @Summary Get a loyalty account
+// @Description Get the points balance, its value and the tier of the customer with a phone number
+// @Tags Loyalty
+// @Produce json
+// @Security bearerAuth
+// @Param phone path string true "Customer phone number"
+// @Success 200 {object} Response{data=interfaces.LoyaltyAccount}
+// @Failure 404 {object} Response
+// @Router /loyalty/accounts/{phone} [get]
+func (h *LoyaltyHandler) GetAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	account, err := h.loyaltyService.GetAccount(ctx, c.Param("phone"))
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get loyalty account", "error", err)
+		return loyaltyErrorResponse(c, err, "Failed to get loyalty account")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Loyalty account retrieved successfully", account)
+}
+
+// ListEntries handles listing a customer's points ledger
+// @Summary List loyalty ledger
+// @Description Get the points ledger of the customer with a phone number, newest first
+// @Tags Loyalty
+// @Produce json
+// @Security bearerAuth
+// @Param phone path string true "Customer phone number"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /loyalty/accounts/{phone}/ledger [get]
+func (h *LoyaltyHandler) ListEntries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	entries, total, err := h.loyaltyService.ListEntries(ctx, c.Param("phone"), page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list loyalty ledger", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list loyalty ledger")
+	}
+
+	items := make([]HashIDResponse, len(entries))
+	for i, e := range entries {
+		var transactionID interface{}
+		if e.TransactionID != nil {
+			transactionID = hash.HashID(*e.TransactionID)
+		}
+		items[i] = WithHashID(
+			e.ID,
+			e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			map[string]interface{}{
+				"phone":          e.Phone,
+				"transaction_id": transactionID,
+				"type":           e.Type,
+				"points":         e.Points,
+				"expires_at":     e.ExpiresAt,
+			},
+		)
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Loyalty ledger retrieved successfully", items, total, page, limit)
+}
+
+// loyaltyErrorResponse maps loyalty errors to HTTP status codes
+func loyaltyErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Loyalty program not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrInvalidLoyaltyProgram):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// loyaltyProgramResponse flattens a loyalty program with hashed IDs for API responses
+func loyaltyProgramResponse(p *entities.LoyaltyProgram) HashIDResponse {
+	tiers := make([]map[string]interface{}, len(p.Tiers))
+	for i, t := range p.Tiers {
+		tiers[i] = map[string]interface{}{
+			"id":              hash.HashID(t.ID),
+			"name":            t.Name,
+			"min_points":      t.MinPoints,
+			"earn_multiplier": t.EarnMultiplier,
+		}
+	}
+
+	return WithHashID(
+		p.ID,
+		p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"enabled":      p.Enabled,
+			"earn_spend":   p.EarnSpend,
+			"redeem_value": p.RedeemValue,
+			"expiry_days":  p.ExpiryDays,
+			"tiers":        tiers,
+		},
+	)
+}
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type ModifierHandler struct {
+	modifierService interfaces.ModifierService
+	logger          *slog.Logger
+}
+
+// NewModifierHandler creates a new modifier handler
+func NewModifierHandler(modifierService interfaces.ModifierService, logger *slog.Logger) *ModifierHandler {
+	return &ModifierHandler{
+		modifierService: modifierService,
+		logger:          logger,
+	}
+}
+
+// ModifierGroupRequest represents the create and update modifier group request.
+// A sale must select between min_select and max_select options; a max_select of 0 means no limit.
+type ModifierGroupRequest struct {
+	Name      string                  `json:"name" validate:"required"`
+	MinSelect int                     `json:"min_select" validate:"min=0"`
+	MaxSelect int                     `json:"max_select" validate:"min=0"`
+	Options   []ModifierOptionRequest `json:"options" validate:"required,min=1,dive"`
+}
+
+// ModifierOptionRequest represents a modifier option; send id when updating an existing option
+type ModifierOptionRequest struct {
+	ID    string      `json:"id,omitempty"`
+	Name  string      `json:"name" validate:"required"`
+	Price money.Money `json:"price" validate:"min=0"`
+}
+
+// SetProductModifierGroupsRequest represents the modifier groups offered on a product
+type SetProductModifierGroupsRequest struct {
+	GroupIDs []string `json:"group_ids"`
+}
+
+// CreateModifierGroup handles creating a modifier group
+// @Summary Create a modifier group
+// @Description Create a modifier group such as "Sugar level" or "Extra topping" with its options and selection rules
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body ModifierGroupRequest true "Create modifier group request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /modifier-groups [post]
+func (h *ModifierHandler) CreateModifierGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req ModifierGroupRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	group, err := modifierGroupFromRequest(req)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid modifier option ID format", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier option ID format")
+	}
+
+	if err := h.modifierService.CreateModifierGroup(ctx, group); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create modifier group", "error", err)
+		return modifierErrorResponse(c, err, "Failed to create modifier group")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Modifier group created successfully", modifierGroupResponse(group))
+}
+
+// ListModifierGroups handles listing modifier groups
+// @Summary List modifier groups
+// @Description Get all modifier groups of the tenant with their options
+// @Tags Modifiers
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /modifier-groups [get]
+func (h *ModifierHandler) ListModifierGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	groups, err := h.modifierService.ListModifierGroups(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list modifier groups", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list modifier groups")
+	}
+
+	items := make([]HashIDResponse, len(groups))
+	for i := range groups {
+		items[i] = modifierGroupResponse(&groups[i])
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Modifier groups retrieved successfully", items)
+}
+
+// GetModifierGroup handles getting a modifier group by ID
+// @Summary Get a modifier group
+// @Description Get a modifier group and its options by ID
+// @Tags Modifiers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Modifier group ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /modifier-groups/{id} [get]
+func (h *ModifierHandler) GetModifierGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID format")
+	}
+
+	group, err := h.modifierService.GetModifierGroup(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get modifier group", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Modifier group not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Modifier group retrieved successfully", modifierGroupResponse(group))
+}
+
+// UpdateModifierGroup handles updating a modifier group
+// @Summary Update a modifier group
+// @Description Replace a modifier group's rules and options. Options sent with their id are updated, options without id are added and missing options are removed.
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Modifier group ID"
+// @Param request body ModifierGroupRequest true "Update modifier group request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /modifier-groups/{id} [put]
+func (h *ModifierHandler) UpdateModifierGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID format")
+	}
+
+	var req ModifierGroupRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	group, err := modifierGroupFromRequest(req)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid modifier option ID format", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier option ID format")
+	}
+
+	updated, err := h.modifierService.UpdateModifierGroup(ctx, id, group)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update modifier group", "error", err, "id", id)
+		return modifierErrorResponse(c, err, "Failed to update modifier group")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Modifier group updated successfully", modifierGroupResponse(updated))
+}
+
+// DeleteModifierGroup handles deleting a modifier group
+// @Summary Delete a modifier group
+// @Description Delete a modifier group and remove it from every product. Past sales keep their modifier snapshots.
+// @Tags Modifiers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Modifier group ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /modifier-groups/{id} [delete]
+func (h *ModifierHandler) DeleteModifierGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID format")
+	}
+
+	if err := h.modifierService.DeleteModifierGroup(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete modifier group", "error", err, "id", id)
+		return modifierErrorResponse(c, err, "Failed to delete modifier group")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Modifier group deleted successfully", nil)
+}
+
+// SetProductModifierGroups handles choosing the modifier groups offered on a product
+// @Summary Set product modifier groups
+// @Description Replace the modifier groups offered on a product. Variants also offer the groups of their parent product.
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param request body SetProductModifierGroupsRequest true "Set product modifier groups request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /products/{id}/modifier-groups [put]
+func (h *ModifierHandler) SetProductModifierGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hashedID := c.Param("id")
+	productID, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	var req SetProductModifierGroupsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	groupIDs := make([]uint, len(req.GroupIDs))
+	for i, hashedGroupID := range req.GroupIDs {
+		groupIDs[i], err = hash.DecodeHashID(hashedGroupID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid modifier group ID format", "error", err, "hashed_id", hashedGroupID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID format")
+		}
+	}
+
+	product, err := h.modifierService.SetProductModifierGroups(ctx, productID, groupIDs)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to set product modifier groups", "error", err, "product_id", productID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Product or modifier group not found")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to set product modifier groups")
+	}
+
+	response := WithHashID(
+		product.ID,
+		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":            product.Name,
+			"sku":             product.SKU,
+			"modifier_groups": productModifierGroups(product),
+		},
+	)
+
+	return SuccessResponse(c, http.StatusOK, "Product modifier groups updated successfully", response)
+}
+
+// modifierGroupFromRequest converts a modifier group request, decoding hashed option IDs
+func modifierGroupFromRequest(req ModifierGroupRequest) (*entities.ModifierGroup, error) {
+	group := &entities.ModifierGroup{
+		Name:      req.Name,
+		MinSelect: req.MinSelect,
+		MaxSelect: req.MaxSelect,
+		Options:   make([]entities.ModifierOption, len(req.Options)),
+	}
+	for i, option := range req.Options {
+		group.Options[i] = entities.ModifierOption{Name: option.Name, Price: option.Price}
+		if option.ID == "" {
+			continue
+		}
+		optionID, err := hash.DecodeHashID(option.ID)
+		if err != nil {
+			return nil, err
+		}
+		group.Options[i].ID = optionID
+	}
+	return group, nil
+}
+
+// decodeID decodes the hashed modifier group ID from the URL
+func (h *ModifierHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid modifier group ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// modifierErrorResponse maps modifier group errors to HTTP status codes
+func modifierErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Modifier group not found")
+	case errors.Is(err, entities.ErrInvalidModifierGroup):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// modifierGroupResponse flattens a modifier group with hashed IDs for API responses
+func modifierGroupResponse(g *entities.ModifierGroup) HashIDResponse {
+	options := make([]map[string]interface{}, len(g.Options))
+	for i, o := range g.Options {
+		options[i] = map[string]interface{}{
+			"id":    hash.HashID(o.ID),
+			"name":  o.Name,
+			"price": o.Price,
+		}
+	}
+
+	return WithHashID(
+		g.ID,
+		g.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		g.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":       g.Name,
+			"min_select": g.MinSelect,
+			"max_select": g.MaxSelect,
+			"options":    options,
+		},
+	)
+}
+
+// productModifierGroups lists the modifier groups offered on a product
+func productModifierGroups(p *entities.Product) []HashIDResponse {
+	groups := make([]HashIDResponse, len(p.ModifierGroups))
+	for i := range p.ModifierGroups {
+		groups[i] = modifierGroupResponse(&p.ModifierGroups[i])
+	}
+	return groups
+}
@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type OrderHandler struct {
+	transactionService interfaces.TransactionService
+	logger             *slog.Logger
+}
+
+// NewOrderHandler creates a new open order handler
+func NewOrderHandler(transactionService interfaces.TransactionService, logger *slog.Logger) *OrderHandler {
+	return &OrderHandler{
+		transactionService: transactionService,
+		logger:             logger,
+	}
+}
+
+// OpenOrderRequest represents the open order request. Items may be left empty and added later.
+type OpenOrderRequest struct {
+	User  string                   `json:"user" validate:"required"`
+	Label string                   `json:"label"` // table number or name, e.g. "Table 5"
+	Notes string                   `json:"notes"`
+	Items []TransactionItemRequest `json:"items" validate:"dive"`
+}
+
+// UpdateOrderRequest represents the request to assign an order to a table or label
+type UpdateOrderRequest struct {
+	Label string `json:"label"`
+	Notes string `json:"notes"`
+}
+
+// SettleOrderRequest represents the payment that closes an open order.
+// total_price must match the order's lines after promotions, discount, service charge, tax and rounding.
+type SettleOrderRequest struct {
+	PaymentMethod string           `json:"payment_method"`
+	Discount      float64          `json:"discount"`
+	TotalPrice    money.Money      `json:"total_price" validate:"min=0"`
+	Payments      []PaymentRequest `json:"payments" validate:"dive"`
+	VoucherCode   string           `json:"voucher_code"`
+	CustomerID    string           `json:"customer_id,omitempty"`
+	CustomerPhone string           `json:"customer_phone"`
+}
+
+// OpenOrder handles opening an order
+// @Summary Open an order
+// @Description Open an unpaid order such as a table's tab. Stock is deducted when the order is settled.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body OpenOrderRequest true "Open order request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /orders [post]
+func (h *OrderHandler) OpenOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req OpenOrderRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.OpenOrderRequest{
+		User:  req.User,
+		Label: req.Label,
+		Notes: req.Notes,
+		Items: make([]interfaces.TransactionItemRequest, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		decoded, err := decodeItemRequest(item)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid item ID format", "error", err)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid product or modifier ID format")
+		}
+		serviceReq.Items[i] = decoded
+	}
+
+	order, err := h.transactionService.OpenOrder(ctx, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to open order", "error", err)
+		return orderErrorResponse(c, err, "Failed to open order")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Order opened successfully", transactionResponse(order))
+}
+
+// ListOrders handles listing unsettled orders
+// @Summary List orders
+// @Description Get a paginated list of orders, oldest first. Without status, open and held orders are listed.
+// @Tags Orders
+// @Produce json
+// @Security bearerAuth
+// @Param status query string false "Order status" Enums(open, held, cancelled)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /orders [get]
+func (h *OrderHandler) ListOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	orders, total, err := h.transactionService.ListOrders(ctx, c.QueryParam("status"), page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list orders", "error", err)
+		if errors.Is(err, entities.ErrInvalidTransactionState) {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid order status")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list orders")
+	}
+
+	items := make([]HashIDResponse, len(orders))
+	for i := range orders {
+		items[i] = transactionResponse(&orders[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Orders retrieved successfully", items, total, page, limit)
+}
+
+// UpdateOrder handles assigning an order to a table or label
+// @Summary Update an order
+// @Description Set the table or label and notes of an open or held order
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Param request body UpdateOrderRequest true "Update order request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id} [put]
+func (h *OrderHandler) UpdateOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	var req UpdateOrderRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	order, err := h.transactionService.UpdateOrder(ctx, id, interfaces.UpdateOrderRequest{
+		Label: req.Label,
+		Notes: req.Notes,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update order", "error", err, "id", id)
+		return orderErrorResponse(c, err, "Failed to update order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order updated successfully", transactionResponse(order))
+}
+
+// AddOrderItem handles adding a line to an order
+// @Summary Add an order item
+// @Description Add a line to an open order at the current product and modifier prices
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Param request body TransactionItemRequest true "Order item request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/items [post]
+func (h *OrderHandler) AddOrderItem(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	var req TransactionItemRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	item, err := decodeItemRequest(req)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid item ID format", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product or modifier ID format")
+	}
+
+	order, err := h.transactionService.AddOrderItem(ctx, id, item)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to add order item", "error", err, "id", id)
+		return orderErrorResponse(c, err, "Failed to add order item")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order item added successfully", transactionResponse(order))
+}
+
+// RemoveOrderItem handles removing a line from an order
+// @Summary Remove an order item
+// @Description Remove a line from an open order
+// @Tags Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Param itemId path string true "Order item ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/items/{itemId} [delete]
+func (h *OrderHandler) RemoveOrderItem(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	hashedItemID := c.Param("itemId")
+	itemID, err := hash.DecodeHashID(hashedItemID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid item ID format", "error", err, "hashed_id", hashedItemID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid item ID format")
+	}
+
+	order, err := h.transactionService.RemoveOrderItem(ctx, id, itemID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to remove order item", "error", err, "id", id, "item_id", itemID)
+		return orderErrorResponse(c, err, "Failed to remove order item")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order item removed successfully", transactionResponse(order))
+}
+
+// HoldOrder handles parking an order
+// @Summary Hold an order
+// @Description Park an open order; it cannot be changed or settled until it is resumed
+// @Tags Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/hold [post]
+func (h *OrderHandler) HoldOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	order, err := h.transactionService.HoldOrder(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to hold order", "error", err, "id", id)
+		return orderErrorResponse(c, err, "Failed to hold order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order held successfully", transactionResponse(order))
+}
+
+// ResumeOrder handles reopening a held order
+// @Summary Resume an order
+// @Description Reopen a held order
+// @Tags Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/resume [post]
+func (h *OrderHandler) ResumeOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	order, err := h.transactionService.ResumeOrder(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to resume order", "error", err, "id", id)
+		return orderErrorResponse(c, err, "Failed to resume order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order resumed successfully", transactionResponse(order))
+}
+
+// CancelOrder handles discarding an order
+// @Summary Cancel an order
+// @Description Discard an open or held order. No stock was deducted, so none is restored.
+// @Tags Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	order, err := h.transactionService.CancelOrder(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to cancel order", "error", err, "id", id)
+		return orderErrorResponse(c, err, "Failed to cancel order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order cancelled successfully", transactionResponse(order))
+}
+
+// SettleOrder handles paying for an order
+// @Summary Settle an order
+// @Description Take payment for an open order. Its lines are checked out like a new transaction: promotions, vouchers, tax, loyalty points and stock deduction all apply.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Order ID"
+// @Param request body SettleOrderRequest true "Settle order request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /orders/{id}/settle [post]
+func (h *OrderHandler) SettleOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format")
+	}
+
+	var req SettleOrderRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.SettleOrderRequest{
+		PaymentMethod: req.PaymentMethod,
+		Discount:      req.Discount,
+		TotalPrice:    req.TotalPrice,
+		VoucherCode:   req.VoucherCode,
+		CustomerPhone: req.CustomerPhone,
+		Payments:      make([]interfaces.PaymentRequest, len(req.Payments)),
+	}
+
+	if req.CustomerID != "" {
+		customerID, err := hash.DecodeHashID(req.CustomerID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid customer ID format", "error", err, "hashed_id", req.CustomerID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
+		}
+		serviceReq.CustomerID = &customerID
+	}
+
+	for i, p := range req.Payments {
+		serviceReq.Payments[i] = interfaces.PaymentRequest{
+			Method:          p.Method,
+			Amount:          p.Amount,
+			ReferenceNumber: p.ReferenceNumber,
+		}
+	}
+
+	order, err := h.transactionService.SettleOrder(ctx, id, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to settle order", "error", err, "id", id)
+		if errors.Is(err, entities.ErrInsufficientStock) || errors.Is(err, entities.ErrVoucherExhausted) || errors.Is(err, entities.ErrInsufficientPoints) {
+			return ErrorResponse(c, http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, entities.ErrProductHasVariants) || errors.Is(err, entities.ErrInvalidModifier) ||
+			errors.Is(err, entities.ErrInvalidVoucher) || errors.Is(err, entities.ErrInvalidPointsPayment) {
+			return ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return orderErrorResponse(c, err, "Failed to settle order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Order settled successfully", transactionResponse(order))
+}
+
+// decodeID decodes the hashed order ID from the URL
+func (h *OrderHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid order ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// decodeItemRequest decodes the hashed product and modifier IDs of an item request.
+// Items scanned by barcode are resolved by the service.
+func decodeItemRequest(item TransactionItemRequest) (interfaces.TransactionItemRequest, error) {
+	decoded := interfaces.TransactionItemRequest{
+		Barcode:   item.Barcode,
+		Quantity:  item.Quantity,
+		Modifiers: make([]uint, len(item.Modifiers)),
+	}
+	for i, hashedID := range item.Modifiers {
+		optionID, err := hash.DecodeHashID(hashedID)
+		if err != nil {
+			return decoded, fmt.Errorf("modifier %s: %w", hashedID, err)
+		}
+		decoded.Modifiers[i] = optionID
+	}
+	if item.Barcode != "" {
+		return decoded, nil
+	}
+
+	productID, err := hash.DecodeHashID(item.ProductID)
+	if err != nil {
+		return decoded, fmt.Errorf("product %s: %w", item.ProductID, err)
+	}
+	decoded.ProductID = productID
+	return decoded, nil
+}
+
+// orderErrorResponse maps open order errors to HTTP status codes
+func orderErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrInvalidTransactionState):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrTransactionItemNotFound):
+		return ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrProductHasVariants), errors.Is(err, entities.ErrInvalidModifier):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
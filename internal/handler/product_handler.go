@@ -1,16 +1,20 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
 )
 
 type ProductHandler struct {
@@ -28,10 +32,15 @@ func NewProductHandler(productService interfaces.ProductService, logger *slog.Lo
 
 // UpdateProductRequest represents the update product request
 type UpdateProductRequest struct {
-	Name       *string  `json:"name,omitempty"`
-	SKU        *string  `json:"sku,omitempty"`
-	HargaModal *float64 `json:"harga_modal,omitempty"`
-	HargaJual  *float64 `json:"harga_jual,omitempty"`
+	Name         *string      `json:"name,omitempty"`
+	SKU          *string      `json:"sku,omitempty"`
+	HargaModal   *money.Money `json:"harga_modal,omitempty"`
+	HargaJual    *money.Money `json:"harga_jual,omitempty"`
+	ReorderPoint *int         `json:"reorder_point,omitempty" validate:"omitempty,min=0"`
+	ReorderQty   *int         `json:"reorder_qty,omitempty" validate:"omitempty,min=0"`
+	TaxExempt    *bool        `json:"tax_exempt,omitempty"`
+	CategoryID   *string      `json:"category_id,omitempty"` // empty string clears the category
+	Tags         *[]string    `json:"tags,omitempty"`
 }
 
 // UpdateStockRequest represents the update stock request
@@ -41,12 +50,47 @@ type UpdateStockRequest struct {
 
 // CreateProductRequest represents the create product request
 type CreateProductRequest struct {
-	Name       string  `json:"name" validate:"required"`
-	SKU        string  `json:"sku" validate:"required"`
-	Image      string  `json:"image,omitempty"`
-	HargaModal float64 `json:"harga_modal" validate:"required,min=0"`
-	HargaJual  float64 `json:"harga_jual" validate:"required,min=0"`
-	Stock      int     `json:"stock" validate:"required,min=0"`
+	Name         string      `json:"name" validate:"required"`
+	SKU          string      `json:"sku" validate:"required"`
+	Image        string      `json:"image,omitempty"`
+	HargaModal   money.Money `json:"harga_modal" validate:"required,min=0"`
+	HargaJual    money.Money `json:"harga_jual" validate:"required,min=0"`
+	Stock        int         `json:"stock" validate:"required,min=0"`
+	ReorderPoint int         `json:"reorder_point" validate:"min=0"`
+	ReorderQty   int         `json:"reorder_qty" validate:"min=0"`
+	TaxExempt    bool        `json:"tax_exempt"`
+	CategoryID   string      `json:"category_id,omitempty"`
+	Tags         []string    `json:"tags,omitempty"`
+}
+
+// AddBarcodeRequest represents the add product barcode request
+type AddBarcodeRequest struct {
+	Code       string `json:"code" validate:"required,max=64"`
+	Multiplier int    `json:"multiplier" validate:"omitempty,min=1"` // units per scan, defaults to 1
+}
+
+// GenerateVariantsRequest represents the generate product variants request
+type GenerateVariantsRequest struct {
+	Options []ProductOptionRequest `json:"options" validate:"required,min=1,dive"`
+}
+
+// ProductOptionRequest represents a variant dimension such as size or colour
+type ProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required"`
+	Values []string `json:"values" validate:"required,min=1,dive,required"`
+}
+
+// SetRecipeRequest represents the set product recipe request.
+// An empty component list removes the recipe.
+type SetRecipeRequest struct {
+	Components     []RecipeComponentRequest `json:"components" validate:"dive"`
+	DeductOwnStock bool                     `json:"deduct_own_stock"`
+}
+
+// RecipeComponentRequest represents one component line of a recipe
+type RecipeComponentRequest struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
 }
 
 // GetUploadURLRequest represents the request for getting an upload URL
@@ -54,16 +98,26 @@ type GetUploadURLRequest struct {
 	Extension string `json:"extension" validate:"required"`
 }
 
-// ListProducts handles listing products with pagination
+// ListProducts handles listing products with filtering, sorting and pagination
 // @Summary List all products
-// @Description Get a paginated list of products
+// @Description Get a paginated list of products, optionally filtered and sorted
 // @Tags Products
 // @Produce json
 // @Security bearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Match product name (contains) or SKU (prefix)"
+// @Param category_id query string false "Category ID; includes subcategories"
+// @Param tag query string false "Tag name"
+// @Param min_price query string false "Minimum selling price"
+// @Param max_price query string false "Maximum selling price"
+// @Param in_stock query bool false "Only products with stock above zero"
+// @Param sort_by query string false "Sort field; harga_modal needs the products.view_cost permission" Enums(name, sku, harga_jual, harga_modal, stock, created_at) default(name)
+// @Param sort_dir query string false "Sort direction" Enums(asc, desc) default(asc)
 // @Success 200 {object} Response{data=PaginatedResponse[HashIDResponse]}
+// @Failure 400 {object} Response
 // @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Router /products [get]
 func (h *ProductHandler) ListProducts(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -78,7 +132,53 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 		limit = 10
 	}
 
-	products, total, err := h.productService.ListProducts(ctx, page, limit)
+	query := interfaces.ProductListQuery{
+		Page:    page,
+		Limit:   limit,
+		Search:  strings.TrimSpace(c.QueryParam("search")),
+		Tag:     strings.TrimSpace(c.QueryParam("tag")),
+		SortBy:  c.QueryParam("sort_by"),
+		SortDir: strings.ToLower(c.QueryParam("sort_dir")),
+	}
+
+	if query.SortBy == "harga_modal" && !canViewCost(c) {
+		return ErrorResponse(c, http.StatusForbidden, "Permission denied")
+	}
+
+	if hashedID := c.QueryParam("category_id"); hashedID != "" {
+		categoryID, err := hash.DecodeHashID(hashedID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid category ID format", "error", err, "hashed_id", hashedID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+		}
+		query.CategoryID = &categoryID
+	}
+
+	if v := c.QueryParam("min_price"); v != "" {
+		minPrice, err := money.Parse(v)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid min_price")
+		}
+		query.MinPrice = &minPrice
+	}
+
+	if v := c.QueryParam("max_price"); v != "" {
+		maxPrice, err := money.Parse(v)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid max_price")
+		}
+		query.MaxPrice = &maxPrice
+	}
+
+	if v := c.QueryParam("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid in_stock")
+		}
+		query.InStockOnly = inStock
+	}
+
+	products, total, err := h.productService.ListProducts(ctx, query)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to list products", "error", err)
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list products")
@@ -101,12 +201,17 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 			p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			map[string]interface{}{
-				"name":        p.Name,
-				"sku":         p.SKU,
-				"image_url":   imageURL,
-				"harga_modal": p.HargaModal,
-				"harga_jual":  p.HargaJual,
-				"stock":       p.Stock,
+				"name":          p.Name,
+				"sku":           p.SKU,
+				"image_url":     imageURL,
+				"harga_modal":   p.HargaModal,
+				"harga_jual":    p.HargaJual,
+				"stock":         p.TotalStock(),
+				"reorder_point": p.ReorderPoint,
+				"reorder_qty":   p.ReorderQty,
+				"category_id":   productCategoryID(&p),
+				"tags":          productTagNames(&p),
+				"variants":      productVariants(&p),
 			},
 		)
 	}
@@ -122,6 +227,63 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 	)
 }
 
+// ListLowStockProducts handles listing products at or below their reorder point
+// @Summary List low-stock products
+// @Description Get a paginated list of products whose stock is at or below reorder_point, lowest first
+// @Tags Products
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse[HashIDResponse]}
+// @Failure 401 {object} Response
+// @Router /products/low-stock [get]
+func (h *ProductHandler) ListLowStockProducts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	products, total, err := h.productService.ListLowStockProducts(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list low stock products", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list low stock products")
+	}
+
+	items := make([]HashIDResponse, len(products))
+	for i, p := range products {
+		items[i] = WithHashID(
+			p.ID,
+			p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			map[string]interface{}{
+				"name":          p.Name,
+				"sku":           p.SKU,
+				"stock":         p.Stock,
+				"reorder_point": p.ReorderPoint,
+				"reorder_qty":   p.ReorderQty,
+			},
+		)
+	}
+
+	return SuccessPaginatedResponse(
+		c,
+		http.StatusOK,
+		"Low stock products retrieved successfully",
+		items,
+		total,
+		page,
+		limit,
+	)
+}
+
 // GetProduct handles getting a single product by ID
 // @Summary Get a product by ID
 // @Description Get detailed information about a specific product
@@ -166,12 +328,26 @@ func (h *ProductHandler) GetProduct(c echo.Context) error {
 		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"name":        product.Name,
-			"sku":         product.SKU,
-			"image_url":   imageURL,
-			"harga_modal": product.HargaModal,
-			"harga_jual":  product.HargaJual,
-			"stock":       product.Stock,
+			"name":             product.Name,
+			"sku":              product.SKU,
+			"image_url":        imageURL,
+			"harga_modal":      product.HargaModal,
+			"harga_jual":       product.HargaJual,
+			"stock":            product.TotalStock(),
+			"reorder_point":    product.ReorderPoint,
+			"reorder_qty":      product.ReorderQty,
+			"tax_exempt":       product.TaxExempt,
+			"category_id":      productCategoryID(product),
+			"tags":             productTagNames(product),
+			"barcodes":         productBarcodes(product),
+			"parent_id":        productParentID(product),
+			"variant_name":     product.VariantName,
+			"options":          productOptions(product),
+			"variants":         productVariants(product),
+			"components":       productComponents(product),
+			"deduct_own_stock": product.DeductOwnStock,
+			"unit_cost":        product.UnitCost(),
+			"modifier_groups":  productModifierGroups(product),
 		},
 	)
 
@@ -210,6 +386,11 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
 	// Convert to updates map
 	updates := make(map[string]interface{})
 	if req.Name != nil {
@@ -224,10 +405,36 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 	if req.HargaJual != nil {
 		updates["harga_jual"] = *req.HargaJual
 	}
+	if req.ReorderPoint != nil {
+		updates["reorder_point"] = *req.ReorderPoint
+	}
+	if req.ReorderQty != nil {
+		updates["reorder_qty"] = *req.ReorderQty
+	}
+	if req.TaxExempt != nil {
+		updates["tax_exempt"] = *req.TaxExempt
+	}
+	if req.CategoryID != nil {
+		var categoryID *uint
+		if *req.CategoryID != "" {
+			decoded, err := hash.DecodeHashID(*req.CategoryID)
+			if err != nil {
+				return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+			}
+			categoryID = &decoded
+		}
+		updates["category_id"] = categoryID
+	}
+	if req.Tags != nil {
+		updates["tags"] = *req.Tags
+	}
 
 	product, err := h.productService.UpdateProduct(ctx, id, updates)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to update product", "error", err, "id", id)
+		if errors.Is(err, entities.ErrForbidden) {
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to update product")
 	}
 
@@ -245,12 +452,17 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"name":        product.Name,
-			"sku":         product.SKU,
-			"image_url":   imageURL,
-			"harga_modal": product.HargaModal,
-			"harga_jual":  product.HargaJual,
-			"stock":       product.Stock,
+			"name":          product.Name,
+			"sku":           product.SKU,
+			"image_url":     imageURL,
+			"harga_modal":   product.HargaModal,
+			"harga_jual":    product.HargaJual,
+			"stock":         product.Stock,
+			"reorder_point": product.ReorderPoint,
+			"reorder_qty":   product.ReorderQty,
+			"tax_exempt":    product.TaxExempt,
+			"category_id":   productCategoryID(product),
+			"tags":          productTagNames(product),
 		},
 	)
 
@@ -297,6 +509,12 @@ func (h *ProductHandler) UpdateStock(c echo.Context) error {
 	product, err := h.productService.UpdateStock(ctx, id, req.Stock)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to update stock", "error", err, "id", id)
+		switch {
+		case errors.Is(err, entities.ErrProductHasVariants):
+			return ErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entities.ErrForbidden):
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to update stock")
 	}
 
@@ -314,13 +532,18 @@ func (h *ProductHandler) UpdateStock(c echo.Context) error {
 		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"name":        product.Name,
-			"sku":         product.SKU,
-			"image":       product.Image,
-			"image_url":   imageURL,
-			"harga_modal": product.HargaModal,
-			"harga_jual":  product.HargaJual,
-			"stock":       product.Stock,
+			"name":          product.Name,
+			"sku":           product.SKU,
+			"image":         product.Image,
+			"image_url":     imageURL,
+			"harga_modal":   product.HargaModal,
+			"harga_jual":    product.HargaJual,
+			"stock":         product.Stock,
+			"reorder_point": product.ReorderPoint,
+			"reorder_qty":   product.ReorderQty,
+			"tax_exempt":    product.TaxExempt,
+			"category_id":   productCategoryID(product),
+			"tags":          productTagNames(product),
 		},
 	)
 
@@ -355,12 +578,26 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 
 	// Create product entity
 	product := &entities.Product{
-		Name:       req.Name,
-		SKU:        req.SKU,
-		Image:      req.Image,
-		HargaModal: req.HargaModal,
-		HargaJual:  req.HargaJual,
-		Stock:      req.Stock,
+		Name:         req.Name,
+		SKU:          req.SKU,
+		Image:        req.Image,
+		HargaModal:   req.HargaModal,
+		HargaJual:    req.HargaJual,
+		Stock:        req.Stock,
+		ReorderPoint: req.ReorderPoint,
+		ReorderQty:   req.ReorderQty,
+		TaxExempt:    req.TaxExempt,
+	}
+
+	if req.CategoryID != "" {
+		categoryID, err := hash.DecodeHashID(req.CategoryID)
+		if err != nil {
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid category ID format")
+		}
+		product.CategoryID = &categoryID
+	}
+	for _, name := range req.Tags {
+		product.Tags = append(product.Tags, entities.Tag{Name: name})
 	}
 
 	// Set tenant_id from context
@@ -370,6 +607,9 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 
 	if err := h.productService.CreateProduct(ctx, product); err != nil {
 		h.logger.ErrorContext(ctx, "failed to create product", "error", err)
+		if errors.Is(err, entities.ErrForbidden) {
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to create product")
 	}
 
@@ -378,12 +618,17 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"name":        product.Name,
-			"sku":         product.SKU,
-			"image":       product.Image,
-			"harga_modal": product.HargaModal,
-			"harga_jual":  product.HargaJual,
-			"stock":       product.Stock,
+			"name":          product.Name,
+			"sku":           product.SKU,
+			"image":         product.Image,
+			"harga_modal":   product.HargaModal,
+			"harga_jual":    product.HargaJual,
+			"stock":         product.Stock,
+			"reorder_point": product.ReorderPoint,
+			"reorder_qty":   product.ReorderQty,
+			"tax_exempt":    product.TaxExempt,
+			"category_id":   productCategoryID(product),
+			"tags":          productTagNames(product),
 		},
 	)
 
@@ -520,12 +765,17 @@ func (h *ProductHandler) UploadProductImage(c echo.Context) error {
 		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"name":        product.Name,
-			"sku":         product.SKU,
-			"image_url":   imageURL,
-			"harga_modal": product.HargaModal,
-			"harga_jual":  product.HargaJual,
-			"stock":       product.Stock,
+			"name":          product.Name,
+			"sku":           product.SKU,
+			"image_url":     imageURL,
+			"harga_modal":   product.HargaModal,
+			"harga_jual":    product.HargaJual,
+			"stock":         product.Stock,
+			"reorder_point": product.ReorderPoint,
+			"reorder_qty":   product.ReorderQty,
+			"tax_exempt":    product.TaxExempt,
+			"category_id":   productCategoryID(product),
+			"tags":          productTagNames(product),
 		},
 	)
 
@@ -571,3 +821,487 @@ func (h *ProductHandler) GetProductImageBytes(c echo.Context) error {
 	// Write image bytes to response
 	return c.Blob(http.StatusOK, contentType, imageBytes)
 }
+
+// GetStockHistory handles listing the stock ledger of a product
+// @Summary Get product stock history
+// @Description Get a paginated list of stock movements for a product, newest first
+// @Tags Products
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /products/{id}/stock-history [get]
+func (h *ProductHandler) GetStockHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get hashed ID from URL
+	hashedID := c.Param("id")
+
+	// Decode hashed ID to get the actual ID
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	movements, total, err := h.productService.GetStockHistory(ctx, id, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get stock history", "error", err, "product_id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Product not found")
+	}
+
+	items := make([]HashIDResponse, len(movements))
+	for i, m := range movements {
+		data := map[string]interface{}{
+			"product_id":   hash.HashID(m.ProductID),
+			"delta":        m.Delta,
+			"balance":      m.Balance,
+			"reason":       m.Reason,
+			"reference_id": nil,
+			"user_id":      nil,
+		}
+		if m.ReferenceID != nil {
+			data["reference_id"] = hash.HashID(*m.ReferenceID)
+		}
+		if m.UserID != nil {
+			data["user_id"] = hash.HashID(*m.UserID)
+		}
+
+		items[i] = WithHashID(
+			m.ID,
+			m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			data,
+		)
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Stock history retrieved successfully", items, total, page, limit)
+}
+
+// ListTags handles listing the product tags of the tenant
+// @Summary List product tags
+// @Description Get all product tags of the tenant, ordered by name
+// @Tags Products
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /products/tags [get]
+func (h *ProductHandler) ListTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tags, err := h.productService.ListTags(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list tags", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list tags")
+	}
+
+	items := make([]HashIDResponse, len(tags))
+	for i, t := range tags {
+		items[i] = WithHashID(
+			t.ID,
+			t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			map[string]interface{}{
+				"name": t.Name,
+			},
+		)
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Tags retrieved successfully", items)
+}
+
+// LookupBarcode handles finding a product by a scanned barcode
+// @Summary Look up a product by barcode
+// @Description Find the product for a scanned EAN/UPC code, falling back to the SKU. quantity is the number of units one scan represents.
+// @Tags Products
+// @Produce json
+// @Security bearerAuth
+// @Param code path string true "Scanned barcode"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Router /products/barcode/{code} [get]
+func (h *ProductHandler) LookupBarcode(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	code := c.Param("code")
+
+	product, quantity, err := h.productService.LookupBarcode(ctx, code)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to look up barcode", "error", err, "code", code)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Product not found")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to look up barcode")
+	}
+
+	// Get presigned image URL if image exists
+	imageURL := ""
+	if product.Image != "" {
+		imageURL, err = h.productService.GetProductImageURL(ctx, product)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get image URL", "error", err, "product_id", product.ID)
+		}
+	}
+
+	response := WithHashID(
+		product.ID,
+		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":        product.Name,
+			"sku":         product.SKU,
+			"image_url":   imageURL,
+			"harga_jual":  product.HargaJual,
+			"stock":       product.Stock,
+			"barcode":     code,
+			"quantity":    quantity,
+			"category_id": productCategoryID(product),
+		},
+	)
+
+	return SuccessResponse(c, http.StatusOK, "Product retrieved successfully", response)
+}
+
+// AddBarcode handles registering a barcode for a product
+// @Summary Add a product barcode
+// @Description Register a scannable code for a product; pack barcodes set multiplier to the units per pack
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param request body AddBarcodeRequest true "Add barcode request"
+// @Success 201 {object} Response{data=map[string]interface{}}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /products/{id}/barcodes [post]
+func (h *ProductHandler) AddBarcode(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get hashed ID from URL
+	hashedID := c.Param("id")
+
+	// Decode hashed ID to get the actual ID
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	var req AddBarcodeRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	barcode := &entities.ProductBarcode{
+		Code:       strings.TrimSpace(req.Code),
+		Multiplier: req.Multiplier,
+	}
+
+	if err := h.productService.AddBarcode(ctx, id, barcode); err != nil {
+		h.logger.ErrorContext(ctx, "failed to add barcode", "error", err, "product_id", id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return ErrorResponse(c, http.StatusNotFound, "Product not found")
+		case errors.Is(err, entities.ErrBarcodeTaken):
+			return ErrorResponse(c, http.StatusConflict, err.Error())
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to add barcode")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Barcode added successfully", barcodeResponse(barcode))
+}
+
+// RemoveBarcode handles deleting a barcode from a product
+// @Summary Remove a product barcode
+// @Description Delete a registered barcode from a product
+// @Tags Products
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param barcodeId path string true "Barcode ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /products/{id}/barcodes/{barcodeId} [delete]
+func (h *ProductHandler) RemoveBarcode(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := hash.DecodeHashID(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", c.Param("id"))
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	barcodeID, err := hash.DecodeHashID(c.Param("barcodeId"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid barcode ID format", "error", err, "hashed_id", c.Param("barcodeId"))
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid barcode ID format")
+	}
+
+	if err := h.productService.RemoveBarcode(ctx, id, barcodeID); err != nil {
+		h.logger.ErrorContext(ctx, "failed to remove barcode", "error", err, "product_id", id, "barcode_id", barcodeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Barcode not found")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to remove barcode")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Barcode removed successfully", nil)
+}
+
+// GenerateVariants handles creating variants from option dimensions
+// @Summary Generate product variants
+// @Description Set the option dimensions (e.g. size, colour) of a product and create a variant for every missing combination. Variants have their own SKU, prices, stock and image and are sold instead of the parent.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param request body GenerateVariantsRequest true "Generate variants request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /products/{id}/variants [post]
+func (h *ProductHandler) GenerateVariants(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get hashed ID from URL
+	hashedID := c.Param("id")
+
+	// Decode hashed ID to get the actual ID
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	var req GenerateVariantsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	options := make([]interfaces.ProductOptionInput, len(req.Options))
+	for i, o := range req.Options {
+		options[i] = interfaces.ProductOptionInput{Name: o.Name, Values: o.Values}
+	}
+
+	product, err := h.productService.GenerateVariants(ctx, id, options)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to generate variants", "error", err, "product_id", id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Product not found")
+		}
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	response := WithHashID(
+		product.ID,
+		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":     product.Name,
+			"sku":      product.SKU,
+			"stock":    product.TotalStock(),
+			"options":  productOptions(product),
+			"variants": productVariants(product),
+		},
+	)
+
+	return SuccessResponse(c, http.StatusOK, "Variants generated successfully", response)
+}
+
+// SetRecipe handles replacing the recipe of a composite product
+// @Summary Set product recipe
+// @Description Define the component products and quantities one unit consumes. Sales then deduct component stock (and the product's own stock when deduct_own_stock is set); cost is the summed component harga_modal.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Product ID"
+// @Param request body SetRecipeRequest true "Set recipe request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /products/{id}/recipe [put]
+func (h *ProductHandler) SetRecipe(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get hashed ID from URL
+	hashedID := c.Param("id")
+
+	// Decode hashed ID to get the actual ID
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	var req SetRecipeRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	components := make([]interfaces.ProductComponentInput, len(req.Components))
+	for i, component := range req.Components {
+		componentID, err := hash.DecodeHashID(component.ProductID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid component ID format", "error", err, "hashed_id", component.ProductID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid component product ID format")
+		}
+		components[i] = interfaces.ProductComponentInput{ProductID: componentID, Quantity: component.Quantity}
+	}
+
+	product, err := h.productService.SetRecipe(ctx, id, components, req.DeductOwnStock)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to set recipe", "error", err, "product_id", id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Product not found")
+		}
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	response := WithHashID(
+		product.ID,
+		product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":             product.Name,
+			"sku":              product.SKU,
+			"harga_jual":       product.HargaJual,
+			"components":       productComponents(product),
+			"deduct_own_stock": product.DeductOwnStock,
+			"unit_cost":        product.UnitCost(),
+		},
+	)
+
+	return SuccessResponse(c, http.StatusOK, "Recipe updated successfully", response)
+}
+
+// productComponents returns the recipe lines of a composite product
+func productComponents(p *entities.Product) []map[string]interface{} {
+	components := make([]map[string]interface{}, len(p.Components))
+	for i, c := range p.Components {
+		component := map[string]interface{}{
+			"product_id": hash.HashID(c.ComponentID),
+			"quantity":   c.Quantity,
+		}
+		if c.Component != nil {
+			component["name"] = c.Component.Name
+			component["sku"] = c.Component.SKU
+			component["harga_modal"] = c.Component.HargaModal
+			component["stock"] = c.Component.Stock
+		}
+		components[i] = component
+	}
+	return components
+}
+
+// productParentID returns the hashed parent ID of a variant, or nil for top-level products
+func productParentID(p *entities.Product) interface{} {
+	if p.ParentID == nil {
+		return nil
+	}
+	return hash.HashID(*p.ParentID)
+}
+
+// productOptions returns the option dimensions of a parent product
+func productOptions(p *entities.Product) []map[string]interface{} {
+	options := make([]map[string]interface{}, len(p.Options))
+	for i, o := range p.Options {
+		values := make([]string, len(o.Values))
+		for j, v := range o.Values {
+			values[j] = v.Value
+		}
+		options[i] = map[string]interface{}{
+			"name":   o.Name,
+			"values": values,
+		}
+	}
+	return options
+}
+
+// productVariants returns the variants of a parent product
+func productVariants(p *entities.Product) []map[string]interface{} {
+	variants := make([]map[string]interface{}, len(p.Variants))
+	for i, v := range p.Variants {
+		variants[i] = map[string]interface{}{
+			"id":           hash.HashID(v.ID),
+			"sku":          v.SKU,
+			"variant_name": v.VariantName,
+			"harga_modal":  v.HargaModal,
+			"harga_jual":   v.HargaJual,
+			"stock":        v.Stock,
+		}
+	}
+	return variants
+}
+
+// productCategoryID returns the hashed category ID of a product, or nil when uncategorised
+func productCategoryID(p *entities.Product) interface{} {
+	if p.CategoryID == nil {
+		return nil
+	}
+	return hash.HashID(*p.CategoryID)
+}
+
+// productTagNames returns the tag names of a product
+func productTagNames(p *entities.Product) []string {
+	names := make([]string, len(p.Tags))
+	for i, t := range p.Tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// productBarcodes returns the registered barcodes of a product
+func productBarcodes(p *entities.Product) []map[string]interface{} {
+	barcodes := make([]map[string]interface{}, len(p.Barcodes))
+	for i := range p.Barcodes {
+		barcodes[i] = barcodeResponse(&p.Barcodes[i])
+	}
+	return barcodes
+}
+
+// barcodeResponse flattens a product barcode with hashed IDs for API responses
+func barcodeResponse(b *entities.ProductBarcode) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         hash.HashID(b.ID),
+		"code":       b.Code,
+		"multiplier": b.Multiplier,
+	}
+}
@@ -0,0 +1,514 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type PromotionHandler struct {
+	promotionService interfaces.PromotionService
+	logger           *slog.Logger
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(promotionService interfaces.PromotionService, logger *slog.Logger) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+		logger:           logger,
+	}
+}
+
+// PromotionRequest represents the create and update promotion request.
+// buy_x_get_y uses buy_qty, get_qty and an optional discount_percent (free when 0); bundle uses buy_qty
+// and bundle_price; item_discount uses discount_amount or discount_percent per unit; min_spend uses
+// min_spend with discount_amount or discount_percent off the basket. An empty product_ids applies to
+// every product. A code turns the promotion into a voucher entered at checkout, and voucher_only
+// promotions only apply through their generated vouchers. daily_start/daily_end ("15:04") and
+// days ("1,2,3,4,5", 0 is Sunday) limit it to recurring windows such as happy hours.
+type PromotionRequest struct {
+	Name            string      `json:"name" validate:"required"`
+	Type            string      `json:"type" validate:"required,oneof=buy_x_get_y bundle item_discount min_spend"`
+	Code            string      `json:"code,omitempty"`
+	Active          *bool       `json:"active,omitempty"`
+	VoucherOnly     bool        `json:"voucher_only"`
+	ProductIDs      []string    `json:"product_ids"`
+	BuyQty          int         `json:"buy_qty" validate:"min=0"`
+	GetQty          int         `json:"get_qty" validate:"min=0"`
+	BundlePrice     money.Money `json:"bundle_price" validate:"min=0"`
+	DiscountAmount  money.Money `json:"discount_amount" validate:"min=0"`
+	DiscountPercent float64     `json:"discount_percent" validate:"min=0,max=100"`
+	MinSpend        money.Money `json:"min_spend" validate:"min=0"`
+	StartsAt        *time.Time  `json:"starts_at,omitempty"`
+	EndsAt          *time.Time  `json:"ends_at,omitempty"`
+	DailyStart      string      `json:"daily_start,omitempty"`
+	DailyEnd        string      `json:"daily_end,omitempty"`
+	Days            string      `json:"days,omitempty"`
+}
+
+// CreatePromotion handles creating a promotion
+// @Summary Create a promotion
+// @Description Create a buy-X-get-Y, bundle, item discount or minimum-spend promotion, optionally as a voucher or limited to a schedule
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body PromotionRequest true "Create promotion request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions [post]
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	if err := h.promotionService.CreatePromotion(ctx, promotion); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create promotion", "error", err)
+		return promotionErrorResponse(c, err, "Failed to create promotion")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Promotion created successfully", promotionResponse(promotion))
+}
+
+// ListPromotions handles listing promotions
+// @Summary List promotions
+// @Description Get a paginated list of the tenant's promotions, newest first
+// @Tags Promotions
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /promotions [get]
+func (h *PromotionHandler) ListPromotions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	promotions, total, err := h.promotionService.ListPromotions(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list promotions", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list promotions")
+	}
+
+	items := make([]HashIDResponse, len(promotions))
+	for i := range promotions {
+		items[i] = promotionResponse(&promotions[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Promotions retrieved successfully", items, total, page, limit)
+}
+
+// GetPromotion handles getting a promotion by ID
+// @Summary Get a promotion
+// @Description Get a promotion and its products by ID
+// @Tags Promotions
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	promotion, err := h.promotionService.GetPromotion(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get promotion", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Promotion not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Promotion retrieved successfully", promotionResponse(promotion))
+}
+
+// UpdatePromotion handles updating a promotion
+// @Summary Update a promotion
+// @Description Replace a promotion's rules, schedule and products. Past sales keep the discounts they were given.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Param request body PromotionRequest true "Update promotion request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	var req PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid product ID format", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+	}
+
+	updated, err := h.promotionService.UpdatePromotion(ctx, id, promotion)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update promotion", "error", err, "id", id)
+		return promotionErrorResponse(c, err, "Failed to update promotion")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Promotion updated successfully", promotionResponse(updated))
+}
+
+// DeletePromotion handles deleting a promotion
+// @Summary Delete a promotion
+// @Description Delete a promotion. Past sales keep the discounts it gave and reports still show them.
+// @Tags Promotions
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	if err := h.promotionService.DeletePromotion(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete promotion", "error", err, "id", id)
+		return promotionErrorResponse(c, err, "Failed to delete promotion")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Promotion deleted successfully", nil)
+}
+
+// GenerateVouchersRequest represents a bulk voucher generation request.
+// max_uses of 1 makes single-use codes and 0 unlimited; max_uses_per_customer is counted by customer phone.
+type GenerateVouchersRequest struct {
+	Count              int         `json:"count" validate:"required,min=1,max=1000"`
+	Prefix             string      `json:"prefix" validate:"omitempty,max=8,alphanum"`
+	Length             int         `json:"length" validate:"omitempty,min=6,max=16"`
+	MaxUses            int         `json:"max_uses" validate:"min=0"`
+	MaxUsesPerCustomer int         `json:"max_uses_per_customer" validate:"min=0"`
+	MinBasket          money.Money `json:"min_basket" validate:"min=0"`
+	ExpiresAt          *time.Time  `json:"expires_at,omitempty"`
+}
+
+// GenerateVouchers handles bulk-generating voucher codes for a promotion
+// @Summary Generate vouchers
+// @Description Generate unique voucher codes that redeem a voucher_only promotion, with usage limits, a minimum basket and an expiry
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Param request body GenerateVouchersRequest true "Generate vouchers request"
+// @Success 201 {object} Response{data=[]HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id}/vouchers [post]
+func (h *PromotionHandler) GenerateVouchers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	var req GenerateVouchersRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	vouchers, err := h.promotionService.GenerateVouchers(ctx, id, interfaces.GenerateVouchersRequest{
+		Count:              req.Count,
+		Prefix:             req.Prefix,
+		Length:             req.Length,
+		MaxUses:            req.MaxUses,
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+		MinBasket:          req.MinBasket,
+		ExpiresAt:          req.ExpiresAt,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to generate vouchers", "error", err, "promotion_id", id)
+		return promotionErrorResponse(c, err, "Failed to generate vouchers")
+	}
+
+	items := make([]HashIDResponse, len(vouchers))
+	for i := range vouchers {
+		items[i] = voucherResponse(&vouchers[i])
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Vouchers generated successfully", items)
+}
+
+// ListVouchers handles listing the vouchers of a promotion
+// @Summary List vouchers
+// @Description Get a paginated list of a promotion's vouchers with their usage
+// @Tags Promotions
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id}/vouchers [get]
+func (h *PromotionHandler) ListVouchers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	vouchers, total, err := h.promotionService.ListVouchers(ctx, id, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list vouchers", "error", err, "promotion_id", id)
+		return promotionErrorResponse(c, err, "Failed to list vouchers")
+	}
+
+	items := make([]HashIDResponse, len(vouchers))
+	for i := range vouchers {
+		items[i] = voucherResponse(&vouchers[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Vouchers retrieved successfully", items, total, page, limit)
+}
+
+// ExportVouchers handles exporting the vouchers of a promotion as CSV
+// @Summary Export vouchers
+// @Description Download every voucher of a promotion as a CSV file for printing or distribution
+// @Tags Promotions
+// @Produce text/csv
+// @Security bearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {file} file
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /promotions/{id}/vouchers/export [get]
+func (h *PromotionHandler) ExportVouchers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid promotion ID format")
+	}
+
+	vouchers, err := h.promotionService.ExportVouchers(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to export vouchers", "error", err, "promotion_id", id)
+		return promotionErrorResponse(c, err, "Failed to export vouchers")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"code", "max_uses", "max_uses_per_customer", "used_count", "min_basket", "expires_at", "active"})
+	for _, v := range vouchers {
+		expiresAt := ""
+		if v.ExpiresAt != nil {
+			expiresAt = v.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		_ = w.Write([]string{
+			v.Code,
+			strconv.Itoa(v.MaxUses),
+			strconv.Itoa(v.MaxUsesPerCustomer),
+			strconv.Itoa(v.UsedCount),
+			v.MinBasket.String(),
+			expiresAt,
+			strconv.FormatBool(v.Active),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.ErrorContext(ctx, "failed to write voucher export", "error", err, "promotion_id", id)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to export vouchers")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"vouchers-%s.csv\"", hash.HashID(id)))
+	return c.Blob(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// promotionFromRequest converts a promotion request, decoding hashed product IDs
+func promotionFromRequest(req PromotionRequest) (*entities.Promotion, error) {
+	promotion := &entities.Promotion{
+		Name:            req.Name,
+		Type:            req.Type,
+		Code:            req.Code,
+		Active:          req.Active == nil || *req.Active,
+		VoucherOnly:     req.VoucherOnly,
+		Products:        make([]entities.Product, len(req.ProductIDs)),
+		BuyQty:          req.BuyQty,
+		GetQty:          req.GetQty,
+		BundlePrice:     req.BundlePrice,
+		DiscountAmount:  req.DiscountAmount,
+		DiscountPercent: req.DiscountPercent,
+		MinSpend:        req.MinSpend,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		DailyStart:      req.DailyStart,
+		DailyEnd:        req.DailyEnd,
+		Days:            req.Days,
+	}
+	for i, hashedID := range req.ProductIDs {
+		productID, err := hash.DecodeHashID(hashedID)
+		if err != nil {
+			return nil, err
+		}
+		promotion.Products[i] = entities.Product{ID: productID}
+	}
+	return promotion, nil
+}
+
+// decodeID decodes the hashed promotion ID from the URL
+func (h *PromotionHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid promotion ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// promotionErrorResponse maps promotion errors to HTTP status codes
+func promotionErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Promotion or product not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrInvalidPromotion):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// promotionResponse flattens a promotion with hashed IDs for API responses
+func promotionResponse(p *entities.Promotion) HashIDResponse {
+	products := make([]map[string]interface{}, len(p.Products))
+	for i, product := range p.Products {
+		products[i] = map[string]interface{}{
+			"id":   hash.HashID(product.ID),
+			"name": product.Name,
+			"sku":  product.SKU,
+		}
+	}
+
+	return WithHashID(
+		p.ID,
+		p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":             p.Name,
+			"type":             p.Type,
+			"code":             p.Code,
+			"active":           p.Active,
+			"voucher_only":     p.VoucherOnly,
+			"products":         products,
+			"buy_qty":          p.BuyQty,
+			"get_qty":          p.GetQty,
+			"bundle_price":     p.BundlePrice,
+			"discount_amount":  p.DiscountAmount,
+			"discount_percent": p.DiscountPercent,
+			"min_spend":        p.MinSpend,
+			"starts_at":        p.StartsAt,
+			"ends_at":          p.EndsAt,
+			"daily_start":      p.DailyStart,
+			"daily_end":        p.DailyEnd,
+			"days":             p.Days,
+		},
+	)
+}
+
+// voucherResponse flattens a voucher with hashed IDs for API responses
+func voucherResponse(v *entities.Voucher) HashIDResponse {
+	return WithHashID(
+		v.ID,
+		v.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		v.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"promotion_id":          hash.HashID(v.PromotionID),
+			"code":                  v.Code,
+			"max_uses":              v.MaxUses,
+			"max_uses_per_customer": v.MaxUsesPerCustomer,
+			"used_count":            v.UsedCount,
+			"min_basket":            v.MinBasket,
+			"expires_at":            v.ExpiresAt,
+			"active":                v.Active,
+		},
+	)
+}
@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderService interfaces.PurchaseOrderService
+	logger               *slog.Logger
+}
+
+// NewPurchaseOrderHandler creates a new purchase order handler
+func NewPurchaseOrderHandler(purchaseOrderService interfaces.PurchaseOrderService, logger *slog.Logger) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		purchaseOrderService: purchaseOrderService,
+		logger:               logger,
+	}
+}
+
+// CreatePurchaseOrderRequest represents the create purchase order request
+type CreatePurchaseOrderRequest struct {
+	SupplierID string                     `json:"supplier_id" validate:"required"`
+	ExpectedAt *time.Time                 `json:"expected_at,omitempty"`
+	Notes      string                     `json:"notes"`
+	Items      []PurchaseOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderItemRequest represents an ordered product
+type PurchaseOrderItemRequest struct {
+	ProductID string      `json:"product_id" validate:"required"`
+	Quantity  int         `json:"quantity" validate:"required,min=1"`
+	UnitCost  money.Money `json:"unit_cost" validate:"min=0"`
+}
+
+// ReceiveGoodsRequest represents a delivery received against a purchase order
+type ReceiveGoodsRequest struct {
+	Notes      string               `json:"notes"`
+	UpdateCost bool                 `json:"update_cost"`
+	Items      []ReceiveItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// ReceiveItemRequest represents a received quantity of a purchase order item
+type ReceiveItemRequest struct {
+	ItemID   string       `json:"item_id" validate:"required"`
+	Quantity int          `json:"quantity" validate:"required,min=1"`
+	UnitCost *money.Money `json:"unit_cost,omitempty"`
+}
+
+// CreatePurchaseOrder handles creating a purchase order
+// @Summary Create a purchase order
+// @Description Order products from a supplier
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body CreatePurchaseOrderRequest true "Create purchase order request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreatePurchaseOrderRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	supplierID, err := hash.DecodeHashID(req.SupplierID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid supplier ID format", "error", err, "hashed_id", req.SupplierID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID format")
+	}
+
+	serviceReq := interfaces.CreatePurchaseOrderRequest{
+		SupplierID: supplierID,
+		ExpectedAt: req.ExpectedAt,
+		Notes:      req.Notes,
+		Items:      make([]interfaces.PurchaseOrderItemRequest, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		productID, err := hash.DecodeHashID(item.ProductID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", item.ProductID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+		}
+		serviceReq.Items[i] = interfaces.PurchaseOrderItemRequest{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		}
+	}
+
+	order, err := h.purchaseOrderService.CreatePurchaseOrder(ctx, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create purchase order", "error", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, err.Error())
+		}
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Purchase order created successfully", purchaseOrderResponse(order))
+}
+
+// ListPurchaseOrders handles listing purchase orders
+// @Summary List purchase orders
+// @Description Get a paginated list of purchase orders, newest first
+// @Tags Purchase Orders
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	orders, total, err := h.purchaseOrderService.ListPurchaseOrders(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list purchase orders", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list purchase orders")
+	}
+
+	items := make([]HashIDResponse, len(orders))
+	for i := range orders {
+		items[i] = purchaseOrderResponse(&orders[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Purchase orders retrieved successfully", items, total, page, limit)
+}
+
+// GetPurchaseOrder handles getting a purchase order by ID
+// @Summary Get a purchase order
+// @Description Get a purchase order with its items and goods receipts
+// @Tags Purchase Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID format")
+	}
+
+	order, err := h.purchaseOrderService.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get purchase order", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Purchase order not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Purchase order retrieved successfully", purchaseOrderResponse(order))
+}
+
+// ReceiveGoods handles receiving a delivery against a purchase order
+// @Summary Receive goods
+// @Description Increment stock for delivered items. With update_cost, harga_modal becomes the weighted average cost.
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Purchase order ID"
+// @Param request body ReceiveGoodsRequest true "Receive goods request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceiveGoods(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID format")
+	}
+
+	var req ReceiveGoodsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.ReceiveGoodsRequest{
+		Notes:      req.Notes,
+		UpdateCost: req.UpdateCost,
+		Items:      make([]interfaces.ReceiveItemRequest, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		itemID, err := hash.DecodeHashID(item.ItemID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid purchase order item ID format", "error", err, "hashed_id", item.ItemID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order item ID format")
+		}
+		serviceReq.Items[i] = interfaces.ReceiveItemRequest{
+			PurchaseOrderItemID: itemID,
+			Quantity:            item.Quantity,
+			UnitCost:            item.UnitCost,
+		}
+	}
+
+	order, err := h.purchaseOrderService.ReceiveGoods(ctx, id, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to receive goods", "error", err, "id", id)
+		return purchaseOrderErrorResponse(c, err, "Failed to receive goods")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Goods received successfully", purchaseOrderResponse(order))
+}
+
+// CancelPurchaseOrder handles cancelling a purchase order
+// @Summary Cancel a purchase order
+// @Description Close a purchase order; quantities not yet received are no longer expected
+// @Tags Purchase Orders
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID format")
+	}
+
+	order, err := h.purchaseOrderService.CancelPurchaseOrder(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to cancel purchase order", "error", err, "id", id)
+		return purchaseOrderErrorResponse(c, err, "Failed to cancel purchase order")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Purchase order cancelled successfully", purchaseOrderResponse(order))
+}
+
+// GetOutstandingReport handles the outstanding purchase orders report
+// @Summary Outstanding purchase orders per supplier
+// @Description Ordered quantities and cost not yet received, grouped by supplier
+// @Tags Purchase Orders
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]interfaces.OutstandingPurchaseOrders}
+// @Failure 500 {object} Response
+// @Router /purchase-orders/outstanding [get]
+func (h *PurchaseOrderHandler) GetOutstandingReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	results, err := h.purchaseOrderService.GetOutstandingReport(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get outstanding purchase orders", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to get outstanding purchase orders")
+	}
+
+	suppliers := make([]map[string]interface{}, len(results))
+	for i, r := range results {
+		suppliers[i] = map[string]interface{}{
+			"supplier_id":       hash.HashID(r.SupplierID),
+			"supplier_name":     r.SupplierName,
+			"open_orders":       r.OpenOrders,
+			"outstanding_qty":   r.OutstandingQty,
+			"outstanding_value": r.OutstandingValue,
+		}
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Outstanding purchase orders retrieved successfully", suppliers)
+}
+
+// decodeID decodes the hashed purchase order ID from the URL
+func (h *PurchaseOrderHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid purchase order ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// purchaseOrderErrorResponse maps purchase order errors to HTTP status codes
+func purchaseOrderErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, entities.ErrPurchaseOrderItemNotFound):
+		return ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrPurchaseOrderClosed):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrInvalidReceiveQuantity):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// purchaseOrderResponse flattens a purchase order with hashed IDs for API responses
+func purchaseOrderResponse(o *entities.PurchaseOrder) HashIDResponse {
+	items := make([]map[string]interface{}, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = map[string]interface{}{
+			"id":              hash.HashID(item.ID),
+			"product_id":      hash.HashID(item.ProductID),
+			"product_name":    item.Product.Name,
+			"sku":             item.Product.SKU,
+			"quantity":        item.Quantity,
+			"received_qty":    item.ReceivedQty,
+			"outstanding_qty": item.OutstandingQty(),
+			"unit_cost":       item.UnitCost,
+		}
+	}
+
+	receipts := make([]map[string]interface{}, len(o.Receipts))
+	for i, receipt := range o.Receipts {
+		receiptItems := make([]map[string]interface{}, len(receipt.Items))
+		for j, item := range receipt.Items {
+			receiptItems[j] = map[string]interface{}{
+				"item_id":              hash.HashID(item.PurchaseOrderItemID),
+				"product_id":           hash.HashID(item.ProductID),
+				"quantity":             item.Quantity,
+				"unit_cost":            item.UnitCost,
+				"previous_harga_modal": item.PreviousHargaModal,
+				"new_harga_modal":      item.NewHargaModal,
+			}
+		}
+		receipts[i] = map[string]interface{}{
+			"id":          hash.HashID(receipt.ID),
+			"notes":       receipt.Notes,
+			"user_id":     hash.HashID(receipt.UserID),
+			"items":       receiptItems,
+			"received_at": receipt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	return WithHashID(
+		o.ID,
+		o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"supplier_id":   hash.HashID(o.SupplierID),
+			"supplier_name": o.Supplier.Name,
+			"status":        o.Status,
+			"expected_at":   o.ExpectedAt,
+			"notes":         o.Notes,
+			"total_cost":    o.TotalCost,
+			"created_by":    hash.HashID(o.CreatedBy),
+			"items":         items,
+			"receipts":      receipts,
+		},
+	)
+}
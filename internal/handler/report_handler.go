@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 )
 
@@ -75,6 +77,9 @@ func (h *ReportHandler) GetSalesReport(c echo.Context) error {
 	report, err := h.reportService.GetSalesReport(ctx, startDate, endDate)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to get sales report", "error", err)
+		if errors.Is(err, entities.ErrForbidden) {
+			return ErrorResponse(c, http.StatusForbidden, err.Error())
+		}
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to get sales report")
 	}
 
@@ -86,19 +91,45 @@ func (h *ReportHandler) GetSalesReport(c echo.Context) error {
 			"", // No created_at for report details
 			"", // No updated_at for report details
 			map[string]interface{}{
-				"product_id":   detail.ProductID,
-				"product_name": detail.ProductName,
-				"total":        detail.Total,
-				"total_price":  detail.TotalPrice,
+				"product_id":          detail.ProductID,
+				"product_name":        detail.ProductName,
+				"parent_product_id":   detail.ParentProductID,
+				"parent_product_name": detail.ParentProductName,
+				"total":               detail.Total,
+				"total_price":         detail.TotalPrice,
+				"total_cost":          detail.TotalCost,
+				"gross_margin":        detail.TotalPrice - detail.TotalCost,
 			},
 		)
 	}
 
+	// Per-product totals with variants rolled up into their parent
+	products := make([]map[string]interface{}, len(report.Products))
+	for i, p := range report.Products {
+		products[i] = map[string]interface{}{
+			"product_id":   p.ProductID,
+			"product_name": p.ProductName,
+			"total":        p.Total,
+			"total_price":  p.TotalPrice,
+			"total_cost":   p.TotalCost,
+			"gross_margin": p.TotalPrice - p.TotalCost,
+		}
+	}
+
 	response := map[string]interface{}{
-		"total_revenue":       report.TotalRevenue,
-		"items_sold":          report.ItemsSold,
-		"average_transaction": report.AverageTransaction,
-		"details":             details,
+		"total_revenue":        report.TotalRevenue,
+		"total_cost":           report.TotalCost,
+		"gross_margin":         report.GrossMargin,
+		"total_tax":            report.TotalTax,
+		"total_service_charge": report.TotalServiceCharge,
+		"items_sold":           report.ItemsSold,
+		"average_transaction":  report.AverageTransaction,
+		"details":              details,
+		"products":             products,
+		"payments":             report.Payments,
+		"modifiers":            report.Modifiers,
+		"taxes":                report.Taxes,
+		"promotions":           report.Promotions,
 	}
 
 	return SuccessResponse(c, http.StatusOK, "Sales report retrieved successfully", response)
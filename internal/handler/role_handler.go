@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type RoleHandler struct {
+	roleService interfaces.RoleService
+	logger      *slog.Logger
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleService interfaces.RoleService, logger *slog.Logger) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+		logger:      logger,
+	}
+}
+
+// RoleRequest represents the create or update custom role request
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required"`
+}
+
+// ListRoles handles listing roles
+// @Summary List roles
+// @Description Get the built-in roles (owner, manager, cashier, stock_clerk) and the tenant's custom roles with their permissions
+// @Tags Roles
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]interfaces.RoleSummary}
+// @Failure 403 {object} Response
+// @Router /roles [get]
+func (h *RoleHandler) ListRoles(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	roles, err := h.roleService.ListRoles(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list roles", "error", err)
+		return roleErrorResponse(c, err, "Failed to list roles")
+	}
+
+	items := make([]map[string]interface{}, len(roles))
+	for i, role := range roles {
+		var id interface{}
+		if role.ID != nil {
+			id = hash.HashID(*role.ID)
+		}
+		items[i] = map[string]interface{}{
+			"id":          id,
+			"name":        role.Name,
+			"description": role.Description,
+			"built_in":    role.BuiltIn,
+			"permissions": role.Permissions,
+		}
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Roles retrieved successfully", items)
+}
+
+// ListPermissions handles listing the permissions roles can be granted
+// @Summary List permissions
+// @Description Get every permission a custom role can be granted
+// @Tags Roles
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]string}
+// @Router /roles/permissions [get]
+func (h *RoleHandler) ListPermissions(c echo.Context) error {
+	return SuccessResponse(c, http.StatusOK, "Permissions retrieved successfully", entities.AllPermissions)
+}
+
+// GetRole handles getting a custom role by ID
+// @Summary Get a custom role
+// @Description Get a custom role by ID
+// @Tags Roles
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /roles/{id} [get]
+func (h *RoleHandler) GetRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid role ID format")
+	}
+
+	role, err := h.roleService.GetRole(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get role", "error", err, "id", id)
+		return roleErrorResponse(c, err, "Failed to get role")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Role retrieved successfully", roleResponse(role))
+}
+
+// CreateRole handles creating a custom role
+// @Summary Create a custom role
+// @Description Create a tenant role with a chosen set of permissions. Names must be lowercase letters, digits and underscores and cannot reuse a built-in role. The role cannot grant permissions the caller lacks.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body RoleRequest true "Create role request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req RoleRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	role, err := h.roleService.CreateRole(ctx, interfaces.RoleRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create role", "error", err)
+		return roleErrorResponse(c, err, "Failed to create role")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Role created successfully", roleResponse(role))
+}
+
+// UpdateRole handles updating a custom role
+// @Summary Update a custom role
+// @Description Rename a custom role or replace its permissions. Users holding the role follow a rename. The role cannot grant permissions the caller lacks.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Role ID"
+// @Param request body RoleRequest true "Update role request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid role ID format")
+	}
+
+	var req RoleRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	role, err := h.roleService.UpdateRole(ctx, id, interfaces.RoleRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update role", "error", err, "id", id)
+		return roleErrorResponse(c, err, "Failed to update role")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Role updated successfully", roleResponse(role))
+}
+
+// DeleteRole handles deleting a custom role
+// @Summary Delete a custom role
+// @Description Delete a custom role that is not assigned to any user
+// @Tags Roles
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid role ID format")
+	}
+
+	if err := h.roleService.DeleteRole(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete role", "error", err, "id", id)
+		return roleErrorResponse(c, err, "Failed to delete role")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Role deleted successfully", nil)
+}
+
+// decodeID decodes the hashed role ID from the URL
+func (h *RoleHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid role ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// roleErrorResponse maps role errors to HTTP status codes
+func roleErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Role not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrInvalidRole):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entities.ErrRoleNameTaken), errors.Is(err, entities.ErrRoleInUse):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// roleResponse flattens a custom role with hashed IDs for API responses
+func roleResponse(r *entities.Role) HashIDResponse {
+	return WithHashID(
+		r.ID,
+		r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		r.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":        r.Name,
+			"description": r.Description,
+			"built_in":    false,
+			"permissions": r.PermissionNames(),
+		},
+	)
+}
@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+type ShiftHandler struct {
+	shiftService interfaces.ShiftService
+	logger       *slog.Logger
+}
+
+// NewShiftHandler creates a new shift handler
+func NewShiftHandler(shiftService interfaces.ShiftService, logger *slog.Logger) *ShiftHandler {
+	return &ShiftHandler{
+		shiftService: shiftService,
+		logger:       logger,
+	}
+}
+
+// OpenShiftRequest represents the open shift request
+type OpenShiftRequest struct {
+	OpeningFloat money.Money `json:"opening_float" validate:"min=0"`
+	Notes        string      `json:"notes"`
+}
+
+// CashEventRequest represents cash put into or taken out of the drawer
+type CashEventRequest struct {
+	Amount money.Money `json:"amount" validate:"required,gt=0"`
+	Reason string      `json:"reason" validate:"required"`
+}
+
+// CloseShiftRequest represents the closing count of a shift.
+// Use counts for tenders other than cash, such as card terminal settlement totals.
+type CloseShiftRequest struct {
+	CountedCash money.Money          `json:"counted_cash" validate:"min=0"`
+	Counts      []TenderCountRequest `json:"counts" validate:"dive"`
+	Notes       string               `json:"notes"`
+}
+
+// TenderCountRequest represents the amount counted for one tender
+type TenderCountRequest struct {
+	Method string      `json:"method" validate:"required"`
+	Amount money.Money `json:"amount" validate:"min=0"`
+}
+
+// OpenShift handles opening a shift
+// @Summary Open a shift
+// @Description Open a shift for the logged-in user with the starting cash float. Sales and refunds the user makes are linked to it until it is closed.
+// @Tags Shifts
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body OpenShiftRequest true "Open shift request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /shifts [post]
+func (h *ShiftHandler) OpenShift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req OpenShiftRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	shift, err := h.shiftService.OpenShift(ctx, interfaces.OpenShiftRequest{
+		OpeningFloat: req.OpeningFloat,
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to open shift", "error", err)
+		return shiftErrorResponse(c, err, "Failed to open shift")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Shift opened successfully", shiftResponse(shift))
+}
+
+// ListShifts handles listing shifts
+// @Summary List shifts
+// @Description Get a paginated list of shifts, newest first
+// @Tags Shifts
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Router /shifts [get]
+func (h *ShiftHandler) ListShifts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	shifts, total, err := h.shiftService.ListShifts(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list shifts", "error", err)
+		return shiftErrorResponse(c, err, "Failed to list shifts")
+	}
+
+	items := make([]HashIDResponse, len(shifts))
+	for i := range shifts {
+		items[i] = shiftResponse(&shifts[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Shifts retrieved successfully", items, total, page, limit)
+}
+
+// GetCurrentShift handles getting the logged-in user's open shift
+// @Summary Get the current shift
+// @Description Get the open shift of the logged-in user
+// @Tags Shifts
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 404 {object} Response
+// @Router /shifts/current [get]
+func (h *ShiftHandler) GetCurrentShift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	shift, err := h.shiftService.GetCurrentShift(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get current shift", "error", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "No open shift")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to get current shift")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Shift retrieved successfully", shiftResponse(shift))
+}
+
+// GetShift handles getting a shift by ID
+// @Summary Get a shift by ID
+// @Description Get a shift with its cash events and closing counts
+// @Tags Shifts
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Shift ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /shifts/{id} [get]
+func (h *ShiftHandler) GetShift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID format")
+	}
+
+	shift, err := h.shiftService.GetShift(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get shift", "error", err, "id", id)
+		return shiftErrorResponse(c, err, "Failed to get shift")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Shift retrieved successfully", shiftResponse(shift))
+}
+
+// CashIn handles putting cash into the drawer
+// @Summary Record cash in
+// @Description Record cash put into the drawer of an open shift, such as a change top-up
+// @Tags Shifts
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Shift ID"
+// @Param request body CashEventRequest true "Cash event request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /shifts/{id}/cash-in [post]
+func (h *ShiftHandler) CashIn(c echo.Context) error {
+	return h.recordCashEvent(c, entities.ShiftCashIn)
+}
+
+// CashOut handles taking cash out of the drawer
+// @Summary Record cash out
+// @Description Record cash taken out of the drawer of an open shift, such as petty cash or a safe drop
+// @Tags Shifts
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Shift ID"
+// @Param request body CashEventRequest true "Cash event request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /shifts/{id}/cash-out [post]
+func (h *ShiftHandler) CashOut(c echo.Context) error {
+	return h.recordCashEvent(c, entities.ShiftCashOut)
+}
+
+// recordCashEvent records a cash event of eventType on the shift in the URL
+func (h *ShiftHandler) recordCashEvent(c echo.Context, eventType string) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID format")
+	}
+
+	var req CashEventRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	shift, err := h.shiftService.RecordCashEvent(ctx, id, interfaces.CashEventRequest{
+		Type:   eventType,
+		Amount: req.Amount,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to record cash event", "error", err, "id", id)
+		return shiftErrorResponse(c, err, "Failed to record cash event")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Cash event recorded successfully", shiftResponse(shift))
+}
+
+// CloseShift handles closing a shift
+// @Summary Close a shift
+// @Description Close a shift with the counted cash, and optionally counts for other tenders. The expected cash and the difference are recorded.
+// @Tags Shifts
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Shift ID"
+// @Param request body CloseShiftRequest true "Close shift request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /shifts/{id}/close [post]
+func (h *ShiftHandler) CloseShift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID format")
+	}
+
+	var req CloseShiftRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.CloseShiftRequest{
+		CountedCash: req.CountedCash,
+		Notes:       req.Notes,
+		Counts:      make([]interfaces.TenderCount, len(req.Counts)),
+	}
+	for i, count := range req.Counts {
+		serviceReq.Counts[i] = interfaces.TenderCount{
+			Method: count.Method,
+			Amount: count.Amount,
+		}
+	}
+
+	shift, err := h.shiftService.CloseShift(ctx, id, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to close shift", "error", err, "id", id)
+		return shiftErrorResponse(c, err, "Failed to close shift")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Shift closed successfully", shiftResponse(shift))
+}
+
+// GetShiftReport handles the shift cash reconciliation report
+// @Summary Get a shift report
+// @Description Compare the expected amount of every tender with what was counted. Open shifts show what is expected so far.
+// @Tags Shifts
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Shift ID"
+// @Success 200 {object} Response{data=interfaces.ShiftReport}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /shifts/{id}/report [get]
+func (h *ShiftHandler) GetShiftReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID format")
+	}
+
+	report, err := h.shiftService.GetShiftReport(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get shift report", "error", err, "id", id)
+		return shiftErrorResponse(c, err, "Failed to get shift report")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Shift report retrieved successfully", map[string]interface{}{
+		"shift":   shiftResponse(report.Shift),
+		"tenders": report.Tenders,
+	})
+}
+
+// decodeID decodes the hashed shift ID from the URL
+func (h *ShiftHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid shift ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// shiftErrorResponse maps shift errors to HTTP status codes
+func shiftErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Shift not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrShiftAlreadyOpen), errors.Is(err, entities.ErrShiftClosed):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// shiftResponse flattens a shift with hashed IDs for API responses
+func shiftResponse(s *entities.Shift) HashIDResponse {
+	var closedBy, closedAt interface{}
+	if s.ClosedBy != nil {
+		closedBy = hash.HashID(*s.ClosedBy)
+	}
+	if s.ClosedAt != nil {
+		closedAt = s.ClosedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	var username string
+	if s.User != nil {
+		username = s.User.Username
+	}
+
+	events := make([]map[string]interface{}, len(s.CashEvents))
+	for i, e := range s.CashEvents {
+		events[i] = map[string]interface{}{
+			"id":         hash.HashID(e.ID),
+			"type":       e.Type,
+			"amount":     e.Amount,
+			"reason":     e.Reason,
+			"user_id":    hash.HashID(e.UserID),
+			"created_at": e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	counts := make([]map[string]interface{}, len(s.Counts))
+	for i, count := range s.Counts {
+		counts[i] = map[string]interface{}{
+			"method": count.Method,
+			"amount": count.Amount,
+		}
+	}
+
+	return WithHashID(
+		s.ID,
+		s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"user_id":         hash.HashID(s.UserID),
+			"username":        username,
+			"status":          s.Status,
+			"opening_float":   s.OpeningFloat,
+			"expected_cash":   s.ExpectedCash,
+			"counted_cash":    s.CountedCash,
+			"cash_difference": s.CashDifference,
+			"notes":           s.Notes,
+			"closed_by":       closedBy,
+			"closed_at":       closedAt,
+			"cash_events":     events,
+			"counts":          counts,
+		},
+	)
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type StockOpnameHandler struct {
+	opnameService interfaces.StockOpnameService
+	logger        *slog.Logger
+}
+
+// NewStockOpnameHandler creates a new stock opname handler
+func NewStockOpnameHandler(opnameService interfaces.StockOpnameService, logger *slog.Logger) *StockOpnameHandler {
+	return &StockOpnameHandler{
+		opnameService: opnameService,
+		logger:        logger,
+	}
+}
+
+// OpenStockOpnameRequest represents the open stock opname request
+type OpenStockOpnameRequest struct {
+	Notes string `json:"notes"`
+}
+
+// RecordStockCountRequest represents the counts submitted by one device.
+// Quantities are added to earlier counts unless replace is true.
+type RecordStockCountRequest struct {
+	Items   []StockCountItemRequest `json:"items" validate:"required,min=1,dive"`
+	Replace bool                    `json:"replace"`
+}
+
+// StockCountItemRequest represents the counted quantity of a product
+type StockCountItemRequest struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"min=0"`
+}
+
+// OpenSession handles opening a new stock count session
+// @Summary Open a stock opname session
+// @Description Start a physical stock count session for the current tenant
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body OpenStockOpnameRequest false "Open stock opname request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /stock-opnames [post]
+func (h *StockOpnameHandler) OpenSession(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req OpenStockOpnameRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	opname, err := h.opnameService.OpenSession(ctx, req.Notes)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to open stock opname", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to open stock opname")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Stock opname opened successfully", stockOpnameResponse(opname))
+}
+
+// ListSessions handles listing stock count sessions
+// @Summary List stock opname sessions
+// @Description Get a paginated list of stock count sessions, newest first
+// @Tags Stock Opname
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /stock-opnames [get]
+func (h *StockOpnameHandler) ListSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	opnames, total, err := h.opnameService.ListSessions(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list stock opnames", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list stock opnames")
+	}
+
+	items := make([]HashIDResponse, len(opnames))
+	for i := range opnames {
+		items[i] = stockOpnameResponse(&opnames[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Stock opnames retrieved successfully", items, total, page, limit)
+}
+
+// GetSession handles getting a stock count session
+// @Summary Get a stock opname session
+// @Description Get a stock count session with its counted items
+// @Tags Stock Opname
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Stock opname ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /stock-opnames/{id} [get]
+func (h *StockOpnameHandler) GetSession(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid stock opname ID format")
+	}
+
+	opname, err := h.opnameService.GetSession(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get stock opname", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Stock opname not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Stock opname retrieved successfully", stockOpnameResponse(opname))
+}
+
+// RecordCounts handles submitting counted quantities
+// @Summary Record stock counts
+// @Description Submit counted quantities for an open session. Counts from several devices are summed unless replace is true.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Stock opname ID"
+// @Param request body RecordStockCountRequest true "Record stock count request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /stock-opnames/{id}/counts [post]
+func (h *StockOpnameHandler) RecordCounts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid stock opname ID format")
+	}
+
+	var req RecordStockCountRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.RecordStockCountRequest{
+		Items:   make([]interfaces.StockCountItem, len(req.Items)),
+		Replace: req.Replace,
+	}
+	for i, item := range req.Items {
+		productID, err := hash.DecodeHashID(item.ProductID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", item.ProductID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+		}
+		serviceReq.Items[i] = interfaces.StockCountItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	opname, err := h.opnameService.RecordCounts(ctx, id, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to record stock counts", "error", err, "id", id)
+		return stockOpnameErrorResponse(c, err, "Failed to record stock counts")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Stock counts recorded successfully", stockOpnameResponse(opname))
+}
+
+// GetVariance handles reviewing counted versus system stock
+// @Summary Get stock opname variance
+// @Description Compare counted quantities with system stock, valued at harga_modal
+// @Tags Stock Opname
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Stock opname ID"
+// @Success 200 {object} Response{data=interfaces.StockOpnameVariance}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /stock-opnames/{id}/variance [get]
+func (h *StockOpnameHandler) GetVariance(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid stock opname ID format")
+	}
+
+	variance, err := h.opnameService.GetVariance(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get stock opname variance", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Stock opname not found")
+	}
+
+	items := make([]map[string]interface{}, len(variance.Items))
+	for i, item := range variance.Items {
+		items[i] = map[string]interface{}{
+			"product_id":     hash.HashID(item.ProductID),
+			"product_name":   item.ProductName,
+			"sku":            item.SKU,
+			"system_qty":     item.SystemQty,
+			"counted_qty":    item.CountedQty,
+			"variance":       item.Variance,
+			"harga_modal":    item.HargaModal,
+			"variance_value": item.VarianceValue,
+		}
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Stock opname variance retrieved successfully", map[string]interface{}{
+		"opname_id":            hash.HashID(variance.OpnameID),
+		"status":               variance.Status,
+		"items":                items,
+		"total_variance":       variance.TotalVariance,
+		"total_variance_value": variance.TotalVarianceValue,
+	})
+}
+
+// CommitSession handles posting a session's variance as stock adjustments
+// @Summary Commit a stock opname session
+// @Description Adjust stock of every counted product to its counted quantity in one transaction
+// @Tags Stock Opname
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Stock opname ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /stock-opnames/{id}/commit [post]
+func (h *StockOpnameHandler) CommitSession(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid stock opname ID format")
+	}
+
+	opname, err := h.opnameService.CommitSession(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to commit stock opname", "error", err, "id", id)
+		return stockOpnameErrorResponse(c, err, "Failed to commit stock opname")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Stock opname committed successfully", stockOpnameResponse(opname))
+}
+
+// CancelSession handles discarding an open session
+// @Summary Cancel a stock opname session
+// @Description Discard an open stock count session without changing stock
+// @Tags Stock Opname
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Stock opname ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /stock-opnames/{id}/cancel [post]
+func (h *StockOpnameHandler) CancelSession(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid stock opname ID format")
+	}
+
+	opname, err := h.opnameService.CancelSession(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to cancel stock opname", "error", err, "id", id)
+		return stockOpnameErrorResponse(c, err, "Failed to cancel stock opname")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Stock opname cancelled successfully", stockOpnameResponse(opname))
+}
+
+// decodeID decodes the hashed session ID from the URL
+func (h *StockOpnameHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid stock opname ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// stockOpnameErrorResponse maps stock opname errors to HTTP status codes
+func stockOpnameErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrStockOpnameClosed):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrProductHasVariants):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// stockOpnameResponse flattens a stock opname with hashed IDs for API responses
+func stockOpnameResponse(o *entities.StockOpname) HashIDResponse {
+	items := make([]map[string]interface{}, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = map[string]interface{}{
+			"product_id":     hash.HashID(item.ProductID),
+			"product_name":   item.Product.Name,
+			"sku":            item.Product.SKU,
+			"counted_qty":    item.CountedQty,
+			"system_qty":     item.SystemQty,
+			"variance":       item.Variance,
+			"variance_value": item.VarianceValue,
+		}
+	}
+
+	data := map[string]interface{}{
+		"status":       o.Status,
+		"notes":        o.Notes,
+		"opened_by":    hash.HashID(o.OpenedBy),
+		"committed_by": nil,
+		"committed_at": o.CommittedAt,
+		"items":        items,
+	}
+	if o.CommittedBy != nil {
+		data["committed_by"] = hash.HashID(*o.CommittedBy)
+	}
+
+	return WithHashID(
+		o.ID,
+		o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		data,
+	)
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type SupplierHandler struct {
+	supplierService interfaces.SupplierService
+	logger          *slog.Logger
+}
+
+// NewSupplierHandler creates a new supplier handler
+func NewSupplierHandler(supplierService interfaces.SupplierService, logger *slog.Logger) *SupplierHandler {
+	return &SupplierHandler{
+		supplierService: supplierService,
+		logger:          logger,
+	}
+}
+
+// CreateSupplierRequest represents the create supplier request
+type CreateSupplierRequest struct {
+	Name        string `json:"name" validate:"required"`
+	ContactName string `json:"contact_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	Address     string `json:"address"`
+	Notes       string `json:"notes"`
+}
+
+// UpdateSupplierRequest represents the update supplier request
+type UpdateSupplierRequest struct {
+	Name        *string `json:"name,omitempty"`
+	ContactName *string `json:"contact_name,omitempty"`
+	Phone       *string `json:"phone,omitempty"`
+	Email       *string `json:"email,omitempty" validate:"omitempty,email"`
+	Address     *string `json:"address,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+// CreateSupplier handles creating a supplier
+// @Summary Create a supplier
+// @Description Create a new supplier for the current tenant
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body CreateSupplierRequest true "Create supplier request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Router /suppliers [post]
+func (h *SupplierHandler) CreateSupplier(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateSupplierRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	supplier := &entities.Supplier{
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Notes:       req.Notes,
+	}
+
+	if err := h.supplierService.CreateSupplier(ctx, supplier); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create supplier", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to create supplier")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Supplier created successfully", supplierResponse(supplier))
+}
+
+// ListSuppliers handles listing suppliers
+// @Summary List suppliers
+// @Description Get a paginated list of suppliers
+// @Tags Suppliers
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 401 {object} Response
+// @Router /suppliers [get]
+func (h *SupplierHandler) ListSuppliers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	suppliers, total, err := h.supplierService.ListSuppliers(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list suppliers", "error", err)
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to list suppliers")
+	}
+
+	items := make([]HashIDResponse, len(suppliers))
+	for i := range suppliers {
+		items[i] = supplierResponse(&suppliers[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Suppliers retrieved successfully", items, total, page, limit)
+}
+
+// GetSupplier handles getting a supplier by ID
+// @Summary Get a supplier
+// @Description Get a supplier by ID
+// @Tags Suppliers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /suppliers/{id} [get]
+func (h *SupplierHandler) GetSupplier(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID format")
+	}
+
+	supplier, err := h.supplierService.GetSupplier(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get supplier", "error", err, "id", id)
+		return ErrorResponse(c, http.StatusNotFound, "Supplier not found")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Supplier retrieved successfully", supplierResponse(supplier))
+}
+
+// UpdateSupplier handles updating a supplier
+// @Summary Update a supplier
+// @Description Update the provided fields of a supplier
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Supplier ID"
+// @Param request body UpdateSupplierRequest true "Update supplier request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /suppliers/{id} [put]
+func (h *SupplierHandler) UpdateSupplier(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID format")
+	}
+
+	var req UpdateSupplierRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.ContactName != nil {
+		updates["contact_name"] = *req.ContactName
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	supplier, err := h.supplierService.UpdateSupplier(ctx, id, updates)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update supplier", "error", err, "id", id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Supplier not found")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to update supplier")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Supplier updated successfully", supplierResponse(supplier))
+}
+
+// DeleteSupplier handles deleting a supplier
+// @Summary Delete a supplier
+// @Description Delete a supplier that has no purchase orders
+// @Tags Suppliers
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /suppliers/{id} [delete]
+func (h *SupplierHandler) DeleteSupplier(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID format")
+	}
+
+	if err := h.supplierService.DeleteSupplier(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete supplier", "error", err, "id", id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrorResponse(c, http.StatusNotFound, "Supplier not found")
+		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to delete supplier")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Supplier deleted successfully", nil)
+}
+
+// decodeID decodes the hashed supplier ID from the URL
+func (h *SupplierHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid supplier ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// supplierResponse flattens a supplier with hashed IDs for API responses
+func supplierResponse(s *entities.Supplier) HashIDResponse {
+	return WithHashID(
+		s.ID,
+		s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"name":         s.Name,
+			"contact_name": s.ContactName,
+			"phone":        s.Phone,
+			"email":        s.Email,
+			"address":      s.Address,
+			"notes":        s.Notes,
+		},
+	)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type TerminalHandler struct {
+	terminalService interfaces.TerminalService
+	logger          *slog.Logger
+}
+
+// NewTerminalHandler creates a new terminal handler
+func NewTerminalHandler(terminalService interfaces.TerminalService, logger *slog.Logger) *TerminalHandler {
+	return &TerminalHandler{
+		terminalService: terminalService,
+		logger:          logger,
+	}
+}
+
+// RegisterTerminalRequest represents the register terminal request
+type RegisterTerminalRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// ListTerminals handles listing the tenant's terminals
+// @Summary List terminals
+// @Description Get the tenant's registered terminals, including revoked ones
+// @Tags Terminals
+// @Produce json
+// @Security bearerAuth
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 403 {object} Response
+// @Router /terminals [get]
+func (h *TerminalHandler) ListTerminals(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	terminals, err := h.terminalService.ListTerminals(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list terminals", "error", err)
+		return terminalErrorResponse(c, err, "Failed to list terminals")
+	}
+
+	items := make([]HashIDResponse, len(terminals))
+	for i := range terminals {
+		items[i] = terminalResponse(&terminals[i], "")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Terminals retrieved successfully", items)
+}
+
+// RegisterTerminal handles registering a terminal
+// @Summary Register a terminal
+// @Description Register a shared till for PIN login. The response contains the device token the till sends in the X-Terminal-Token header; it is shown only once.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body RegisterTerminalRequest true "Register terminal request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /terminals [post]
+func (h *TerminalHandler) RegisterTerminal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req RegisterTerminalRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	terminal, deviceToken, err := h.terminalService.RegisterTerminal(ctx, req.Name)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to register terminal", "error", err)
+		return terminalErrorResponse(c, err, "Failed to register terminal")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "Terminal registered successfully", terminalResponse(terminal, deviceToken))
+}
+
+// RevokeTerminal handles revoking a terminal
+// @Summary Revoke a terminal
+// @Description Stop a terminal accepting PIN logins and sign out the user signed in on it
+// @Tags Terminals
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Terminal ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /terminals/{id} [delete]
+func (h *TerminalHandler) RevokeTerminal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid terminal ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid terminal ID format")
+	}
+
+	if err := h.terminalService.RevokeTerminal(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to revoke terminal", "error", err, "id", id)
+		return terminalErrorResponse(c, err, "Failed to revoke terminal")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Terminal revoked successfully", nil)
+}
+
+// terminalErrorResponse maps terminal errors to HTTP status codes
+func terminalErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Terminal not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// terminalResponse flattens a terminal with hashed IDs for API responses.
+// The device token is only included right after registration.
+func terminalResponse(t *entities.Terminal, deviceToken string) HashIDResponse {
+	data := map[string]interface{}{
+		"name":         t.Name,
+		"last_seen_at": t.LastSeenAt,
+		"revoked_at":   t.RevokedAt,
+	}
+	if deviceToken != "" {
+		data["device_token"] = deviceToken
+	}
+
+	return WithHashID(
+		t.ID,
+		t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		t.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		data,
+	)
+}
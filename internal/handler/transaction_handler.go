@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -26,18 +29,52 @@ func NewTransactionHandler(transactionService interfaces.TransactionService, log
 
 // CreateTransactionRequest represents the create transaction request
 type CreateTransactionRequest struct {
-	Items         []TransactionItemRequest `json:"items" validate:"required,min=1"`
+	Items         []TransactionItemRequest `json:"items" validate:"required,min=1,dive"`
 	User          string                   `json:"user" validate:"required"`
-	PaymentMethod string                   `json:"payment_method" validate:"required"`
+	PaymentMethod string                   `json:"payment_method"`
 	Discount      float64                  `json:"discount"`
-	TotalPrice    float64                  `json:"total_price" validate:"required,min=0"`
+	TotalPrice    money.Money              `json:"total_price" validate:"min=0"`
 	Notes         string                   `json:"notes"`
+	Payments      []PaymentRequest         `json:"payments" validate:"dive"`
+	VoucherCode   string                   `json:"voucher_code"` // a voucher beaten by a bigger promotion is returned as voucher_not_applied
+	CustomerID    string                   `json:"customer_id,omitempty"`
+	CustomerPhone string                   `json:"customer_phone"` // links a registered customer; required by vouchers limited per customer
 }
 
-// TransactionItemRequest represents an item in transaction request
+// PaymentRequest represents a tender in transaction request.
+// Use payments instead of payment_method to split a sale across several tenders.
+type PaymentRequest struct {
+	Method          string      `json:"method" validate:"required"`
+	Amount          money.Money `json:"amount" validate:"required,gt=0"`
+	ReferenceNumber string      `json:"reference_number"`
+}
+
+// TransactionItemRequest represents an item in transaction request.
+// Send either product_id or a scanned barcode; pack barcodes multiply quantity.
+// Modifiers lists the IDs of the selected modifier options.
 type TransactionItemRequest struct {
-	ProductID string `json:"product_id" validate:"required"`
-	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	ProductID string   `json:"product_id" validate:"required_without=Barcode"`
+	Barcode   string   `json:"barcode" validate:"required_without=ProductID"`
+	Quantity  int      `json:"quantity" validate:"required,min=1"`
+	Modifiers []string `json:"modifiers"`
+}
+
+// VoidTransactionRequest represents the void transaction request
+type VoidTransactionRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// RefundTransactionRequest represents the refund transaction request.
+// Leave items empty to refund everything that has not been refunded yet.
+type RefundTransactionRequest struct {
+	Reason string              `json:"reason" validate:"required"`
+	Items  []RefundItemRequest `json:"items" validate:"dive"`
+}
+
+// RefundItemRequest represents a returned item in refund request
+type RefundItemRequest struct {
+	ItemID   string `json:"item_id" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1"`
 }
 
 // CreateTransaction handles creating a new transaction
@@ -73,61 +110,70 @@ func (h *TransactionHandler) CreateTransaction(c echo.Context) error {
 		Discount:      req.Discount,
 		TotalPrice:    req.TotalPrice,
 		Notes:         req.Notes,
+		VoucherCode:   req.VoucherCode,
+		CustomerPhone: req.CustomerPhone,
 		Items:         make([]interfaces.TransactionItemRequest, len(req.Items)),
+		Payments:      make([]interfaces.PaymentRequest, len(req.Payments)),
 	}
 
-	// Decode hashed product IDs and convert to service request
-	for i, item := range req.Items {
-		productID, err := hash.DecodeHashID(item.ProductID)
+	if req.CustomerID != "" {
+		customerID, err := hash.DecodeHashID(req.CustomerID)
 		if err != nil {
-			h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", item.ProductID)
-			return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+			h.logger.WarnContext(ctx, "invalid customer ID format", "error", err, "hashed_id", req.CustomerID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID format")
 		}
+		serviceReq.CustomerID = &customerID
+	}
 
+	for i, p := range req.Payments {
+		serviceReq.Payments[i] = interfaces.PaymentRequest{
+			Method:          p.Method,
+			Amount:          p.Amount,
+			ReferenceNumber: p.ReferenceNumber,
+		}
+	}
+
+	// Decode hashed product IDs and convert to service request; barcodes are resolved by the service
+	for i, item := range req.Items {
 		serviceReq.Items[i] = interfaces.TransactionItemRequest{
-			ProductID: productID,
+			Barcode:   item.Barcode,
 			Quantity:  item.Quantity,
+			Modifiers: make([]uint, len(item.Modifiers)),
+		}
+		for j, hashedID := range item.Modifiers {
+			optionID, err := hash.DecodeHashID(hashedID)
+			if err != nil {
+				h.logger.WarnContext(ctx, "invalid modifier ID format", "error", err, "hashed_id", hashedID)
+				return ErrorResponse(c, http.StatusBadRequest, "Invalid modifier ID format")
+			}
+			serviceReq.Items[i].Modifiers[j] = optionID
 		}
+		if item.Barcode != "" {
+			continue
+		}
+
+		productID, err := hash.DecodeHashID(item.ProductID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid product ID format", "error", err, "hashed_id", item.ProductID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format")
+		}
+		serviceReq.Items[i].ProductID = productID
 	}
 
 	transaction, err := h.transactionService.CreateTransaction(ctx, serviceReq)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to create transaction", "error", err)
-		return ErrorResponse(c, http.StatusInternalServerError, "Failed to create transaction")
-	}
-
-	// Convert items to flattened structure
-	items := make([]map[string]interface{}, len(transaction.Items))
-	for i, item := range transaction.Items {
-		items[i] = map[string]interface{}{
-			"product_id": hash.HashID(item.ProductID),
-			"quantity":   item.Quantity,
-			"price":      item.Price,
-			"product": map[string]interface{}{
-				"id":          hash.HashID(item.Product.ID),
-				"name":        item.Product.Name,
-				"sku":         item.Product.SKU,
-				"image":       item.Product.Image,
-				"harga_modal": item.Product.HargaModal,
-				"harga_jual":  item.Product.HargaJual,
-				"stock":       item.Product.Stock,
-			},
+		if errors.Is(err, entities.ErrInsufficientStock) || errors.Is(err, entities.ErrVoucherExhausted) || errors.Is(err, entities.ErrInsufficientPoints) {
+			return ErrorResponse(c, http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, entities.ErrProductHasVariants) || errors.Is(err, entities.ErrInvalidModifier) ||
+			errors.Is(err, entities.ErrInvalidVoucher) || errors.Is(err, entities.ErrInvalidPointsPayment) {
+			return ErrorResponse(c, http.StatusBadRequest, err.Error())
 		}
+		return ErrorResponse(c, http.StatusInternalServerError, "Failed to create transaction")
 	}
 
-	response := WithHashID(
-		transaction.ID,
-		transaction.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		transaction.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		map[string]interface{}{
-			"items":          items,
-			"user":           transaction.User,
-			"payment_method": transaction.PaymentMethod,
-			"discount":       transaction.Discount,
-			"total_price":    transaction.TotalPrice,
-			"notes":          transaction.Notes,
-		},
-	)
+	response := transactionResponse(transaction)
 
 	return SuccessResponse(c, http.StatusCreated, "Transaction created successfully", response)
 }
@@ -164,39 +210,8 @@ func (h *TransactionHandler) ListTransactions(c echo.Context) error {
 
 	// Convert transactions to HashIDResponse
 	items := make([]HashIDResponse, len(transactions))
-	for i, t := range transactions {
-		// Convert items to flattened structure
-		transactionItems := make([]map[string]interface{}, len(t.Items))
-		for j, item := range t.Items {
-			transactionItems[j] = map[string]interface{}{
-				"product_id": hash.HashID(item.ProductID),
-				"quantity":   item.Quantity,
-				"price":      item.Price,
-				"product": map[string]interface{}{
-					"id":          hash.HashID(item.Product.ID),
-					"name":        item.Product.Name,
-					"sku":         item.Product.SKU,
-					"image":       item.Product.Image,
-					"harga_modal": item.Product.HargaModal,
-					"harga_jual":  item.Product.HargaJual,
-					"stock":       item.Product.Stock,
-				},
-			}
-		}
-
-		items[i] = WithHashID(
-			t.ID,
-			t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			t.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			map[string]interface{}{
-				"items":          transactionItems,
-				"user":           t.User,
-				"payment_method": t.PaymentMethod,
-				"discount":       t.Discount,
-				"total_price":    t.TotalPrice,
-				"notes":          t.Notes,
-			},
-		)
+	for i := range transactions {
+		items[i] = transactionResponse(&transactions[i])
 	}
 
 	return SuccessPaginatedResponse(c, http.StatusOK, "Transactions retrieved successfully", items, total, page, limit)
@@ -235,13 +250,176 @@ func (h *TransactionHandler) GetTransaction(c echo.Context) error {
 		return ErrorResponse(c, http.StatusInternalServerError, "Failed to get transaction")
 	}
 
-	// Convert items to flattened structure
-	items := make([]map[string]interface{}, len(transaction.Items))
-	for i, item := range transaction.Items {
+	response := transactionResponse(transaction)
+
+	return SuccessResponse(c, http.StatusOK, "Transaction retrieved successfully", response)
+}
+
+// VoidTransaction handles voiding a transaction
+// @Summary Void a transaction
+// @Description Cancel a completed transaction and restore stock for all of its items
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Transaction ID"
+// @Param request body VoidTransactionRequest true "Void transaction request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /transactions/{id}/void [post]
+func (h *TransactionHandler) VoidTransaction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid transaction ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid transaction ID format")
+	}
+
+	var req VoidTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	transaction, err := h.transactionService.VoidTransaction(ctx, id, interfaces.VoidTransactionRequest{
+		Reason: req.Reason,
+		UserID: c.Get("user_id").(uint),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to void transaction", "error", err, "id", id)
+		return reversalErrorResponse(c, err, "Failed to void transaction")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Transaction voided successfully", transactionResponse(transaction))
+}
+
+// RefundTransaction handles refunding a transaction
+// @Summary Refund a transaction
+// @Description Refund all or part of a transaction and restore stock for the returned items
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "Transaction ID"
+// @Param request body RefundTransactionRequest true "Refund transaction request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /transactions/{id}/refund [post]
+func (h *TransactionHandler) RefundTransaction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid transaction ID format", "error", err, "hashed_id", hashedID)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid transaction ID format")
+	}
+
+	var req RefundTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	serviceReq := interfaces.RefundTransactionRequest{
+		Reason: req.Reason,
+		UserID: c.Get("user_id").(uint),
+		Items:  make([]interfaces.RefundItemRequest, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		itemID, err := hash.DecodeHashID(item.ItemID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid item ID format", "error", err, "hashed_id", item.ItemID)
+			return ErrorResponse(c, http.StatusBadRequest, "Invalid item ID format")
+		}
+
+		serviceReq.Items[i] = interfaces.RefundItemRequest{
+			TransactionItemID: itemID,
+			Quantity:          item.Quantity,
+		}
+	}
+
+	transaction, err := h.transactionService.RefundTransaction(ctx, id, serviceReq)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to refund transaction", "error", err, "id", id)
+		return reversalErrorResponse(c, err, "Failed to refund transaction")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Transaction refunded successfully", transactionResponse(transaction))
+}
+
+// reversalErrorResponse maps void and refund errors to HTTP status codes
+func reversalErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "Transaction not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrInvalidTransactionState):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrInvalidRefundQuantity), errors.Is(err, entities.ErrTransactionItemNotFound):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// transactionItemModifiers lists the modifiers selected on a transaction item for receipts
+func transactionItemModifiers(modifiers []entities.TransactionItemModifier) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(modifiers))
+	for i, m := range modifiers {
+		result[i] = map[string]interface{}{
+			"modifier_option_id": hash.HashID(m.ModifierOptionID),
+			"group_name":         m.GroupName,
+			"name":               m.Name,
+			"price":              m.Price,
+		}
+	}
+	return result
+}
+
+// transactionItemPromotions lists the promotions applied to a transaction item
+func transactionItemPromotions(promotions []entities.TransactionItemPromotion) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(promotions))
+	for i, p := range promotions {
+		result[i] = map[string]interface{}{
+			"promotion_id": hash.HashID(p.PromotionID),
+			"name":         p.Name,
+			"amount":       p.Amount,
+		}
+	}
+	return result
+}
+
+// transactionResponse flattens a transaction with hashed IDs for API responses
+func transactionResponse(t *entities.Transaction) HashIDResponse {
+	items := make([]map[string]interface{}, len(t.Items))
+	for i, item := range t.Items {
 		items[i] = map[string]interface{}{
-			"product_id": hash.HashID(item.ProductID),
-			"quantity":   item.Quantity,
-			"price":      item.Price,
+			"id":                hash.HashID(item.ID),
+			"product_id":        hash.HashID(item.ProductID),
+			"quantity":          item.Quantity,
+			"refunded_quantity": t.RefundedQuantity(item.ID),
+			"price":             item.Price,
+			"tax_exempt":        item.TaxExempt,
+			"discount":          item.Discount,
+			"promotions":        transactionItemPromotions(item.Promotions),
+			"modifiers":         transactionItemModifiers(item.Modifiers),
 			"product": map[string]interface{}{
 				"id":          hash.HashID(item.Product.ID),
 				"name":        item.Product.Name,
@@ -254,19 +432,80 @@ func (h *TransactionHandler) GetTransaction(c echo.Context) error {
 		}
 	}
 
-	response := WithHashID(
-		transaction.ID,
-		transaction.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		transaction.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	payments := make([]map[string]interface{}, len(t.Payments))
+	for i, p := range t.Payments {
+		payments[i] = map[string]interface{}{
+			"method":           p.Method,
+			"amount":           p.Amount,
+			"change_amount":    p.ChangeAmount,
+			"reference_number": p.ReferenceNumber,
+		}
+	}
+
+	refunds := make([]map[string]interface{}, len(t.Refunds))
+	for i, refund := range t.Refunds {
+		refundItems := make([]map[string]interface{}, len(refund.Items))
+		for j, item := range refund.Items {
+			refundItems[j] = map[string]interface{}{
+				"item_id":        hash.HashID(item.TransactionItemID),
+				"product_id":     hash.HashID(item.ProductID),
+				"quantity":       item.Quantity,
+				"amount":         item.Amount,
+				"service_charge": item.ServiceCharge,
+				"tax":            item.Tax,
+			}
+		}
+
+		refunds[i] = map[string]interface{}{
+			"id":         hash.HashID(refund.ID),
+			"type":       refund.Type,
+			"reason":     refund.Reason,
+			"user_id":    hash.HashID(refund.UserID),
+			"amount":     refund.Amount,
+			"items":      refundItems,
+			"created_at": refund.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	var customerID, shiftID interface{}
+	if t.CustomerID != nil {
+		customerID = hash.HashID(*t.CustomerID)
+	}
+	if t.ShiftID != nil {
+		shiftID = hash.HashID(*t.ShiftID)
+	}
+
+	return WithHashID(
+		t.ID,
+		t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		t.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		map[string]interface{}{
-			"items":          items,
-			"user":           transaction.User,
-			"payment_method": transaction.PaymentMethod,
-			"discount":       transaction.Discount,
-			"total_price":    transaction.TotalPrice,
-			"notes":          transaction.Notes,
+			"items":               items,
+			"payments":            payments,
+			"refunds":             refunds,
+			"user":                t.User,
+			"payment_method":      t.PaymentMethod,
+			"subtotal":            t.Subtotal,
+			"promotion_discount":  t.PromotionDiscount,
+			"voucher_code":        t.VoucherCode,
+			"voucher_not_applied": t.VoucherNotApplied,
+			"customer_id":         customerID,
+			"customer_phone":      t.CustomerPhone,
+			"points_earned":       t.PointsEarned,
+			"points_redeemed":     t.PointsRedeemed,
+			"discount":            t.Discount,
+			"service_charge_rate": t.ServiceChargeRate,
+			"service_charge":      t.ServiceCharge,
+			"tax_rate":            t.TaxRate,
+			"tax_inclusive":       t.TaxInclusive,
+			"tax_base":            t.TaxBase,
+			"tax":                 t.Tax,
+			"total_price":         t.TotalPrice,
+			"change_due":          t.ChangeDue,
+			"status":              t.Status,
+			"label":               t.Label,
+			"shift_id":            shiftID,
+			"notes":               t.Notes,
 		},
 	)
-
-	return SuccessResponse(c, http.StatusOK, "Transaction retrieved successfully", response)
 }
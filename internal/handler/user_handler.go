@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"gorm.io/gorm"
+)
+
+type UserHandler struct {
+	userService interfaces.UserService
+	logger      *slog.Logger
+}
+
+// NewUserHandler creates a new tenant user handler
+func NewUserHandler(userService interfaces.UserService, logger *slog.Logger) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// CreateUserRequest represents the create tenant user request
+type CreateUserRequest struct {
+	Username string `json:"username" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,min=6"`
+	Role     string `json:"role" validate:"required"`
+}
+
+// UpdateUserRequest represents the update tenant user request
+type UpdateUserRequest struct {
+	Username *string `json:"username,omitempty" validate:"omitempty,max=255"`
+	Role     *string `json:"role,omitempty" validate:"omitempty,min=1"`
+	Active   *bool   `json:"active,omitempty"`
+}
+
+// ResetPasswordRequest represents the reset user password request
+type ResetPasswordRequest struct {
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// ListUsers handles listing the tenant's users
+// @Summary List users
+// @Description Get a paginated list of the tenant's users
+// @Tags Users
+// @Produce json
+// @Security bearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} Response{data=[]HashIDResponse}
+// @Failure 403 {object} Response
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	users, total, err := h.userService.ListUsers(ctx, page, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list users", "error", err)
+		return userErrorResponse(c, err, "Failed to list users")
+	}
+
+	items := make([]HashIDResponse, len(users))
+	for i := range users {
+		items[i] = userResponse(&users[i])
+	}
+
+	return SuccessPaginatedResponse(c, http.StatusOK, "Users retrieved successfully", items, total, page, limit)
+}
+
+// GetUser handles getting a user by ID
+// @Summary Get a user
+// @Description Get a user of the tenant by ID
+// @Tags Users
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/{id} [get]
+func (h *UserHandler) GetUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format")
+	}
+
+	user, err := h.userService.GetUser(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get user", "error", err, "id", id)
+		return userErrorResponse(c, err, "Failed to get user")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "User retrieved successfully", userResponse(user))
+}
+
+// CreateUser handles creating a user in the tenant
+// @Summary Create a user
+// @Description Create an active user in the caller's tenant with a built-in or custom role. The role cannot grant permissions the caller lacks.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param request body CreateUserRequest true "Create user request"
+// @Success 201 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /users [post]
+func (h *UserHandler) CreateUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	user, err := h.userService.CreateUser(ctx, interfaces.CreateUserRequest{
+		Username: req.Username,
+		Password: req.Password,
+		Role:     req.Role,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create user", "error", err)
+		return userErrorResponse(c, err, "Failed to create user")
+	}
+
+	return SuccessResponse(c, http.StatusCreated, "User created successfully", userResponse(user))
+}
+
+// UpdateUser handles updating a user
+// @Summary Update a user
+// @Description Rename a user, assign a role, or deactivate and reactivate the account. Changes apply from the user's next request; users cannot change their own role or deactivate themselves.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "User ID"
+// @Param request body UpdateUserRequest true "Update user request"
+// @Success 200 {object} Response{data=HashIDResponse}
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /users/{id} [put]
+func (h *UserHandler) UpdateUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format")
+	}
+
+	var req UpdateUserRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	user, err := h.userService.UpdateUser(ctx, id, interfaces.UpdateUserRequest{
+		Username: req.Username,
+		Role:     req.Role,
+		Active:   req.Active,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update user", "error", err, "id", id)
+		return userErrorResponse(c, err, "Failed to update user")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "User updated successfully", userResponse(user))
+}
+
+// ResetPassword handles resetting a user's password
+// @Summary Reset a user's password
+// @Description Set a new password for a user of the tenant without knowing the current one
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "User ID"
+// @Param request body ResetPasswordRequest true "Reset password request"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/{id}/reset-password [post]
+func (h *UserHandler) ResetPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format")
+	}
+
+	var req ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WarnContext(ctx, "invalid request body", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.WarnContext(ctx, "validation failed", "error", err)
+		return ErrorResponse(c, http.StatusBadRequest, "Validation failed")
+	}
+
+	if err := h.userService.ResetPassword(ctx, id, req.Password); err != nil {
+		h.logger.ErrorContext(ctx, "failed to reset password", "error", err, "id", id)
+		return userErrorResponse(c, err, "Failed to reset password")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// UnlockUser handles lifting a user's login lockouts
+// @Summary Unlock a user
+// @Description Clear the failed password login count, backoff and lockout of a user, whether or not the logins named the tenant, and the user's PIN lockout
+// @Tags Users
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/{id}/unlock [post]
+func (h *UserHandler) UnlockUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format")
+	}
+
+	if err := h.userService.UnlockUser(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to unlock user", "error", err, "id", id)
+		return userErrorResponse(c, err, "Failed to unlock user")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "User unlocked successfully", nil)
+}
+
+// DeleteUser handles deleting a user
+// @Summary Delete a user
+// @Description Delete a user with no recorded sales, stock or purchasing activity. Deactivate users that have history instead.
+// @Tags Users
+// @Produce json
+// @Security bearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := h.decodeID(c)
+	if err != nil {
+		return ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format")
+	}
+
+	if err := h.userService.DeleteUser(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete user", "error", err, "id", id)
+		return userErrorResponse(c, err, "Failed to delete user")
+	}
+
+	return SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
+}
+
+// decodeID decodes the hashed user ID from the URL
+func (h *UserHandler) decodeID(c echo.Context) (uint, error) {
+	hashedID := c.Param("id")
+	id, err := hash.DecodeHashID(hashedID)
+	if err != nil {
+		h.logger.WarnContext(c.Request().Context(), "invalid user ID format", "error", err, "hashed_id", hashedID)
+		return 0, err
+	}
+	return id, nil
+}
+
+// userErrorResponse maps user management errors to HTTP status codes
+func userErrorResponse(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorResponse(c, http.StatusNotFound, "User not found")
+	case errors.Is(err, entities.ErrForbidden):
+		return ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrInvalidRole), errors.Is(err, entities.ErrInvalidUser):
+		return ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entities.ErrUsernameTaken), errors.Is(err, entities.ErrUserSelfChange), errors.Is(err, entities.ErrUserHasHistory):
+		return ErrorResponse(c, http.StatusConflict, err.Error())
+	}
+	return ErrorResponse(c, http.StatusInternalServerError, message)
+}
+
+// userResponse flattens a user with hashed IDs for API responses
+func userResponse(u *entities.User) HashIDResponse {
+	return WithHashID(
+		u.ID,
+		u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		u.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		map[string]interface{}{
+			"username": u.Username,
+			"role":     u.Role,
+			"active":   u.Active,
+			"has_pin":  u.HasPIN(),
+		},
+	)
+}
@@ -31,9 +31,48 @@ func AutoMigrate(db *gorm.DB, log *slog.Logger) error {
 
 	if err := db.AutoMigrate(
 		&entities.User{},
+		&entities.Role{},
+		&entities.RolePermission{},
+		&entities.Terminal{},
+		&entities.Session{},
+		&entities.RevokedToken{},
+		&entities.LoginAttempt{},
+		&entities.AuthAuditLog{},
+		&entities.Category{},
+		&entities.Tag{},
 		&entities.Product{},
+		&entities.Customer{},
+		&entities.LoyaltyProgram{},
+		&entities.LoyaltyTier{},
+		&entities.LoyaltyLedgerEntry{},
+		&entities.Shift{},
+		&entities.ShiftCashEvent{},
+		&entities.ShiftCount{},
+		&entities.ProductBarcode{},
+		&entities.ProductOption{},
+		&entities.ProductOptionValue{},
+		&entities.ProductComponent{},
+		&entities.ModifierGroup{},
+		&entities.ModifierOption{},
+		&entities.Promotion{},
+		&entities.Voucher{},
 		&entities.Transaction{},
 		&entities.TransactionItem{},
+		&entities.TransactionItemDeduction{},
+		&entities.TransactionItemModifier{},
+		&entities.TransactionItemPromotion{},
+		&entities.VoucherRedemption{},
+		&entities.TransactionPayment{},
+		&entities.TransactionRefund{},
+		&entities.TransactionRefundItem{},
+		&entities.StockMovement{},
+		&entities.StockOpname{},
+		&entities.StockOpnameItem{},
+		&entities.Supplier{},
+		&entities.PurchaseOrder{},
+		&entities.PurchaseOrderItem{},
+		&entities.GoodsReceipt{},
+		&entities.GoodsReceiptItem{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -0,0 +1,108 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DBStore keeps login counters in the login_attempts table so that every
+// instance behind a load balancer sees the same counts
+type DBStore struct {
+	db *gorm.DB
+}
+
+// NewDBStore creates a store backed by db
+func NewDBStore(db *gorm.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// Get returns the attempts recorded for key
+func (s *DBStore) Get(ctx context.Context, key string) (Attempts, error) {
+	var row entities.LoginAttempt
+	if err := s.db.WithContext(ctx).Where("attempt_key = ?", key).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Attempts{}, nil
+		}
+		return Attempts{}, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	return fromRow(row), nil
+}
+
+// RecordFailure counts a failed login for key, locking the row so concurrent
+// failures on other instances are counted too
+func (s *DBStore) RecordFailure(ctx context.Context, key string, policy Policy, now time.Time) (Attempts, error) {
+	var attempts Attempts
+
+	// Only the key's own row is touched; a counter idle for longer than the window starts
+	// over in policy.apply, and Sweep removes idle rows of other keys
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.LoginAttempt{Key: key}).Error; err != nil {
+			return err
+		}
+
+		var row entities.LoginAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("attempt_key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		attempts = fromRow(row)
+		policy.apply(&attempts, now)
+
+		lastFailureAt := attempts.LastFailureAt
+		row.Failures = attempts.Failures
+		row.LastFailureAt = &lastFailureAt
+		row.BlockedUntil = nil
+		if !attempts.BlockedUntil.IsZero() {
+			blockedUntil := attempts.BlockedUntil
+			row.BlockedUntil = &blockedUntil
+		}
+		row.Locked = attempts.Locked
+		return tx.Save(&row).Error
+	})
+	if err != nil {
+		return Attempts{}, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// Reset forgets the attempts recorded for key
+func (s *DBStore) Reset(ctx context.Context, key string) error {
+	if err := s.db.WithContext(ctx).Where("attempt_key = ?", key).Delete(&entities.LoginAttempt{}).Error; err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Sweep deletes counters that are not blocked and have had no failure within window at now,
+// so the table does not grow unbounded. Run it periodically rather than on every failure,
+// where a table-wide delete would contend with concurrent failures for locks.
+func (s *DBStore) Sweep(ctx context.Context, window time.Duration, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("last_failure_at < ? AND (blocked_until IS NULL OR blocked_until < ?)", now.Add(-window), now).
+		Delete(&entities.LoginAttempt{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to sweep login attempts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func fromRow(row entities.LoginAttempt) Attempts {
+	attempts := Attempts{
+		Failures: row.Failures,
+		Locked:   row.Locked,
+	}
+	if row.LastFailureAt != nil {
+		attempts.LastFailureAt = *row.LastFailureAt
+	}
+	if row.BlockedUntil != nil {
+		attempts.BlockedUntil = *row.BlockedUntil
+	}
+	return attempts
+}
@@ -0,0 +1,208 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+)
+
+// Policy decides how failed logins for one key are slowed down and locked out
+type Policy struct {
+	Window          time.Duration // failures are forgotten after this long without another
+	BackoffAfter    int           // failures allowed before delays start
+	BaseDelay       time.Duration // first delay, doubled on every further failure
+	MaxDelay        time.Duration
+	LockoutAfter    int // failures that lock the key out
+	LockoutDuration time.Duration
+}
+
+// DefaultUserPolicy slows down password guessing against one username and
+// locks the username out after 10 failures in a row
+var DefaultUserPolicy = Policy{
+	Window:          time.Hour,
+	BackoffAfter:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAfter:    10,
+	LockoutDuration: 30 * time.Minute,
+}
+
+// DefaultIPPolicy slows down one address trying many usernames. Its limits are
+// higher because a store's tills often share one public address.
+var DefaultIPPolicy = Policy{
+	Window:          time.Hour,
+	BackoffAfter:    20,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAfter:    100,
+	LockoutDuration: 30 * time.Minute,
+}
+
+// apply counts one more failure at now and sets the resulting backoff or lockout
+func (p Policy) apply(a *Attempts, now time.Time) {
+	if !a.Blocked(now) && now.Sub(a.LastFailureAt) > p.Window {
+		*a = Attempts{}
+	}
+
+	a.Failures++
+	a.LastFailureAt = now
+	a.Locked = false
+
+	switch {
+	case p.LockoutAfter > 0 && a.Failures >= p.LockoutAfter:
+		a.BlockedUntil = now.Add(p.LockoutDuration)
+		a.Locked = true
+	case a.Failures > p.BackoffAfter:
+		delay := p.MaxDelay
+		if shift := a.Failures - p.BackoffAfter - 1; shift < 30 {
+			delay = min(p.BaseDelay<<shift, p.MaxDelay)
+		}
+		a.BlockedUntil = now.Add(delay)
+	}
+}
+
+// BlockedError reports a login refused because of earlier failures
+type BlockedError struct {
+	Until  time.Time
+	Locked bool
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s until %s", e.Unwrap(), e.Until.Format(time.RFC3339))
+}
+
+// Unwrap returns entities.ErrAccountLocked for lockouts and entities.ErrLoginThrottled for delays
+func (e *BlockedError) Unwrap() error {
+	if e.Locked {
+		return entities.ErrAccountLocked
+	}
+	return entities.ErrLoginThrottled
+}
+
+// RetryAfter returns how long the caller has to wait from now, rounded up to a second
+func (e *BlockedError) RetryAfter(now time.Time) time.Duration {
+	wait := e.Until.Sub(now)
+	if wait < time.Second {
+		return time.Second
+	}
+	return wait.Round(time.Second)
+}
+
+// Account is the user a login is counted against. Usernames are only unique within a
+// tenant, so an account is a tenant and a username; TenantID is 0 for users without a tenant.
+type Account struct {
+	TenantID uint
+	Username string
+}
+
+// Guard tracks failed password logins per account and per IP address
+type Guard struct {
+	store      Store
+	userPolicy Policy
+	ipPolicy   Policy
+}
+
+// NewGuard creates a guard that keeps its counters in store
+func NewGuard(store Store, userPolicy, ipPolicy Policy) *Guard {
+	return &Guard{
+		store:      store,
+		userPolicy: userPolicy,
+		ipPolicy:   ipPolicy,
+	}
+}
+
+// Check returns a *BlockedError if the address or any of the accounts may not try to log in yet
+func (g *Guard) Check(ctx context.Context, ipAddress string, accounts ...Account) error {
+	now := time.Now()
+	for _, key := range g.keys(ipAddress, accounts) {
+		attempts, err := g.store.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to check login attempts: %w", err)
+		}
+		if attempts.Blocked(now) {
+			return &BlockedError{Until: attempts.BlockedUntil, Locked: attempts.Locked}
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login for the address and each of the accounts. It
+// returns a *BlockedError when the failure locked any of them out.
+func (g *Guard) RecordFailure(ctx context.Context, ipAddress string, accounts ...Account) error {
+	now := time.Now()
+	var lockout *BlockedError
+
+	record := func(key string, policy Policy) error {
+		attempts, err := g.store.RecordFailure(ctx, key, policy, now)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+		if attempts.Locked && lockout == nil {
+			lockout = &BlockedError{Until: attempts.BlockedUntil, Locked: true}
+		}
+		return nil
+	}
+
+	for _, account := range accounts {
+		if err := record(userKey(account), g.userPolicy); err != nil {
+			return err
+		}
+	}
+	if ipAddress != "" {
+		if err := record(ipKey(ipAddress), g.ipPolicy); err != nil {
+			return err
+		}
+	}
+
+	if lockout != nil {
+		return lockout
+	}
+	return nil
+}
+
+// RecordSuccess clears the failures of an account after a successful login.
+// The address keeps its count so one known password cannot reset it.
+func (g *Guard) RecordSuccess(ctx context.Context, account Account) error {
+	if err := g.store.Reset(ctx, userKey(account)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Unlock clears the failures, backoff and lockout of an address and/or accounts
+func (g *Guard) Unlock(ctx context.Context, ipAddress string, accounts ...Account) error {
+	for _, key := range g.keys(ipAddress, accounts) {
+		if err := g.store.Reset(ctx, key); err != nil {
+			return fmt.Errorf("failed to unlock login: %w", err)
+		}
+	}
+	return nil
+}
+
+func (g *Guard) keys(ipAddress string, accounts []Account) []string {
+	var keys []string
+	if ipAddress != "" {
+		keys = append(keys, ipKey(ipAddress))
+	}
+	for _, account := range accounts {
+		keys = append(keys, userKey(account))
+	}
+	return keys
+}
+
+// userKey counts usernames case-insensitively. The prefixes keep the keys of
+// tenants' users, users without a tenant and addresses apart.
+func userKey(account Account) string {
+	username := strings.ToLower(strings.TrimSpace(account.Username))
+	if account.TenantID != 0 {
+		return fmt.Sprintf("tenant:%d:user:%s", account.TenantID, username)
+	}
+	return "user:" + username
+}
+
+func ipKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
@@ -0,0 +1,320 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+)
+
+var kasir = Account{TenantID: 1, Username: "kasir"}
+
+var testPolicy = Policy{
+	Window:          time.Hour,
+	BackoffAfter:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        10 * time.Second,
+	LockoutAfter:    8,
+	LockoutDuration: 30 * time.Minute,
+}
+
+func TestPolicyDelayCurve(t *testing.T) {
+	tests := []struct {
+		failures  int
+		wantDelay time.Duration
+		wantLock  bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, 0, false},
+		{4, time.Second, false},
+		{5, 2 * time.Second, false},
+		{6, 4 * time.Second, false},
+		{7, 8 * time.Second, false},
+		{8, 30 * time.Minute, true},
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	var a Attempts
+	for _, tt := range tests {
+		testPolicy.apply(&a, now)
+
+		if a.Failures != tt.failures {
+			t.Fatalf("failures = %d, want %d", a.Failures, tt.failures)
+		}
+		var delay time.Duration
+		if a.Blocked(now) {
+			delay = a.BlockedUntil.Sub(now)
+		}
+		if delay != tt.wantDelay {
+			t.Errorf("after %d failures: delay = %v, want %v", tt.failures, delay, tt.wantDelay)
+		}
+		if a.Locked != tt.wantLock {
+			t.Errorf("after %d failures: locked = %v, want %v", tt.failures, a.Locked, tt.wantLock)
+		}
+
+		// The next failure arrives once the delay has passed
+		now = now.Add(delay)
+	}
+}
+
+func TestPolicyDelayIsCapped(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{"reaches the cap", 8, 10 * time.Second},
+		{"stays at the cap", 20, 10 * time.Second},
+		{"does not overflow", 200, 10 * time.Second},
+	}
+
+	policy := testPolicy
+	policy.LockoutAfter = 0 // never lock out
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+			a := Attempts{Failures: tt.failures - 1, LastFailureAt: now}
+			policy.apply(&a, now)
+
+			if got := a.BlockedUntil.Sub(now); got != tt.want {
+				t.Errorf("delay = %v, want %v", got, tt.want)
+			}
+			if a.Locked {
+				t.Error("locked without a lockout threshold")
+			}
+		})
+	}
+}
+
+func TestPolicyWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		before       Attempts
+		wantFailures int
+	}{
+		{
+			name:         "failure within the window is added",
+			before:       Attempts{Failures: 2, LastFailureAt: now.Add(-30 * time.Minute)},
+			wantFailures: 3,
+		},
+		{
+			name:         "failure after the window starts over",
+			before:       Attempts{Failures: 7, LastFailureAt: now.Add(-2 * time.Hour)},
+			wantFailures: 1,
+		},
+		{
+			name: "lockout outlasting the window is not forgotten",
+			before: Attempts{
+				Failures:      8,
+				LastFailureAt: now.Add(-2 * time.Hour),
+				BlockedUntil:  now.Add(time.Minute),
+				Locked:        true,
+			},
+			wantFailures: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.before
+			testPolicy.apply(&a, now)
+			if a.Failures != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", a.Failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestGuardLockoutThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		wantLocked bool
+	}{
+		{"below the threshold", testPolicy.LockoutAfter - 1, false},
+		{"at the threshold", testPolicy.LockoutAfter, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			guard := NewGuard(NewMemoryStore(time.Hour), testPolicy, DefaultIPPolicy)
+
+			var err error
+			for i := 0; i < tt.failures; i++ {
+				err = guard.RecordFailure(ctx, "192.0.2.1", kasir)
+			}
+
+			var blocked *BlockedError
+			if locked := errors.As(err, &blocked) && blocked.Locked; locked != tt.wantLocked {
+				t.Errorf("last RecordFailure locked = %v, want %v (err %v)", locked, tt.wantLocked, err)
+			}
+
+			err = guard.Check(ctx, "192.0.2.1", kasir)
+			if tt.wantLocked {
+				if !errors.Is(err, entities.ErrAccountLocked) {
+					t.Errorf("Check = %v, want %v", err, entities.ErrAccountLocked)
+				}
+				return
+			}
+			// Below the threshold the username is only delayed
+			if !errors.Is(err, entities.ErrLoginThrottled) {
+				t.Errorf("Check = %v, want %v", err, entities.ErrLoginThrottled)
+			}
+		})
+	}
+}
+
+func TestGuardIPPolicy(t *testing.T) {
+	ctx := context.Background()
+	ipPolicy := Policy{Window: time.Hour, BackoffAfter: 2, BaseDelay: time.Minute, MaxDelay: time.Minute}
+	guard := NewGuard(NewMemoryStore(time.Hour), testPolicy, ipPolicy)
+
+	// Different usernames from one address add up on the address
+	for _, username := range []string{"a", "b", "c"} {
+		if err := guard.RecordFailure(ctx, "192.0.2.2", Account{TenantID: 1, Username: username}); err != nil {
+			t.Fatalf("RecordFailure(%s) = %v", username, err)
+		}
+	}
+
+	if err := guard.Check(ctx, "192.0.2.2", Account{TenantID: 1, Username: "d"}); !errors.Is(err, entities.ErrLoginThrottled) {
+		t.Errorf("Check from the address = %v, want %v", err, entities.ErrLoginThrottled)
+	}
+	if err := guard.Check(ctx, "192.0.2.3", Account{TenantID: 1, Username: "d"}); err != nil {
+		t.Errorf("Check from another address = %v, want nil", err)
+	}
+}
+
+func TestGuardRecordSuccess(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Hour)
+	guard := NewGuard(store, testPolicy, DefaultIPPolicy)
+
+	for i := 0; i < 3; i++ {
+		if err := guard.RecordFailure(ctx, "192.0.2.4", kasir); err != nil {
+			t.Fatalf("RecordFailure = %v", err)
+		}
+	}
+	if err := guard.RecordSuccess(ctx, Account{TenantID: 1, Username: "Kasir"}); err != nil {
+		t.Fatalf("RecordSuccess = %v", err)
+	}
+
+	user, _ := store.Get(ctx, userKey(kasir))
+	if user.Failures != 0 {
+		t.Errorf("username failures after success = %d, want 0", user.Failures)
+	}
+	// The address keeps its count so one known password cannot reset it
+	ip, _ := store.Get(ctx, ipKey("192.0.2.4"))
+	if ip.Failures != 3 {
+		t.Errorf("address failures after success = %d, want 3", ip.Failures)
+	}
+}
+
+func TestGuardUnlock(t *testing.T) {
+	tests := []struct {
+		name          string
+		accounts      []Account
+		ipAddress     string
+		wantUserClear bool
+		wantIPClear   bool
+	}{
+		{"account", []Account{kasir}, "", true, false},
+		{"address", nil, "192.0.2.5", false, true},
+		{"both", []Account{{TenantID: 1, Username: "KASIR "}}, "192.0.2.5", true, true},
+		{"account of another tenant", []Account{{TenantID: 2, Username: "kasir"}}, "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewMemoryStore(time.Hour)
+			guard := NewGuard(store, testPolicy, testPolicy)
+
+			for i := 0; i < testPolicy.LockoutAfter; i++ {
+				guard.RecordFailure(ctx, "192.0.2.5", kasir)
+			}
+			if err := guard.Unlock(ctx, tt.ipAddress, tt.accounts...); err != nil {
+				t.Fatalf("Unlock = %v", err)
+			}
+
+			user, _ := store.Get(ctx, userKey(kasir))
+			if cleared := user.Failures == 0; cleared != tt.wantUserClear {
+				t.Errorf("username cleared = %v, want %v", cleared, tt.wantUserClear)
+			}
+			ip, _ := store.Get(ctx, ipKey("192.0.2.5"))
+			if cleared := ip.Failures == 0; cleared != tt.wantIPClear {
+				t.Errorf("address cleared = %v, want %v", cleared, tt.wantIPClear)
+			}
+		})
+	}
+}
+
+func TestGuardAccounts(t *testing.T) {
+	ctx := context.Background()
+	guard := NewGuard(NewMemoryStore(time.Hour), testPolicy, DefaultIPPolicy)
+
+	for i := 0; i < testPolicy.LockoutAfter; i++ {
+		guard.RecordFailure(ctx, "", kasir)
+	}
+
+	tests := []struct {
+		name    string
+		account Account
+		want    error
+	}{
+		{"same account is locked", kasir, entities.ErrAccountLocked},
+		{"username is case-insensitive", Account{TenantID: 1, Username: " Kasir"}, entities.ErrAccountLocked},
+		{"same username in another tenant is not", Account{TenantID: 2, Username: "kasir"}, nil},
+		{"same username without a tenant is not", Account{Username: "kasir"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := guard.Check(ctx, "", tt.account)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// A failure tried against several accounts counts once for each of them
+	other := Account{TenantID: 2, Username: "kasir"}
+	guard.RecordFailure(ctx, "", kasir, other)
+	store := guard.store.(*MemoryStore)
+	if got, _ := store.Get(ctx, userKey(other)); got.Failures != 1 {
+		t.Errorf("failures of the other account = %d, want 1", got.Failures)
+	}
+}
+
+func TestBlockedError(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		err       *BlockedError
+		wantErr   error
+		wantRetry time.Duration
+	}{
+		{"delay", &BlockedError{Until: now.Add(4 * time.Second)}, entities.ErrLoginThrottled, 4 * time.Second},
+		{"lockout", &BlockedError{Until: now.Add(30 * time.Minute), Locked: true}, entities.ErrAccountLocked, 30 * time.Minute},
+		{"rounds to a second", &BlockedError{Until: now.Add(1600 * time.Millisecond)}, entities.ErrLoginThrottled, 2 * time.Second},
+		{"never less than a second", &BlockedError{Until: now.Add(10 * time.Millisecond)}, entities.ErrLoginThrottled, time.Second},
+		{"already over", &BlockedError{Until: now.Add(-time.Second)}, entities.ErrLoginThrottled, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.wantErr) {
+				t.Errorf("errors.Is(%v, %v) = false", tt.err, tt.wantErr)
+			}
+			if got := tt.err.RetryAfter(now); got != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantRetry)
+			}
+		})
+	}
+}
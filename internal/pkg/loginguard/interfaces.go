@@ -0,0 +1,30 @@
+package loginguard
+
+import (
+	"context"
+	"time"
+)
+
+// Attempts is the failed login record kept for one username or IP address
+type Attempts struct {
+	Failures      int
+	LastFailureAt time.Time
+	BlockedUntil  time.Time // logins for the key are refused until then
+	Locked        bool      // the block is a lockout rather than a backoff delay
+}
+
+// Blocked reports whether logins for the key are refused at now
+func (a Attempts) Blocked(now time.Time) bool {
+	return now.Before(a.BlockedUntil)
+}
+
+// Store keeps failed login counters. Use MemoryStore for a single instance and
+// DBStore when several instances must share the counters.
+type Store interface {
+	// Get returns the attempts recorded for key, or zero Attempts if there are none
+	Get(ctx context.Context, key string) (Attempts, error)
+	// RecordFailure counts a failed login for key and applies policy to the new count
+	RecordFailure(ctx context.Context, key string, policy Policy, now time.Time) (Attempts, error)
+	// Reset forgets the attempts recorded for key
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,66 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepThreshold is the number of keys above which expired entries are swept
+const sweepThreshold = 10000
+
+// MemoryStore keeps login counters in process memory. Counters are lost on restart
+// and are not shared between instances.
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+	window   time.Duration
+}
+
+// NewMemoryStore creates an in-memory store that drops keys idle for longer than window
+func NewMemoryStore(window time.Duration) *MemoryStore {
+	return &MemoryStore{
+		attempts: make(map[string]Attempts),
+		window:   window,
+	}
+}
+
+// Get returns the attempts recorded for key
+func (s *MemoryStore) Get(ctx context.Context, key string) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts[key], nil
+}
+
+// RecordFailure counts a failed login for key
+func (s *MemoryStore) RecordFailure(ctx context.Context, key string, policy Policy, now time.Time) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.attempts) > sweepThreshold {
+		s.sweep(now)
+	}
+
+	attempts := s.attempts[key]
+	policy.apply(&attempts, now)
+	s.attempts[key] = attempts
+
+	return attempts, nil
+}
+
+// Reset forgets the attempts recorded for key
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
+
+// sweep drops keys that are not blocked and have had no failure within the window
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, attempts := range s.attempts {
+		if !attempts.Blocked(now) && now.Sub(attempts.LastFailureAt) > s.window {
+			delete(s.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+// LoadPermissions loads the logged-in user, rejects deactivated accounts and stores the
+// permissions of the user's current role in the request context, where RequirePermission
+// and the services check them. Reading the user on every request makes role changes and
+// deactivation apply without waiting for tokens to expire. It must run after the JWT middleware.
+func LoadPermissions(authService interfaces.AuthService, roleService interfaces.RoleService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			userID, _ := ctx.Value("user_id").(uint)
+
+			user, err := authService.GetUserByID(ctx, userID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{
+						"status":  "error",
+						"message": "User not found",
+					})
+				}
+				c.Logger().Errorf("failed to load user %d: %v", userID, err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"status":  "error",
+					"message": "Failed to load user",
+				})
+			}
+			if !user.Active {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"status":  "error",
+					"message": "User account is deactivated",
+				})
+			}
+
+			permissions, err := roleService.ResolvePermissions(ctx, user.Role)
+			if err != nil {
+				c.Logger().Errorf("failed to resolve permissions for role %q: %v", user.Role, err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"status":  "error",
+					"message": "Failed to resolve permissions",
+				})
+			}
+
+			c.Set("role", user.Role)
+			c.Set("permissions", permissions)
+			ctx = context.WithValue(ctx, "role", user.Role)
+			ctx = context.WithValue(ctx, "permissions", permissions)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// RequirePermission rejects requests whose role does not grant permission
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			permissions, _ := c.Get("permissions").(entities.PermissionSet)
+			if !permissions.Has(permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"status":  "error",
+					"message": "Permission denied",
+				})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+)
+
+// RejectRevokedTokens rejects access tokens that were revoked by a logout before they expired.
+// Tokens without a jti were issued before sessions existed and are rejected too, so clients
+// log in again and receive a refresh token. It must run after the JWT middleware.
+func RejectRevokedTokens(authService interfaces.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, _ := c.Get("user").(*jwt.Token)
+			var jti string
+			if token != nil {
+				if claims, ok := token.Claims.(jwt.MapClaims); ok {
+					jti, _ = claims["jti"].(string)
+				}
+			}
+			if jti == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"status":  "error",
+					"message": "Invalid token",
+				})
+			}
+
+			revoked, err := authService.IsTokenRevoked(c.Request().Context(), jti)
+			if err != nil {
+				c.Logger().Errorf("failed to check token %s: %v", jti, err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"status":  "error",
+					"message": "Failed to validate token",
+				})
+			}
+			if revoked {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"status":  "error",
+					"message": "Token has been revoked",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
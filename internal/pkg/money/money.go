@@ -0,0 +1,253 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (1/100 Rupiah), matching the decimal(10,2) columns
+type Money int64
+
+// Scale is the number of minor units in one major unit
+const Scale = 100
+
+// RoundingMode decides which way amounts move when rounded to an increment
+type RoundingMode string
+
+// Rounding modes
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+// Valid reports whether the mode is one of the known rounding modes
+func (r RoundingMode) Valid() bool {
+	switch r {
+	case RoundNearest, RoundUp, RoundDown:
+		return true
+	}
+	return false
+}
+
+// FromFloat converts a major unit float (e.g. 12000.5) to Money, rounding to the nearest minor unit
+func FromFloat(f float64) Money {
+	return Money(math.Round(f * Scale))
+}
+
+// FromInt converts a whole Rupiah amount to Money
+func FromInt(i int64) Money {
+	return Money(i * Scale)
+}
+
+// Parse parses a decimal string such as "12000.50", with at most one leading sign.
+// Digits beyond two decimals are rounded half up. Amounts that do not fit Money are rejected.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+
+	if strings.ContainsAny(s, "eE") {
+		return parseExponent(s)
+	}
+
+	unsigned := s
+	neg := false
+	if unsigned[0] == '+' || unsigned[0] == '-' {
+		neg = unsigned[0] == '-'
+		unsigned = unsigned[1:]
+	}
+
+	whole, frac, _ := strings.Cut(unsigned, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount %q: no digits", s)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	var cents int64
+	if len(frac) > 2 && frac[2] >= '5' {
+		cents++
+	}
+	if len(frac) > 2 {
+		frac = frac[:2]
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/Scale {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	cents += f
+	if w*Scale > math.MaxInt64-cents {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+
+	m := Money(w*Scale + cents)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// parseExponent parses an amount in exponent notation such as "1.5e3"
+func parseExponent(s string) (Money, error) {
+	if strings.Trim(s, "0123456789+-.eE") != "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	// math.MaxInt64 converts to 2^63, the first value that does not fit
+	if minor := math.Round(f * Scale); minor >= math.MaxInt64 || minor < math.MinInt64 {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	return FromFloat(f), nil
+}
+
+// isDigits reports whether s consists of ASCII digits only; the empty string does
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Float64 returns the amount in major units. Use it only for display, never for arithmetic.
+func (m Money) Float64() float64 {
+	return float64(m) / Scale
+}
+
+// String formats the amount with two decimals, e.g. "12000.50"
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/Scale, v%Scale)
+}
+
+// Mul multiplies the amount by a quantity
+func (m Money) Mul(qty int) Money {
+	return m * Money(qty)
+}
+
+// Div divides the amount by n, rounding half away from zero
+func (m Money) Div(n int) Money {
+	if n == 0 {
+		return 0
+	}
+	return Money(math.Round(float64(m) / float64(n)))
+}
+
+// Percent returns pct percent of the amount, rounded to the nearest minor unit
+func (m Money) Percent(pct float64) Money {
+	return Money(math.Round(float64(m) * pct / 100))
+}
+
+// ExcludePercent returns the amount before pct percent was added to it, e.g. the net of a tax-inclusive price
+func (m Money) ExcludePercent(pct float64) Money {
+	return Money(math.Round(float64(m) * 100 / (100 + pct)))
+}
+
+// Round rounds the amount to a multiple of increment. A zero increment leaves it unchanged.
+func (m Money) Round(increment Money, mode RoundingMode) Money {
+	if increment <= 0 {
+		return m
+	}
+
+	rem := m % increment
+	if rem == 0 {
+		return m
+	}
+	if rem < 0 {
+		rem += increment
+	}
+
+	down := m - rem
+	switch mode {
+	case RoundUp:
+		return down + increment
+	case RoundDown:
+		return down
+	}
+	if rem*2 >= increment {
+		return down + increment
+	}
+	return down
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string without going through float64
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*m = 0
+		return nil
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan implements sql.Scanner for decimal columns
+func (m *Money) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = FromInt(v)
+		return nil
+	case float64:
+		*m = FromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into money", value)
+}
+
+// Value implements driver.Valuer, storing the amount as a decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// GormDataType keeps auto-migrated columns as decimal(10,2)
+func (Money) GormDataType() string {
+	return "decimal(10,2)"
+}
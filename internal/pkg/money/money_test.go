@@ -0,0 +1,273 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"12000", 1200000},
+		{"12000.5", 1200050},
+		{"12000.50", 1200050},
+		{" 12000.05 ", 1200005},
+		{".75", 75},
+		{"+1.10", 110},
+		{"-1.10", -110},
+		{"1.004", 100},
+		{"1.005", 101},
+		{"1.0049", 100},
+		{"-1.005", -101},
+		{"-0.004", 0},
+		{"1e3", 100000},
+		{"1.5E-2", 2},
+		{"1.", 100},
+		{"+5", 500},
+		{"92233720368547758.07", math.MaxInt64},
+		{"-92233720368547758.07", -math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", "  ", "abc", "1.2.3", "1,000", "1.x", "1.00x",
+		"-", "+", ".", "-.", "+-3", "--3", "12.-5", "12.+5", "1 000",
+		"92233720368547758.08", "92233720368547759", "99999999999999999999",
+		"1e20", "-1e20", "0x1p3", "Inf", "NaN",
+	} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) returned no error", in)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1200050, "12000.50"},
+		{-5, "-0.05"},
+		{-110, "-1.10"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		m    Money
+		pct  float64
+		want Money
+	}{
+		{1000000, 11, 110000},
+		{1000000, 0, 0},
+		{1000000, 100, 1000000},
+		{333, 10, 33},   // 33.3 rounds down
+		{335, 10, 34},   // 33.5 rounds half away from zero
+		{5, 10, 1},      // 0.5 rounds half away from zero
+		{-5, 10, -1},    // -0.5 rounds half away from zero
+		{-335, 10, -34}, // -33.5 rounds half away from zero
+		{150, 2.5, 4},   // 3.75
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.Percent(tt.pct); got != tt.want {
+			t.Errorf("Money(%d).Percent(%v) = %d, want %d", tt.m, tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestExcludePercent(t *testing.T) {
+	tests := []struct {
+		m    Money
+		pct  float64
+		want Money
+	}{
+		{111000, 11, 100000},
+		{100000, 0, 100000},
+		{1000, 11, 901}, // 900.9
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.ExcludePercent(tt.pct); got != tt.want {
+			t.Errorf("Money(%d).ExcludePercent(%v) = %d, want %d", tt.m, tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		m    Money
+		n    int
+		want Money
+	}{
+		{100, 4, 25},
+		{100, 3, 33},
+		{5, 2, 3},   // 2.5 rounds half away from zero
+		{-5, 2, -3}, // -2.5 rounds half away from zero
+		{100, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.Div(tt.n); got != tt.want {
+			t.Errorf("Money(%d).Div(%d) = %d, want %d", tt.m, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	hundred := FromInt(100)
+
+	tests := []struct {
+		name      string
+		m         Money
+		increment Money
+		mode      RoundingMode
+		want      Money
+	}{
+		{"zero increment", FromInt(12345), 0, RoundNearest, FromInt(12345)},
+		{"negative increment", FromInt(12345), -hundred, RoundUp, FromInt(12345)},
+		{"already a multiple", FromInt(12300), hundred, RoundUp, FromInt(12300)},
+
+		{"nearest below half", FromInt(12349), hundred, RoundNearest, FromInt(12300)},
+		{"nearest half way", FromInt(12350), hundred, RoundNearest, FromInt(12400)},
+		{"nearest above half", FromInt(12351), hundred, RoundNearest, FromInt(12400)},
+		{"nearest just below half", FromInt(12350) - 1, hundred, RoundNearest, FromInt(12300)},
+		{"unknown mode rounds to nearest", FromInt(12350), hundred, "", FromInt(12400)},
+
+		{"up", FromInt(12301), hundred, RoundUp, FromInt(12400)},
+		{"up by one minor unit", FromInt(12300) + 1, hundred, RoundUp, FromInt(12400)},
+		{"down", FromInt(12399), hundred, RoundDown, FromInt(12300)},
+
+		{"negative nearest below half", FromInt(-12349), hundred, RoundNearest, FromInt(-12300)},
+		{"negative nearest half way", FromInt(-12350), hundred, RoundNearest, FromInt(-12300)},
+		{"negative nearest above half", FromInt(-12351), hundred, RoundNearest, FromInt(-12400)},
+		{"negative up", FromInt(-12399), hundred, RoundUp, FromInt(-12300)},
+		{"negative down", FromInt(-12301), hundred, RoundDown, FromInt(-12400)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Round(tt.increment, tt.mode); got != tt.want {
+				t.Errorf("Money(%d).Round(%d, %q) = %d, want %d", tt.m, tt.increment, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundingModeValid(t *testing.T) {
+	for _, mode := range []RoundingMode{RoundNearest, RoundUp, RoundDown} {
+		if !mode.Valid() {
+			t.Errorf("RoundingMode(%q).Valid() = false, want true", mode)
+		}
+	}
+	for _, mode := range []RoundingMode{"", "NEAREST", "half_up", "ceil"} {
+		if mode.Valid() {
+			t.Errorf("RoundingMode(%q).Valid() = true, want false", mode)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{`12000.5`, 1200050},
+		{`"12000.50"`, 1200050},
+		{`0.1`, 10},
+		{`-3.005`, -301},
+		{`null`, 0},
+		{`""`, 0},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	var bad Money
+	if err := json.Unmarshal([]byte(`"abc"`), &bad); err == nil {
+		t.Error(`Unmarshal("abc") returned no error`)
+	}
+
+	out, err := json.Marshal(struct {
+		Total Money `json:"total"`
+	}{Total: 1200050})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `{"total":12000.50}`; string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		in   any
+		want Money
+	}{
+		{nil, 0},
+		{[]byte("12000.50"), 1200050},
+		{"99.99", 9999},
+		{int64(12), 1200},
+		{float64(1.25), 125},
+		{[]byte("-0.5"), -50},
+	}
+
+	for _, tt := range tests {
+		m := Money(42)
+		if err := m.Scan(tt.in); err != nil {
+			t.Errorf("Scan(%#v) returned error: %v", tt.in, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("Scan(%#v) = %d, want %d", tt.in, m, tt.want)
+		}
+	}
+
+	var m Money
+	if err := m.Scan(true); err == nil {
+		t.Error("Scan(true) returned no error")
+	}
+	if err := m.Scan([]byte("abc")); err == nil {
+		t.Error(`Scan("abc") returned no error`)
+	}
+
+	v, err := Money(1200050).Value()
+	if err != nil {
+		t.Fatalf("Value returned error: %v", err)
+	}
+	if v != "12000.50" {
+		t.Errorf("Value = %v, want 12000.50", v)
+	}
+}
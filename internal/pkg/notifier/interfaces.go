@@ -0,0 +1,20 @@
+package notifier
+
+import "context"
+
+// LowStockAlert describes a product whose stock fell to its reorder point
+type LowStockAlert struct {
+	TenantID     uint   `json:"tenant_id"`
+	ProductID    string `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	SKU          string `json:"sku"`
+	Stock        int    `json:"stock"`
+	ReorderPoint int    `json:"reorder_point"`
+	ReorderQty   int    `json:"reorder_qty"`
+}
+
+// Notifier delivers stock alerts to staff
+type Notifier interface {
+	// NotifyLowStock sends alerts for products that reached their reorder point
+	NotifyLowStock(ctx context.Context, alerts []LowStockAlert) error
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogNotifier writes alerts to the application log
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier creates a notifier that logs alerts
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// NotifyLowStock logs one warning per alert
+func (n *LogNotifier) NotifyLowStock(ctx context.Context, alerts []LowStockAlert) error {
+	for _, alert := range alerts {
+		n.logger.WarnContext(ctx, "product reached reorder point",
+			"tenant_id", alert.TenantID,
+			"product_id", alert.ProductID,
+			"sku", alert.SKU,
+			"stock", alert.Stock,
+			"reorder_point", alert.ReorderPoint,
+			"reorder_qty", alert.ReorderQty,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type authAuditRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewAuthAuditRepository creates a new authentication audit log repository
+func NewAuthAuditRepository(db *gorm.DB, logger *slog.Logger) interfaces.AuthAuditRepository {
+	return &authAuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create records an authentication audit entry
+func (r *authAuditRepository) Create(ctx context.Context, entry *entities.AuthAuditLog) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create auth audit entry", "error", err, "event", entry.Event)
+		return fmt.Errorf("failed to create auth audit entry: %w", err)
+	}
+	return nil
+}
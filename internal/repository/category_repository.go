@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *gorm.DB, logger *slog.Logger) interfaces.CategoryRepository {
+	return &categoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new category
+func (r *categoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	r.logger.InfoContext(ctx, "creating category", "name", category.Name)
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create category", "error", err)
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a category by ID
+func (r *categoryRepository) GetByID(ctx context.Context, id uint) (*entities.Category, error) {
+	r.logger.InfoContext(ctx, "getting category by ID", "id", id)
+
+	var category entities.Category
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get category", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return &category, nil
+}
+
+// List retrieves all categories of the tenant, ordered by name.
+// Categories are returned flat; callers build the tree from ParentID.
+func (r *categoryRepository) List(ctx context.Context) ([]entities.Category, error) {
+	r.logger.InfoContext(ctx, "listing categories")
+
+	var categories []entities.Category
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", ctx.Value("tenant_id")).Order("name").Find(&categories).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list categories", "error", err)
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Update updates a category
+func (r *categoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	r.logger.InfoContext(ctx, "updating category", "id", category.ID)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", category.ID, ctx.Value("tenant_id")).Omit("Parent", "Tenant").Save(category).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to update category", "error", err, "id", category.ID)
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a category
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting category", "id", id)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).Delete(&entities.Category{}).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete category", "error", err, "id", id)
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return nil
+}
+
+// CountProducts counts the products directly assigned to a category
+func (r *categoryRepository) CountProducts(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Product{}).Where("category_id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).Count(&count).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count category products", "error", err, "id", id)
+		return 0, fmt.Errorf("failed to count category products: %w", err)
+	}
+	return count, nil
+}
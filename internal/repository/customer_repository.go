@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type customerRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *gorm.DB, logger *slog.Logger) interfaces.CustomerRepository {
+	return &customerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new customer
+func (r *customerRepository) Create(ctx context.Context, customer *entities.Customer) error {
+	r.logger.InfoContext(ctx, "creating customer", "name", customer.Name)
+	if err := r.db.WithContext(ctx).Create(customer).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create customer", "error", err)
+		return fmt.Errorf("failed to create customer: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a customer by ID
+func (r *customerRepository) GetByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	r.logger.InfoContext(ctx, "getting customer by ID", "id", id)
+
+	var customer entities.Customer
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&customer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("customer not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get customer", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return &customer, nil
+}
+
+// GetByPhone retrieves a customer by phone number
+func (r *customerRepository) GetByPhone(ctx context.Context, phone string) (*entities.Customer, error) {
+	r.logger.InfoContext(ctx, "getting customer by phone")
+
+	var customer entities.Customer
+	if err := r.db.WithContext(ctx).Where("phone = ? AND tenant_id = ?", phone, ctx.Value("tenant_id")).First(&customer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("customer not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get customer by phone", "error", err)
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return &customer, nil
+}
+
+// List retrieves customers with pagination, ordered by name. A non-empty search matches
+// name or phone.
+func (r *customerRepository) List(ctx context.Context, search string, page, limit int) ([]entities.Customer, int64, error) {
+	r.logger.InfoContext(ctx, "listing customers", "search", search, "page", page, "limit", limit)
+
+	var customers []entities.Customer
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Customer{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name LIKE ? OR phone LIKE ?", like, like)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count customers", "error", err)
+		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("name").Offset(offset).Limit(limit).Find(&customers).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list customers", "error", err)
+		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+	}
+
+	return customers, total, nil
+}
+
+// Update updates a customer
+func (r *customerRepository) Update(ctx context.Context, customer *entities.Customer) error {
+	r.logger.InfoContext(ctx, "updating customer", "id", customer.ID)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", customer.ID, ctx.Value("tenant_id")).Save(customer).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to update customer", "error", err, "id", customer.ID)
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a customer. Their past sales are kept and only lose the link.
+func (r *customerRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting customer", "id", id)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Transaction{}).
+			Where("customer_id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).
+			Update("customer_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).Delete(&entities.Customer{}).Error
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete customer", "error", err, "id", id)
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+	return nil
+}
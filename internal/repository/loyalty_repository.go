@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type loyaltyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewLoyaltyRepository creates a new loyalty repository
+func NewLoyaltyRepository(db *gorm.DB, logger *slog.Logger) interfaces.LoyaltyRepository {
+	return &loyaltyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetProgram retrieves the tenant's loyalty program with its tiers, lowest first
+func (r *loyaltyRepository) GetProgram(ctx context.Context) (*entities.LoyaltyProgram, error) {
+	r.logger.InfoContext(ctx, "getting loyalty program")
+
+	var program entities.LoyaltyProgram
+	if err := r.db.WithContext(ctx).Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_points")
+	}).Where("tenant_id = ?", ctx.Value("tenant_id")).First(&program).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("loyalty program not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get loyalty program", "error", err)
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	return &program, nil
+}
+
+// SaveProgram creates or updates the tenant's loyalty program and replaces its tiers
+func (r *loyaltyRepository) SaveProgram(ctx context.Context, program *entities.LoyaltyProgram) error {
+	r.logger.InfoContext(ctx, "saving loyalty program", "id", program.ID)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tiers := program.Tiers
+		if err := tx.Omit("Tiers", "Tenant").Save(program).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("program_id = ?", program.ID).Delete(&entities.LoyaltyTier{}).Error; err != nil {
+			return err
+		}
+		for i := range tiers {
+			tiers[i].ID = 0
+			tiers[i].ProgramID = program.ID
+		}
+		if len(tiers) > 0 {
+			if err := tx.Create(&tiers).Error; err != nil {
+				return err
+			}
+		}
+		program.Tiers = tiers
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to save loyalty program", "error", err)
+		return fmt.Errorf("failed to save loyalty program: %w", err)
+	}
+
+	return nil
+}
+
+// ListEntries retrieves a customer's points ledger with pagination, newest first
+func (r *loyaltyRepository) ListEntries(ctx context.Context, phone string, page, limit int) ([]entities.LoyaltyLedgerEntry, int64, error) {
+	r.logger.InfoContext(ctx, "listing loyalty ledger", "page", page, "limit", limit)
+
+	var entries []entities.LoyaltyLedgerEntry
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.LoyaltyLedgerEntry{}).Where("tenant_id = ? AND phone = ?", ctx.Value("tenant_id"), phone)
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count loyalty ledger", "error", err)
+		return nil, 0, fmt.Errorf("failed to count loyalty ledger: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&entries).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list loyalty ledger", "error", err)
+		return nil, 0, fmt.Errorf("failed to list loyalty ledger: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// AllEntries retrieves a customer's whole points ledger in the order it was written
+func (r *loyaltyRepository) AllEntries(ctx context.Context, phone string) ([]entities.LoyaltyLedgerEntry, error) {
+	var entries []entities.LoyaltyLedgerEntry
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND phone = ?", ctx.Value("tenant_id"), phone).Order("id").Find(&entries).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get loyalty ledger", "error", err)
+		return nil, fmt.Errorf("failed to get loyalty ledger: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type modifierGroupRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewModifierGroupRepository creates a new modifier group repository
+func NewModifierGroupRepository(db *gorm.DB, logger *slog.Logger) interfaces.ModifierGroupRepository {
+	return &modifierGroupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// preloadModifierOptions loads group options in display order
+func preloadModifierOptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") })
+}
+
+// Create creates a new modifier group with its options
+func (r *modifierGroupRepository) Create(ctx context.Context, group *entities.ModifierGroup) error {
+	r.logger.InfoContext(ctx, "creating modifier group", "name", group.Name)
+	if err := r.db.WithContext(ctx).Create(group).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create modifier group", "error", err)
+		return fmt.Errorf("failed to create modifier group: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a modifier group with its options by ID
+func (r *modifierGroupRepository) GetByID(ctx context.Context, id uint) (*entities.ModifierGroup, error) {
+	r.logger.InfoContext(ctx, "getting modifier group by ID", "id", id)
+
+	var group entities.ModifierGroup
+	if err := preloadModifierOptions(r.db.WithContext(ctx)).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&group).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("modifier group not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get modifier group", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get modifier group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// GetByIDs retrieves the tenant's modifier groups among ids; unknown IDs are skipped
+func (r *modifierGroupRepository) GetByIDs(ctx context.Context, ids []uint) ([]entities.ModifierGroup, error) {
+	var groups []entities.ModifierGroup
+	if err := r.db.WithContext(ctx).Where("id IN ? AND tenant_id = ?", ids, ctx.Value("tenant_id")).Find(&groups).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get modifier groups", "error", err)
+		return nil, fmt.Errorf("failed to get modifier groups: %w", err)
+	}
+	return groups, nil
+}
+
+// List retrieves all modifier groups of the tenant with their options, ordered by name
+func (r *modifierGroupRepository) List(ctx context.Context) ([]entities.ModifierGroup, error) {
+	r.logger.InfoContext(ctx, "listing modifier groups")
+
+	var groups []entities.ModifierGroup
+	if err := preloadModifierOptions(r.db.WithContext(ctx)).Where("tenant_id = ?", ctx.Value("tenant_id")).Order("name").Find(&groups).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list modifier groups", "error", err)
+		return nil, fmt.Errorf("failed to list modifier groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// Update saves a modifier group and syncs its options: options with an ID are updated,
+// new ones are created and options no longer present are removed
+func (r *modifierGroupRepository) Update(ctx context.Context, group *entities.ModifierGroup) error {
+	r.logger.InfoContext(ctx, "updating modifier group", "id", group.ID)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND tenant_id = ?", group.ID, ctx.Value("tenant_id")).Omit("Options", "Tenant").Save(group).Error; err != nil {
+			return err
+		}
+
+		keep := make([]uint, 0, len(group.Options))
+		for _, option := range group.Options {
+			if option.ID != 0 {
+				keep = append(keep, option.ID)
+			}
+		}
+		stale := tx.Where("group_id = ?", group.ID)
+		if len(keep) > 0 {
+			stale = stale.Where("id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&entities.ModifierOption{}).Error; err != nil {
+			return err
+		}
+
+		for i := range group.Options {
+			group.Options[i].GroupID = group.ID
+			if err := tx.Save(&group.Options[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update modifier group", "error", err, "id", group.ID)
+		return fmt.Errorf("failed to update modifier group: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a modifier group, its options and its product assignments
+func (r *modifierGroupRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting modifier group", "id", id)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var group entities.ModifierGroup
+		if err := tx.Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&group).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM product_modifier_groups WHERE modifier_group_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&entities.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&group).Error
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete modifier group", "error", err, "id", id)
+		return fmt.Errorf("failed to delete modifier group: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type productBarcodeRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewProductBarcodeRepository creates a new product barcode repository
+func NewProductBarcodeRepository(db *gorm.DB, logger *slog.Logger) interfaces.ProductBarcodeRepository {
+	return &productBarcodeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new product barcode
+func (r *productBarcodeRepository) Create(ctx context.Context, barcode *entities.ProductBarcode) error {
+	r.logger.InfoContext(ctx, "creating product barcode", "product_id", barcode.ProductID, "code", barcode.Code)
+	if err := r.db.WithContext(ctx).Create(barcode).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create product barcode", "error", err)
+		return fmt.Errorf("failed to create product barcode: %w", err)
+	}
+	return nil
+}
+
+// GetByCode retrieves a barcode of the tenant by its scanned code
+func (r *productBarcodeRepository) GetByCode(ctx context.Context, code string) (*entities.ProductBarcode, error) {
+	r.logger.InfoContext(ctx, "getting product barcode by code", "code", code)
+
+	var barcode entities.ProductBarcode
+	if err := r.db.WithContext(ctx).Where("code = ? AND tenant_id = ?", code, ctx.Value("tenant_id")).First(&barcode).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("product barcode not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get product barcode", "error", err, "code", code)
+		return nil, fmt.Errorf("failed to get product barcode: %w", err)
+	}
+
+	return &barcode, nil
+}
+
+// Delete deletes a barcode of a product
+func (r *productBarcodeRepository) Delete(ctx context.Context, productID, id uint) error {
+	r.logger.InfoContext(ctx, "deleting product barcode", "product_id", productID, "id", id)
+
+	result := r.db.WithContext(ctx).Where("id = ? AND product_id = ? AND tenant_id = ?", id, productID, ctx.Value("tenant_id")).Delete(&entities.ProductBarcode{})
+	if result.Error != nil {
+		r.logger.ErrorContext(ctx, "failed to delete product barcode", "error", result.Error, "id", id)
+		return fmt.Errorf("failed to delete product barcode: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product barcode not found: %w", gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -62,7 +63,13 @@ func (r *productRepository) GetByID(ctx context.Context, id uint) (*entities.Pro
 	r.logger.InfoContext(ctx, "getting product by ID", "id", id)
 
 	var product entities.Product
-	query := r.db.WithContext(ctx).Where("id = ?", id)
+	query := r.db.WithContext(ctx).Preload("Category").Preload("Tags").Preload("Barcodes").
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Options.Values", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Components.Component").
+		Preload("ModifierGroups.Options", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Where("id = ?", id)
 
 	// Add tenant_id filter if it exists in context
 	if tenantID, ok := ctx.Value("tenant_id").(uint); ok {
@@ -83,9 +90,20 @@ func (r *productRepository) GetByID(ctx context.Context, id uint) (*entities.Pro
 	return &product, nil
 }
 
-// List retrieves all products with pagination
-func (r *productRepository) List(ctx context.Context, page, limit int) ([]entities.Product, int64, error) {
-	r.logger.InfoContext(ctx, "listing products", "page", page, "limit", limit)
+// productSortColumns maps accepted sort fields to columns
+var productSortColumns = map[string]string{
+	"name":        "products.name",
+	"sku":         "products.sku",
+	"harga_jual":  "products.harga_jual",
+	"harga_modal": "products.harga_modal",
+	"stock":       "products.stock",
+	"created_at":  "products.created_at",
+}
+
+// List retrieves top-level products matching the query with pagination.
+// Variants are not listed on their own; they come preloaded under their parent.
+func (r *productRepository) List(ctx context.Context, q interfaces.ProductListQuery) ([]entities.Product, int64, error) {
+	r.logger.InfoContext(ctx, "listing products", "page", q.Page, "limit", q.Limit, "search", q.Search, "sort_by", q.SortBy)
 
 	var products []entities.Product
 	var total int64
@@ -94,10 +112,31 @@ func (r *productRepository) List(ctx context.Context, page, limit int) ([]entiti
 
 	// Add tenant_id filter if it exists in context
 	if tenantID, ok := ctx.Value("tenant_id").(uint); ok {
-		query = query.Where("tenant_id = ?", tenantID)
+		query = query.Where("products.tenant_id = ?", tenantID)
 	} else {
 		// If no tenant_id in context, only show products with NULL tenant_id
-		query = query.Where("tenant_id IS NULL")
+		query = query.Where("products.tenant_id IS NULL")
+	}
+	query = query.Where("products.parent_id IS NULL")
+
+	if q.Search != "" {
+		query = query.Where("(products.name LIKE ? OR products.sku LIKE ? OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.sku LIKE ?))",
+			"%"+q.Search+"%", q.Search+"%", q.Search+"%")
+	}
+	if len(q.CategoryIDs) > 0 {
+		query = query.Where("products.category_id IN ?", q.CategoryIDs)
+	}
+	if q.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM product_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.product_id = products.id AND t.name = ?)", q.Tag)
+	}
+	if q.MinPrice != nil {
+		query = query.Where("products.harga_jual >= ?", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		query = query.Where("products.harga_jual <= ?", *q.MaxPrice)
+	}
+	if q.InStockOnly {
+		query = query.Where("(products.stock > 0 OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.stock > 0))")
 	}
 
 	// Get total count
@@ -106,9 +145,23 @@ func (r *productRepository) List(ctx context.Context, page, limit int) ([]entiti
 		return nil, 0, fmt.Errorf("failed to count products: %w", err)
 	}
 
-	// Get paginated results
-	offset := (page - 1) * limit
-	if err := query.Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	column, ok := productSortColumns[q.SortBy]
+	if !ok {
+		column = productSortColumns["name"]
+	}
+	direction := "ASC"
+	if q.SortDir == "desc" {
+		direction = "DESC"
+	}
+
+	// Get paginated results; id breaks ties so pages are stable
+	offset := (q.Page - 1) * q.Limit
+	if err := query.Preload("Tags").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Order(column + " " + direction).
+		Order("products.id " + direction).
+		Offset(offset).Limit(q.Limit).
+		Find(&products).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to list products", "error", err)
 		return nil, 0, fmt.Errorf("failed to list products: %w", err)
 	}
@@ -116,11 +169,12 @@ func (r *productRepository) List(ctx context.Context, page, limit int) ([]entiti
 	return products, total, nil
 }
 
-// Update updates a product
+// Update updates a product. Stock is left untouched; it only changes through the stock ledger.
+// Tags are replaced separately with ReplaceTags.
 func (r *productRepository) Update(ctx context.Context, product *entities.Product) error {
 	r.logger.InfoContext(ctx, "updating product", "id", product.ID)
 
-	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", product.ID, ctx.Value("tenant_id")).Save(product).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", product.ID, ctx.Value("tenant_id")).Omit("stock", clause.Associations).Save(product).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to update product", "error", err, "id", product.ID)
 		return fmt.Errorf("failed to update product: %w", err)
 	}
@@ -128,14 +182,68 @@ func (r *productRepository) Update(ctx context.Context, product *entities.Produc
 	return nil
 }
 
-// UpdateStock updates product stock
-func (r *productRepository) UpdateStock(ctx context.Context, id uint, stock int) error {
-	r.logger.InfoContext(ctx, "updating product stock", "id", id, "stock", stock)
+// ReplaceTags sets the product's tags to exactly the given tags
+func (r *productRepository) ReplaceTags(ctx context.Context, product *entities.Product, tags []entities.Tag) error {
+	r.logger.InfoContext(ctx, "replacing product tags", "id", product.ID, "tags", len(tags))
+
+	if err := r.db.WithContext(ctx).Model(product).Association("Tags").Replace(tags); err != nil {
+		r.logger.ErrorContext(ctx, "failed to replace product tags", "error", err, "id", product.ID)
+		return fmt.Errorf("failed to replace product tags: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceModifierGroups sets the modifier groups offered on a product
+func (r *productRepository) ReplaceModifierGroups(ctx context.Context, product *entities.Product, groups []entities.ModifierGroup) error {
+	r.logger.InfoContext(ctx, "replacing product modifier groups", "id", product.ID, "groups", len(groups))
 
-	if err := r.db.WithContext(ctx).Model(&entities.Product{}).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).Update("stock", stock).Error; err != nil {
-		r.logger.ErrorContext(ctx, "failed to update product stock", "error", err, "id", id)
-		return fmt.Errorf("failed to update product stock: %w", err)
+	if err := r.db.WithContext(ctx).Model(product).Association("ModifierGroups").Replace(groups); err != nil {
+		r.logger.ErrorContext(ctx, "failed to replace product modifier groups", "error", err, "id", product.ID)
+		return fmt.Errorf("failed to replace product modifier groups: %w", err)
 	}
 
 	return nil
 }
+
+// ListLowStock retrieves products at or below their reorder point, lowest stock first
+func (r *productRepository) ListLowStock(ctx context.Context, page, limit int) ([]entities.Product, int64, error) {
+	r.logger.InfoContext(ctx, "listing low stock products", "page", page, "limit", limit)
+
+	var products []entities.Product
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Product{}).
+		Where("tenant_id = ?", ctx.Value("tenant_id")).
+		Where("reorder_point > 0 AND stock <= reorder_point").
+		Where("NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id)") // parents hold no stock of their own
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count low stock products", "error", err)
+		return nil, 0, fmt.Errorf("failed to count low stock products: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("stock - reorder_point, name").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list low stock products", "error", err)
+		return nil, 0, fmt.Errorf("failed to list low stock products: %w", err)
+	}
+
+	return products, total, nil
+}
+
+// GetLowStockByIDs retrieves the given products that are at or below their reorder point
+func (r *productRepository) GetLowStockByIDs(ctx context.Context, ids []uint) ([]entities.Product, error) {
+	r.logger.InfoContext(ctx, "getting low stock products by IDs", "count", len(ids))
+
+	var products []entities.Product
+	if err := r.db.WithContext(ctx).
+		Where("id IN ? AND tenant_id = ?", ids, ctx.Value("tenant_id")).
+		Where("reorder_point > 0 AND stock <= reorder_point").
+		Find(&products).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get low stock products", "error", err)
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+
+	return products, nil
+}
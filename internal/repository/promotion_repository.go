@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPromotionRepository creates a new promotion repository
+func NewPromotionRepository(db *gorm.DB, logger *slog.Logger) interfaces.PromotionRepository {
+	return &promotionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new promotion with its products
+func (r *promotionRepository) Create(ctx context.Context, promotion *entities.Promotion) error {
+	r.logger.InfoContext(ctx, "creating promotion", "name", promotion.Name, "type", promotion.Type)
+	if err := r.db.WithContext(ctx).Omit("Products.*").Create(promotion).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create promotion", "error", err)
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a promotion with its products by ID
+func (r *promotionRepository) GetByID(ctx context.Context, id uint) (*entities.Promotion, error) {
+	r.logger.InfoContext(ctx, "getting promotion by ID", "id", id)
+
+	var promotion entities.Promotion
+	if err := r.db.WithContext(ctx).Preload("Products").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&promotion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promotion not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get promotion", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	return &promotion, nil
+}
+
+// GetByCode retrieves the promotion using a voucher code
+func (r *promotionRepository) GetByCode(ctx context.Context, code string) (*entities.Promotion, error) {
+	var promotion entities.Promotion
+	if err := r.db.WithContext(ctx).Where("code = ? AND tenant_id = ?", code, ctx.Value("tenant_id")).First(&promotion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promotion not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get promotion by code", "error", err)
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	return &promotion, nil
+}
+
+// List retrieves promotions with pagination, newest first
+func (r *promotionRepository) List(ctx context.Context, page, limit int) ([]entities.Promotion, int64, error) {
+	r.logger.InfoContext(ctx, "listing promotions", "page", page, "limit", limit)
+
+	var promotions []entities.Promotion
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Promotion{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count promotions", "error", err)
+		return nil, 0, fmt.Errorf("failed to count promotions: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Products").Order("id DESC").Offset(offset).Limit(limit).Find(&promotions).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list promotions", "error", err)
+		return nil, 0, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	return promotions, total, nil
+}
+
+// Update saves a promotion and replaces its products
+func (r *promotionRepository) Update(ctx context.Context, promotion *entities.Promotion) error {
+	r.logger.InfoContext(ctx, "updating promotion", "id", promotion.ID)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND tenant_id = ?", promotion.ID, ctx.Value("tenant_id")).Omit("Products", "Tenant").Save(promotion).Error; err != nil {
+			return err
+		}
+		return tx.Model(promotion).Omit("Products.*").Association("Products").Replace(promotion.Products)
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update promotion", "error", err, "id", promotion.ID)
+		return fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a promotion and its product assignments. Sales keep their promotion records.
+func (r *promotionRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting promotion", "id", id)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var promotion entities.Promotion
+		if err := tx.Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&promotion).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&promotion).Association("Products").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&promotion).Error
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete promotion", "error", err, "id", id)
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+
+	return nil
+}
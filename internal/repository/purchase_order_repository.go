@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type purchaseOrderRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPurchaseOrderRepository creates a new purchase order repository
+func NewPurchaseOrderRepository(db *gorm.DB, logger *slog.Logger) interfaces.PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new purchase order with its items
+func (r *purchaseOrderRepository) Create(ctx context.Context, order *entities.PurchaseOrder) error {
+	r.logger.InfoContext(ctx, "creating purchase order", "supplier_id", order.SupplierID)
+	if err := r.db.WithContext(ctx).Create(order).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create purchase order", "error", err)
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a purchase order with its items and receipts
+func (r *purchaseOrderRepository) GetByID(ctx context.Context, id uint) (*entities.PurchaseOrder, error) {
+	r.logger.InfoContext(ctx, "getting purchase order by ID", "id", id)
+
+	var order entities.PurchaseOrder
+	if err := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("Items.Product").
+		Preload("Receipts.Items").
+		Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).
+		First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("purchase order not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get purchase order", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// List retrieves purchase orders with pagination, newest first
+func (r *purchaseOrderRepository) List(ctx context.Context, page, limit int) ([]entities.PurchaseOrder, int64, error) {
+	r.logger.InfoContext(ctx, "listing purchase orders", "page", page, "limit", limit)
+
+	var orders []entities.PurchaseOrder
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.PurchaseOrder{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count purchase orders", "error", err)
+		return nil, 0, fmt.Errorf("failed to count purchase orders: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Supplier").Preload("Items.Product").Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list purchase orders", "error", err)
+		return nil, 0, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// GetOutstandingBySupplier sums ordered but not yet received stock per supplier
+func (r *purchaseOrderRepository) GetOutstandingBySupplier(ctx context.Context) ([]interfaces.OutstandingPurchaseOrders, error) {
+	r.logger.InfoContext(ctx, "getting outstanding purchase orders by supplier")
+
+	var results []interfaces.OutstandingPurchaseOrders
+	query := `
+		SELECT
+			s.id as supplier_id,
+			s.name as supplier_name,
+			COUNT(DISTINCT po.id) as open_orders,
+			SUM(poi.quantity - poi.received_qty) as outstanding_qty,
+			SUM((poi.quantity - poi.received_qty) * poi.unit_cost) as outstanding_value
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		JOIN purchase_order_items poi ON poi.purchase_order_id = po.id
+		WHERE po.tenant_id = ? AND po.status IN (?, ?) AND poi.received_qty < poi.quantity
+		GROUP BY s.id, s.name
+		ORDER BY outstanding_value DESC
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query,
+		ctx.Value("tenant_id"),
+		entities.PurchaseOrderStatusOpen,
+		entities.PurchaseOrderStatusPartiallyReceived,
+	).Scan(&results).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get outstanding purchase orders", "error", err)
+		return nil, fmt.Errorf("failed to get outstanding purchase orders: %w", err)
+	}
+
+	return results, nil
+}
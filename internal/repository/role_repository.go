@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB, logger *slog.Logger) interfaces.RoleRepository {
+	return &roleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new custom role with its permissions
+func (r *roleRepository) Create(ctx context.Context, role *entities.Role) error {
+	r.logger.InfoContext(ctx, "creating role", "name", role.Name)
+	if err := r.db.WithContext(ctx).Create(role).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create role", "error", err)
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a custom role with its permissions
+func (r *roleRepository) GetByID(ctx context.Context, id uint) (*entities.Role, error) {
+	r.logger.InfoContext(ctx, "getting role by ID", "id", id)
+
+	var role entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&role).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("role not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get role", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return &role, nil
+}
+
+// GetByName retrieves a custom role with its permissions by name
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*entities.Role, error) {
+	r.logger.InfoContext(ctx, "getting role by name", "name", name)
+
+	var role entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ? AND tenant_id = ?", name, ctx.Value("tenant_id")).First(&role).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("role not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get role", "error", err, "name", name)
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return &role, nil
+}
+
+// List retrieves the tenant's custom roles ordered by name
+func (r *roleRepository) List(ctx context.Context) ([]entities.Role, error) {
+	r.logger.InfoContext(ctx, "listing roles")
+
+	var roles []entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("tenant_id = ?", ctx.Value("tenant_id")).Order("name").Find(&roles).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list roles", "error", err)
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Update updates a custom role and replaces its permissions. When the role is renamed,
+// users holding the old name are moved to the new one.
+func (r *roleRepository) Update(ctx context.Context, role *entities.Role) error {
+	r.logger.InfoContext(ctx, "updating role", "id", role.ID)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entities.Role
+		if err := tx.Where("id = ? AND tenant_id = ?", role.ID, ctx.Value("tenant_id")).First(&current).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Permissions", "Tenant").Save(role).Error; err != nil {
+			return err
+		}
+
+		if current.Name != role.Name {
+			if err := tx.Model(&entities.User{}).Where("role = ? AND tenant_id = ?", current.Name, ctx.Value("tenant_id")).Update("role", role.Name).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("role_id = ?", role.ID).Delete(&entities.RolePermission{}).Error; err != nil {
+			return err
+		}
+		for i := range role.Permissions {
+			role.Permissions[i].ID = 0
+			role.Permissions[i].RoleID = role.ID
+		}
+		if len(role.Permissions) > 0 {
+			return tx.Create(&role.Permissions).Error
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update role", "error", err, "id", role.ID)
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a custom role and its permissions
+func (r *roleRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting role", "id", id)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role entities.Role
+		if err := tx.Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&role).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&entities.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&role).Error
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete role", "error", err, "id", id)
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+// CountUsers counts the tenant's users holding a role
+func (r *roleRepository) CountUsers(ctx context.Context, name string) (int64, error) {
+	r.logger.InfoContext(ctx, "counting role users", "name", name)
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.User{}).Where("role = ? AND tenant_id = ?", name, ctx.Value("tenant_id")).Count(&count).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count role users", "error", err, "name", name)
+		return 0, fmt.Errorf("failed to count role users: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB, logger *slog.Logger) interfaces.SessionRepository {
+	return &sessionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new login session
+func (r *sessionRepository) Create(ctx context.Context, session *entities.Session) error {
+	r.logger.InfoContext(ctx, "creating session", "user_id", session.UserID)
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create session", "error", err, "user_id", session.UserID)
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetByRefreshTokenHash retrieves the session whose current refresh token has the hash.
+// Refresh tokens are presented without an access token, so the lookup is not tenant scoped.
+func (r *sessionRepository) GetByRefreshTokenHash(ctx context.Context, hash string) (*entities.Session, error) {
+	return r.getBy(ctx, "refresh_token_hash", hash)
+}
+
+// GetByPreviousTokenHash retrieves the session whose refresh token replaced the one with the hash
+func (r *sessionRepository) GetByPreviousTokenHash(ctx context.Context, hash string) (*entities.Session, error) {
+	return r.getBy(ctx, "previous_token_hash", hash)
+}
+
+func (r *sessionRepository) getBy(ctx context.Context, column, hash string) (*entities.Session, error) {
+	var session entities.Session
+	if err := r.db.WithContext(ctx).Where(column+" = ?", hash).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get session", "error", err)
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+// Rotate saves a refreshed session and denylists the access token it replaces.
+// The update only applies while the session is unrevoked and still holds the refresh
+// token in session.PreviousTokenHash; otherwise a concurrent refresh already spent it
+// and ErrInvalidRefreshToken is returned.
+func (r *sessionRepository) Rotate(ctx context.Context, session *entities.Session, replaced entities.RevokedToken) error {
+	r.logger.InfoContext(ctx, "rotating session", "id", session.ID)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Session{}).
+			Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, session.PreviousTokenHash).
+			Updates(map[string]interface{}{
+				"refresh_token_hash":  session.RefreshTokenHash,
+				"previous_token_hash": session.PreviousTokenHash,
+				"access_token_id":     session.AccessTokenID,
+				"access_expires_at":   session.AccessExpiresAt,
+				"user_agent":          session.UserAgent,
+				"ip_address":          session.IPAddress,
+				"last_used_at":        session.LastUsedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrInvalidRefreshToken
+		}
+		return denylist(tx, []entities.RevokedToken{replaced})
+	})
+	if errors.Is(err, entities.ErrInvalidRefreshToken) {
+		r.logger.WarnContext(ctx, "session already rotated or revoked", "id", session.ID)
+		return fmt.Errorf("session %d: %w", session.ID, err)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to rotate session", "error", err, "id", session.ID)
+		return fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke revokes a session and denylists its latest access token
+func (r *sessionRepository) Revoke(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "revoking session", "id", id)
+
+	if _, err := r.revoke(ctx, "id", id); err != nil {
+		r.logger.ErrorContext(ctx, "failed to revoke session", "error", err, "id", id)
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every active session of a user and returns how many were revoked
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID uint) (int64, error) {
+	r.logger.InfoContext(ctx, "revoking user sessions", "user_id", userID)
+
+	revoked, err := r.revoke(ctx, "user_id", userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to revoke user sessions", "error", err, "user_id", userID)
+		return 0, fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// RevokeAllForTerminal revokes every active session started on a terminal and returns how many were revoked
+func (r *sessionRepository) RevokeAllForTerminal(ctx context.Context, terminalID uint) (int64, error) {
+	r.logger.InfoContext(ctx, "revoking terminal sessions", "terminal_id", terminalID)
+
+	revoked, err := r.revoke(ctx, "terminal_id", terminalID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to revoke terminal sessions", "error", err, "terminal_id", terminalID)
+		return 0, fmt.Errorf("failed to revoke terminal sessions: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// revoke marks the unrevoked sessions whose column equals value as revoked and
+// denylists their access tokens that have not expired yet
+func (r *sessionRepository) revoke(ctx context.Context, column string, value uint) (int64, error) {
+	now := time.Now()
+	var revoked int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sessions []entities.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(column+" = ? AND revoked_at IS NULL", value).Find(&sessions).Error; err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(sessions))
+		var tokens []entities.RevokedToken
+		for i, s := range sessions {
+			ids[i] = s.ID
+			if s.AccessExpiresAt.After(now) {
+				tokens = append(tokens, entities.RevokedToken{JTI: s.AccessTokenID, ExpiresAt: s.AccessExpiresAt})
+			}
+		}
+
+		result := tx.Model(&entities.Session{}).Where("id IN ?", ids).Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+
+		return denylist(tx, tokens)
+	})
+
+	return revoked, err
+}
+
+// denylist records revoked access tokens and clears entries whose tokens have expired
+func denylist(tx *gorm.DB, tokens []entities.RevokedToken) error {
+	if err := tx.Where("expires_at < ?", time.Now()).Delete(&entities.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tokens).Error
+}
+
+// IsTokenRevoked reports whether an access token ID is on the denylist
+func (r *sessionRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to check revoked token", "error", err)
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return count > 0, nil
+}
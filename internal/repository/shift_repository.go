@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type shiftRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewShiftRepository creates a new shift repository
+func NewShiftRepository(db *gorm.DB, logger *slog.Logger) interfaces.ShiftRepository {
+	return &shiftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByID retrieves a shift with its cash events and closing counts
+func (r *shiftRepository) GetByID(ctx context.Context, id uint) (*entities.Shift, error) {
+	r.logger.InfoContext(ctx, "getting shift by ID", "id", id)
+
+	var shift entities.Shift
+	if err := r.db.WithContext(ctx).Preload("User").Preload("CashEvents").Preload("Counts").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&shift).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("shift not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get shift", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+
+	return &shift, nil
+}
+
+// GetOpenByUser retrieves the open shift of a user
+func (r *shiftRepository) GetOpenByUser(ctx context.Context, userID uint) (*entities.Shift, error) {
+	r.logger.InfoContext(ctx, "getting open shift", "user_id", userID)
+
+	var shift entities.Shift
+	if err := r.db.WithContext(ctx).Preload("User").Preload("CashEvents").Preload("Counts").
+		Where("user_id = ? AND status = ? AND tenant_id = ?", userID, entities.ShiftStatusOpen, ctx.Value("tenant_id")).
+		First(&shift).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("open shift not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get open shift", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to get open shift: %w", err)
+	}
+
+	return &shift, nil
+}
+
+// List retrieves shifts with pagination, newest first
+func (r *shiftRepository) List(ctx context.Context, page, limit int) ([]entities.Shift, int64, error) {
+	r.logger.InfoContext(ctx, "listing shifts", "page", page, "limit", limit)
+
+	var shifts []entities.Shift
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Shift{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count shifts", "error", err)
+		return nil, 0, fmt.Errorf("failed to count shifts: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&shifts).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list shifts", "error", err)
+		return nil, 0, fmt.Errorf("failed to list shifts: %w", err)
+	}
+
+	return shifts, total, nil
+}
+
+// GetTenderSales retrieves the amount each tender collected for sales paid in a shift, after change.
+// Sales that were later voided or refunded still count here; the reversal counts in the shift that paid it back.
+func (r *shiftRepository) GetTenderSales(ctx context.Context, shiftID uint) ([]interfaces.ShiftTenderTotal, error) {
+	r.logger.InfoContext(ctx, "getting shift tender sales", "shift_id", shiftID)
+
+	var totals []interfaces.ShiftTenderTotal
+
+	query := `
+		SELECT
+			tp.method,
+			COUNT(DISTINCT tp.transaction_id) as transactions,
+			SUM(tp.amount - tp.change_amount) as amount
+		FROM transaction_payments tp
+		JOIN transactions t ON tp.transaction_id = t.id
+		WHERE t.shift_id = ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded', 'voided')
+		GROUP BY tp.method
+		ORDER BY tp.method
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query, shiftID, ctx.Value("tenant_id")).Scan(&totals).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get shift tender sales", "error", err)
+		return nil, fmt.Errorf("failed to get shift tender sales: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetTenderRefunds retrieves the amount each tender paid back for voids and refunds made in a shift.
+// A refund is paid back through the tenders of the original sale in proportion to what each collected.
+func (r *shiftRepository) GetTenderRefunds(ctx context.Context, shiftID uint) ([]interfaces.ShiftTenderTotal, error) {
+	r.logger.InfoContext(ctx, "getting shift tender refunds", "shift_id", shiftID)
+
+	var totals []interfaces.ShiftTenderTotal
+
+	query := `
+		SELECT
+			tp.method,
+			COUNT(DISTINCT r.id) as transactions,
+			SUM(r.amount * (tp.amount - tp.change_amount) / t.total_price) as amount
+		FROM transaction_refunds r
+		JOIN transactions t ON r.transaction_id = t.id
+		JOIN transaction_payments tp ON tp.transaction_id = t.id
+		WHERE r.shift_id = ? AND r.tenant_id = ? AND t.total_price > 0
+		GROUP BY tp.method
+		ORDER BY tp.method
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query, shiftID, ctx.Value("tenant_id")).Scan(&totals).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get shift tender refunds", "error", err)
+		return nil, fmt.Errorf("failed to get shift tender refunds: %w", err)
+	}
+
+	return totals, nil
+}
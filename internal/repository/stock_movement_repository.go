@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type stockMovementRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewStockMovementRepository creates a new stock movement repository
+func NewStockMovementRepository(db *gorm.DB, logger *slog.Logger) interfaces.StockMovementRepository {
+	return &stockMovementRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListByProduct retrieves a product's stock movements, newest first, with pagination
+func (r *stockMovementRepository) ListByProduct(ctx context.Context, productID uint, page, limit int) ([]entities.StockMovement, int64, error) {
+	r.logger.InfoContext(ctx, "listing stock movements", "product_id", productID, "page", page, "limit", limit)
+
+	var movements []entities.StockMovement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.StockMovement{}).Where("product_id = ? AND tenant_id = ?", productID, ctx.Value("tenant_id"))
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count stock movements", "error", err)
+		return nil, 0, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&movements).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list stock movements", "error", err)
+		return nil, 0, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+
+	return movements, total, nil
+}
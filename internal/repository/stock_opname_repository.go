@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type stockOpnameRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewStockOpnameRepository creates a new stock opname repository
+func NewStockOpnameRepository(db *gorm.DB, logger *slog.Logger) interfaces.StockOpnameRepository {
+	return &stockOpnameRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new stock opname session
+func (r *stockOpnameRepository) Create(ctx context.Context, opname *entities.StockOpname) error {
+	r.logger.InfoContext(ctx, "creating stock opname")
+	if err := r.db.WithContext(ctx).Create(opname).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create stock opname", "error", err)
+		return fmt.Errorf("failed to create stock opname: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a stock opname session with its counted items
+func (r *stockOpnameRepository) GetByID(ctx context.Context, id uint) (*entities.StockOpname, error) {
+	r.logger.InfoContext(ctx, "getting stock opname by ID", "id", id)
+
+	var opname entities.StockOpname
+	if err := r.db.WithContext(ctx).Preload("Items.Product").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&opname).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("stock opname not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get stock opname", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get stock opname: %w", err)
+	}
+
+	return &opname, nil
+}
+
+// List retrieves stock opname sessions with pagination, newest first
+func (r *stockOpnameRepository) List(ctx context.Context, page, limit int) ([]entities.StockOpname, int64, error) {
+	r.logger.InfoContext(ctx, "listing stock opnames", "page", page, "limit", limit)
+
+	var opnames []entities.StockOpname
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.StockOpname{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count stock opnames", "error", err)
+		return nil, 0, fmt.Errorf("failed to count stock opnames: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&opnames).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list stock opnames", "error", err)
+		return nil, 0, fmt.Errorf("failed to list stock opnames: %w", err)
+	}
+
+	return opnames, total, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type supplierRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewSupplierRepository creates a new supplier repository
+func NewSupplierRepository(db *gorm.DB, logger *slog.Logger) interfaces.SupplierRepository {
+	return &supplierRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new supplier
+func (r *supplierRepository) Create(ctx context.Context, supplier *entities.Supplier) error {
+	r.logger.InfoContext(ctx, "creating supplier", "name", supplier.Name)
+	if err := r.db.WithContext(ctx).Create(supplier).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create supplier", "error", err)
+		return fmt.Errorf("failed to create supplier: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a supplier by ID
+func (r *supplierRepository) GetByID(ctx context.Context, id uint) (*entities.Supplier, error) {
+	r.logger.InfoContext(ctx, "getting supplier by ID", "id", id)
+
+	var supplier entities.Supplier
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&supplier).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("supplier not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get supplier", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+// List retrieves suppliers with pagination, ordered by name
+func (r *supplierRepository) List(ctx context.Context, page, limit int) ([]entities.Supplier, int64, error) {
+	r.logger.InfoContext(ctx, "listing suppliers", "page", page, "limit", limit)
+
+	var suppliers []entities.Supplier
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Supplier{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count suppliers", "error", err)
+		return nil, 0, fmt.Errorf("failed to count suppliers: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("name").Offset(offset).Limit(limit).Find(&suppliers).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list suppliers", "error", err)
+		return nil, 0, fmt.Errorf("failed to list suppliers: %w", err)
+	}
+
+	return suppliers, total, nil
+}
+
+// Update updates a supplier
+func (r *supplierRepository) Update(ctx context.Context, supplier *entities.Supplier) error {
+	r.logger.InfoContext(ctx, "updating supplier", "id", supplier.ID)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", supplier.ID, ctx.Value("tenant_id")).Save(supplier).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to update supplier", "error", err, "id", supplier.ID)
+		return fmt.Errorf("failed to update supplier: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a supplier
+func (r *supplierRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.InfoContext(ctx, "deleting supplier", "id", id)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).Delete(&entities.Supplier{}).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to delete supplier", "error", err, "id", id)
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB, logger *slog.Logger) interfaces.TagRepository {
+	return &tagRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// FindOrCreate returns the tenant's tags with the given names, creating any that do not exist yet
+func (r *tagRepository) FindOrCreate(ctx context.Context, names []string) ([]entities.Tag, error) {
+	r.logger.InfoContext(ctx, "finding or creating tags", "count", len(names))
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	tags := make([]entities.Tag, 0, len(names))
+	for _, name := range names {
+		tag := entities.Tag{Name: name, TenantID: &tenantID}
+		if err := r.db.WithContext(ctx).Where("name = ? AND tenant_id = ?", name, tenantID).FirstOrCreate(&tag).Error; err != nil {
+			r.logger.ErrorContext(ctx, "failed to find or create tag", "error", err, "name", name)
+			return nil, fmt.Errorf("failed to find or create tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// List retrieves all tags of the tenant, ordered by name
+func (r *tagRepository) List(ctx context.Context) ([]entities.Tag, error) {
+	r.logger.InfoContext(ctx, "listing tags")
+
+	var tags []entities.Tag
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", ctx.Value("tenant_id")).Order("name").Find(&tags).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list tags", "error", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
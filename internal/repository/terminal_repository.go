@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type terminalRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewTerminalRepository creates a new terminal repository
+func NewTerminalRepository(db *gorm.DB, logger *slog.Logger) interfaces.TerminalRepository {
+	return &terminalRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create registers a new terminal
+func (r *terminalRepository) Create(ctx context.Context, terminal *entities.Terminal) error {
+	r.logger.InfoContext(ctx, "creating terminal", "name", terminal.Name)
+	if err := r.db.WithContext(ctx).Create(terminal).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create terminal", "error", err)
+		return fmt.Errorf("failed to create terminal: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a terminal of the tenant by ID
+func (r *terminalRepository) GetByID(ctx context.Context, id uint) (*entities.Terminal, error) {
+	r.logger.InfoContext(ctx, "getting terminal by ID", "id", id)
+
+	var terminal entities.Terminal
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&terminal).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("terminal not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get terminal", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get terminal: %w", err)
+	}
+
+	return &terminal, nil
+}
+
+// GetByTokenHash retrieves a terminal by the hash of its device token.
+// Terminals identify their tenant by the token, so the lookup is not tenant scoped.
+func (r *terminalRepository) GetByTokenHash(ctx context.Context, hash string) (*entities.Terminal, error) {
+	var terminal entities.Terminal
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&terminal).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("terminal not found: %w", err)
+		}
+		r.logger.ErrorContext(ctx, "failed to get terminal", "error", err)
+		return nil, fmt.Errorf("failed to get terminal: %w", err)
+	}
+
+	return &terminal, nil
+}
+
+// List retrieves the tenant's terminals ordered by name
+func (r *terminalRepository) List(ctx context.Context) ([]entities.Terminal, error) {
+	r.logger.InfoContext(ctx, "listing terminals")
+
+	var terminals []entities.Terminal
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", ctx.Value("tenant_id")).Order("name").Find(&terminals).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list terminals", "error", err)
+		return nil, fmt.Errorf("failed to list terminals: %w", err)
+	}
+
+	return terminals, nil
+}
+
+// Update updates a terminal
+func (r *terminalRepository) Update(ctx context.Context, terminal *entities.Terminal) error {
+	r.logger.InfoContext(ctx, "updating terminal", "id", terminal.ID)
+	if err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", terminal.ID, ctx.Value("tenant_id")).Omit("Tenant").Save(terminal).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to update terminal", "error", err, "id", terminal.ID)
+		return fmt.Errorf("failed to update terminal: %w", err)
+	}
+	return nil
+}
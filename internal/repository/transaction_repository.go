@@ -41,7 +41,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uint) (*entities
 	r.logger.InfoContext(ctx, "getting transaction by ID", "id", id)
 
 	var transaction entities.Transaction
-	if err := r.db.WithContext(ctx).Preload("Items.Product").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&transaction).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Items.Product").Preload("Items.Modifiers").Preload("Items.Promotions").Preload("Payments").Preload("Refunds.Items").Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).First(&transaction).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("transaction not found: %w", err)
 		}
@@ -52,7 +52,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uint) (*entities
 	return &transaction, nil
 }
 
-// List retrieves transactions with pagination
+// List retrieves sales with pagination; open, held and cancelled orders are left out
 func (r *transactionRepository) List(ctx context.Context, page, limit int) ([]entities.Transaction, int64, error) {
 	r.logger.InfoContext(ctx, "listing transactions", "page", page, "limit", limit)
 
@@ -60,14 +60,14 @@ func (r *transactionRepository) List(ctx context.Context, page, limit int) ([]en
 	var total int64
 
 	// Count total transactions
-	if err := r.db.WithContext(ctx).Model(&entities.Transaction{}).Where("tenant_id = ?", ctx.Value("tenant_id")).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&entities.Transaction{}).Where("tenant_id = ? AND status NOT IN ?", ctx.Value("tenant_id"), entities.OrderStatuses).Count(&total).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to count transactions", "error", err)
 		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
 	}
 
 	// Get transactions with pagination
 	offset := (page - 1) * limit
-	if err := r.db.WithContext(ctx).Preload("Items.Product").Where("tenant_id = ?", ctx.Value("tenant_id")).Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Items.Product").Preload("Items.Modifiers").Preload("Items.Promotions").Preload("Payments").Preload("Refunds.Items").Where("tenant_id = ? AND status NOT IN ?", ctx.Value("tenant_id"), entities.OrderStatuses).Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to list transactions", "error", err)
 		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -75,6 +75,51 @@ func (r *transactionRepository) List(ctx context.Context, page, limit int) ([]en
 	return transactions, total, nil
 }
 
+// ListOrders retrieves unsettled orders in the given statuses with pagination, oldest first
+func (r *transactionRepository) ListOrders(ctx context.Context, statuses []string, page, limit int) ([]entities.Transaction, int64, error) {
+	r.logger.InfoContext(ctx, "listing orders", "statuses", statuses, "page", page, "limit", limit)
+
+	var orders []entities.Transaction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Transaction{}).Where("tenant_id = ? AND status IN ?", ctx.Value("tenant_id"), statuses)
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count orders", "error", err)
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Items.Product").Preload("Items.Modifiers").Order("created_at").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list orders", "error", err)
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// ListByCustomer retrieves a customer's transactions with pagination, newest first
+func (r *transactionRepository) ListByCustomer(ctx context.Context, customerID uint, page, limit int) ([]entities.Transaction, int64, error) {
+	r.logger.InfoContext(ctx, "listing customer transactions", "customer_id", customerID, "page", page, "limit", limit)
+
+	var transactions []entities.Transaction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Transaction{}).Where("customer_id = ? AND tenant_id = ?", customerID, ctx.Value("tenant_id"))
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count customer transactions", "error", err)
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Items.Product").Preload("Items.Modifiers").Preload("Items.Promotions").Preload("Payments").Preload("Refunds.Items").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list customer transactions", "error", err)
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return transactions, total, nil
+}
+
 // GetReportData retrieves report data for the given date range
 func (r *transactionRepository) GetReportData(ctx context.Context, startDate, endDate time.Time) ([]interfaces.ReportDetail, error) {
 	r.logger.InfoContext(ctx, "getting report data", "start_date", startDate, "end_date", endDate)
@@ -85,13 +130,22 @@ func (r *transactionRepository) GetReportData(ctx context.Context, startDate, en
 		SELECT 
 			ti.product_id,
 			p.name as product_name,
-			SUM(ti.quantity) as total,
-			SUM(ti.price * ti.quantity) as total_price
+			p.parent_id as parent_product_id,
+			pp.name as parent_product_name,
+			SUM(ti.quantity - COALESCE(ri.quantity, 0)) as total,
+			SUM((ti.price * ti.quantity - ti.discount) * (ti.quantity - COALESCE(ri.quantity, 0)) / ti.quantity) as total_price,
+			SUM(ti.cost * (ti.quantity - COALESCE(ri.quantity, 0))) as total_cost
 		FROM transaction_items ti
 		JOIN transactions t ON ti.transaction_id = t.id
 		JOIN products p ON ti.product_id = p.id
-		WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ?
-		GROUP BY ti.product_id, p.name
+		LEFT JOIN products pp ON p.parent_id = pp.id
+		LEFT JOIN (
+			SELECT transaction_item_id, SUM(quantity) as quantity
+			FROM transaction_refund_items
+			GROUP BY transaction_item_id
+		) ri ON ri.transaction_item_id = ti.id
+		WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+		GROUP BY ti.product_id, p.name, p.parent_id, pp.name
 		ORDER BY total_price DESC
 	`
 
@@ -103,6 +157,144 @@ func (r *transactionRepository) GetReportData(ctx context.Context, startDate, en
 	return reportDetails, nil
 }
 
+// GetPaymentBreakdown retrieves collected amounts per tender type for the given date range, net of refunds.
+// A refund is paid back through the tenders of the original sale in proportion to what each collected.
+func (r *transactionRepository) GetPaymentBreakdown(ctx context.Context, startDate, endDate time.Time) ([]interfaces.PaymentBreakdown, error) {
+	r.logger.InfoContext(ctx, "getting payment breakdown", "start_date", startDate, "end_date", endDate)
+
+	var breakdown []interfaces.PaymentBreakdown
+
+	query := `
+		SELECT
+			tp.method,
+			COUNT(DISTINCT tp.transaction_id) as transactions,
+			SUM(tp.amount - tp.change_amount - CASE
+				WHEN t.total_price > 0 THEN COALESCE(r.amount, 0) * (tp.amount - tp.change_amount) / t.total_price
+				ELSE 0
+			END) as amount
+		FROM transaction_payments tp
+		JOIN transactions t ON tp.transaction_id = t.id
+		LEFT JOIN (
+			SELECT transaction_id, SUM(amount) as amount
+			FROM transaction_refunds
+			GROUP BY transaction_id
+		) r ON r.transaction_id = t.id
+		WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+		GROUP BY tp.method
+		ORDER BY amount DESC
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query, startDate, endDate, ctx.Value("tenant_id")).Scan(&breakdown).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get payment breakdown", "error", err)
+		return nil, fmt.Errorf("failed to get payment breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// GetModifierBreakdown retrieves how often each modifier was sold in the given date range, net of refunds
+func (r *transactionRepository) GetModifierBreakdown(ctx context.Context, startDate, endDate time.Time) ([]interfaces.ModifierBreakdown, error) {
+	r.logger.InfoContext(ctx, "getting modifier breakdown", "start_date", startDate, "end_date", endDate)
+
+	var breakdown []interfaces.ModifierBreakdown
+
+	query := `
+		SELECT
+			tim.group_name,
+			tim.name,
+			SUM(ti.quantity - COALESCE(ri.quantity, 0)) as total,
+			SUM(tim.price * (ti.quantity - COALESCE(ri.quantity, 0))) as revenue
+		FROM transaction_item_modifiers tim
+		JOIN transaction_items ti ON tim.transaction_item_id = ti.id
+		JOIN transactions t ON ti.transaction_id = t.id
+		LEFT JOIN (
+			SELECT transaction_item_id, SUM(quantity) as quantity
+			FROM transaction_refund_items
+			GROUP BY transaction_item_id
+		) ri ON ri.transaction_item_id = ti.id
+		WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+		GROUP BY tim.group_name, tim.name
+		ORDER BY total DESC
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query, startDate, endDate, ctx.Value("tenant_id")).Scan(&breakdown).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get modifier breakdown", "error", err)
+		return nil, fmt.Errorf("failed to get modifier breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// GetPromotionBreakdown retrieves the discount each promotion gave in the given date range, net of refunds
+func (r *transactionRepository) GetPromotionBreakdown(ctx context.Context, startDate, endDate time.Time) ([]interfaces.PromotionBreakdown, error) {
+	r.logger.InfoContext(ctx, "getting promotion breakdown", "start_date", startDate, "end_date", endDate)
+
+	var breakdown []interfaces.PromotionBreakdown
+
+	query := `
+		SELECT
+			tip.promotion_id,
+			tip.name,
+			COUNT(DISTINCT t.id) as transactions,
+			SUM(tip.amount * (ti.quantity - COALESCE(ri.quantity, 0)) / ti.quantity) as discount,
+			SUM((ti.price * ti.quantity - ti.discount) * (ti.quantity - COALESCE(ri.quantity, 0)) / ti.quantity) as revenue
+		FROM transaction_item_promotions tip
+		JOIN transaction_items ti ON tip.transaction_item_id = ti.id
+		JOIN transactions t ON ti.transaction_id = t.id
+		LEFT JOIN (
+			SELECT transaction_item_id, SUM(quantity) as quantity
+			FROM transaction_refund_items
+			GROUP BY transaction_item_id
+		) ri ON ri.transaction_item_id = ti.id
+		WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+		GROUP BY tip.promotion_id, tip.name
+		ORDER BY discount DESC
+	`
+
+	if err := r.db.WithContext(ctx).Raw(query, startDate, endDate, ctx.Value("tenant_id")).Scan(&breakdown).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get promotion breakdown", "error", err)
+		return nil, fmt.Errorf("failed to get promotion breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// GetTaxSummary retrieves service charge and PPN per tax rate for the given date range, net of refunds
+func (r *transactionRepository) GetTaxSummary(ctx context.Context, startDate, endDate time.Time) ([]interfaces.TaxSummary, error) {
+	r.logger.InfoContext(ctx, "getting tax summary", "start_date", startDate, "end_date", endDate)
+
+	var summary []interfaces.TaxSummary
+
+	query := `
+		SELECT
+			c.tax_rate,
+			SUM(c.tax_base) as tax_base,
+			SUM(c.tax) as tax,
+			SUM(c.service_charge) as service_charge
+		FROM (
+			SELECT t.tax_rate, t.tax_base, t.tax, t.service_charge
+			FROM transactions t
+			WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+			UNION ALL
+			SELECT t.tax_rate, -ri.tax_base, -ri.tax, -ri.service_charge
+			FROM transaction_refund_items ri
+			JOIN transaction_refunds r ON ri.refund_id = r.id
+			JOIN transactions t ON r.transaction_id = t.id
+			WHERE t.created_at BETWEEN ? AND ? AND t.tenant_id = ? AND t.status IN ('completed', 'partially_refunded', 'refunded')
+		) c
+		GROUP BY c.tax_rate
+		ORDER BY c.tax_rate
+	`
+
+	tenantID := ctx.Value("tenant_id")
+	if err := r.db.WithContext(ctx).Raw(query, startDate, endDate, tenantID, startDate, endDate, tenantID).Scan(&summary).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to get tax summary", "error", err)
+		return nil, fmt.Errorf("failed to get tax summary: %w", err)
+	}
+
+	return summary, nil
+}
+
 // Delete deletes a transaction
 func (r *transactionRepository) Delete(ctx context.Context, id uint) error {
 	r.logger.InfoContext(ctx, "deleting transaction", "id", id)
@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepository struct {
@@ -27,7 +31,6 @@ func NewUserRepository(db *gorm.DB, logger *slog.Logger) interfaces.UserReposito
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
 	r.logger.InfoContext(ctx, "getting user by username", "username", username)
 
-	var user entities.User
 	query := r.db.WithContext(ctx).Where("username = ?", username)
 
 	// Add tenant_id filter if it exists in context
@@ -35,23 +38,53 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*e
 		query = query.Where("tenant_id = ?", tenantID)
 	}
 
-	if err := query.First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found: %w", err)
-		}
+	// Usernames are unique per tenant, so without a tenant the match may be ambiguous
+	var users []entities.User
+	if err := query.Limit(2).Find(&users).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to get user", "error", err, "username", username)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &user, nil
+	switch len(users) {
+	case 0:
+		return nil, fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound)
+	case 1:
+		return &users[0], nil
+	}
+	return nil, entities.ErrTenantRequired
+}
+
+// ListByUsername retrieves every user with the username, within the tenant when one is in context.
+// Without a tenant the same username may belong to users of several tenants.
+func (r *userRepository) ListByUsername(ctx context.Context, username string) ([]entities.User, error) {
+	r.logger.InfoContext(ctx, "listing users by username", "username", username)
+
+	query := r.db.WithContext(ctx).Where("username = ?", username)
+	if tenantID, ok := ctx.Value("tenant_id").(uint); ok {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var users []entities.User
+	if err := query.Order("id").Find(&users).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list users by username", "error", err, "username", username)
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
 }
 
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*entities.User, error) {
 	r.logger.InfoContext(ctx, "getting user by ID", "id", id)
 
+	query := r.db.WithContext(ctx).Where("id = ?", id)
+
+	// Add tenant_id filter if it exists in context
+	if tenantID, ok := ctx.Value("tenant_id").(uint); ok {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
 	var user entities.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+	if err := query.First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("user not found: %w", err)
 		}
@@ -89,17 +122,26 @@ func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-// List retrieves all users
-func (r *userRepository) List(ctx context.Context) ([]*entities.User, error) {
-	r.logger.InfoContext(ctx, "listing users")
+// List retrieves the tenant's users with pagination, ordered by username
+func (r *userRepository) List(ctx context.Context, page, limit int) ([]entities.User, int64, error) {
+	r.logger.InfoContext(ctx, "listing users", "page", page, "limit", limit)
+
+	var users []entities.User
+	var total int64
 
-	var users []*entities.User
-	if err := r.db.WithContext(ctx).Where("tenant_id = ?", ctx.Value("tenant_id")).Find(&users).Error; err != nil {
+	query := r.db.WithContext(ctx).Model(&entities.User{}).Where("tenant_id = ?", ctx.Value("tenant_id"))
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count users", "error", err)
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("username").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
 		r.logger.ErrorContext(ctx, "failed to list users", "error", err)
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
 
-	return users, nil
+	return users, total, nil
 }
 
 // Update updates a user
@@ -117,3 +159,140 @@ func (r *userRepository) Update(ctx context.Context, user *entities.User) error
 	}
 	return nil
 }
+
+// HasHistory reports whether a user is referenced by sales, stock or purchasing records
+func (r *userRepository) HasHistory(ctx context.Context, id uint) (bool, error) {
+	r.logger.InfoContext(ctx, "checking user history", "id", id)
+
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM transaction_refunds WHERE user_id = @id) OR
+			EXISTS(SELECT 1 FROM stock_movements WHERE user_id = @id) OR
+			EXISTS(SELECT 1 FROM stock_opnames WHERE opened_by = @id OR committed_by = @id) OR
+			EXISTS(SELECT 1 FROM purchase_orders WHERE created_by = @id) OR
+			EXISTS(SELECT 1 FROM goods_receipts WHERE user_id = @id) OR
+			EXISTS(SELECT 1 FROM shifts WHERE user_id = @id OR closed_by = @id) OR
+			EXISTS(SELECT 1 FROM shift_cash_events WHERE user_id = @id)
+	`
+
+	var hasHistory bool
+	if err := r.db.WithContext(ctx).Raw(query, sql.Named("id", id)).Scan(&hasHistory).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to check user history", "error", err, "id", id)
+		return false, fmt.Errorf("failed to check user history: %w", err)
+	}
+
+	return hasHistory, nil
+}
+
+// UpdatePIN saves a user's PIN and failed PIN attempts without touching the other columns
+func (r *userRepository) UpdatePIN(ctx context.Context, user *entities.User) error {
+	r.logger.InfoContext(ctx, "updating user PIN", "id", user.ID)
+
+	err := r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ? AND tenant_id = ?", user.ID, ctx.Value("tenant_id")).Updates(map[string]interface{}{
+		"pin_hash":            user.PINHash,
+		"pin_failed_attempts": user.PINFailedAttempts,
+		"pin_locked_until":    user.PINLockedUntil,
+	}).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to update user PIN", "error", err, "id", user.ID)
+		return fmt.Errorf("failed to update user PIN: %w", err)
+	}
+
+	return nil
+}
+
+// RecordPINFailure counts a wrong PIN for a user with the user's row locked, so that
+// concurrent guesses are all counted. Reaching maxAttempts locks the PIN for lockout
+// and restarts the count. It returns when the PIN is locked until, or nil if it is not.
+func (r *userRepository) RecordPINFailure(ctx context.Context, id uint, maxAttempts int, lockout time.Duration) (*time.Time, error) {
+	r.logger.InfoContext(ctx, "recording failed PIN attempt", "id", id)
+
+	var lockedUntil *time.Time
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := lockPINState(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if user.PINLockedUntil != nil && now.Before(*user.PINLockedUntil) {
+			lockedUntil = user.PINLockedUntil
+			return nil
+		}
+
+		failures := user.PINFailedAttempts + 1
+		if failures >= maxAttempts {
+			until := now.Add(lockout)
+			lockedUntil = &until
+			failures = 0
+		}
+
+		return tx.Model(&entities.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"pin_failed_attempts": failures,
+			"pin_locked_until":    lockedUntil,
+		}).Error
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to record failed PIN attempt", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to record PIN attempt: %w", err)
+	}
+
+	return lockedUntil, nil
+}
+
+// ClearPINFailures resets a user's failed PIN count after a correct PIN. It returns
+// ErrPINLocked if concurrent wrong guesses locked the PIN in the meantime.
+func (r *userRepository) ClearPINFailures(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := lockPINState(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if user.PINLockedUntil != nil && time.Now().Before(*user.PINLockedUntil) {
+			return entities.ErrPINLocked
+		}
+		if user.PINFailedAttempts == 0 && user.PINLockedUntil == nil {
+			return nil
+		}
+
+		return tx.Model(&entities.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"pin_failed_attempts": 0,
+			"pin_locked_until":    nil,
+		}).Error
+	})
+	if errors.Is(err, entities.ErrPINLocked) {
+		return err
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to clear failed PIN attempts", "error", err, "id", id)
+		return fmt.Errorf("failed to clear PIN attempts: %w", err)
+	}
+
+	return nil
+}
+
+// lockPINState reads a user's PIN attempt columns with SELECT ... FOR UPDATE on tx
+func lockPINState(ctx context.Context, tx *gorm.DB, id uint) (*entities.User, error) {
+	var user entities.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "pin_failed_attempts", "pin_locked_until").
+		Where("id = ? AND tenant_id = ?", id, ctx.Value("tenant_id")).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListPINUsers retrieves the tenant's active users that have a PIN, ordered by username
+func (r *userRepository) ListPINUsers(ctx context.Context) ([]entities.User, error) {
+	r.logger.InfoContext(ctx, "listing PIN users")
+
+	var users []entities.User
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND active = ? AND pin_hash <> ''", ctx.Value("tenant_id"), true).Order("username").Find(&users).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list PIN users", "error", err)
+		return nil, fmt.Errorf("failed to list PIN users: %w", err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type voucherRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewVoucherRepository creates a new voucher repository
+func NewVoucherRepository(db *gorm.DB, logger *slog.Logger) interfaces.VoucherRepository {
+	return &voucherRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateBatch creates generated vouchers in batches
+func (r *voucherRepository) CreateBatch(ctx context.Context, vouchers []entities.Voucher) error {
+	r.logger.InfoContext(ctx, "creating vouchers", "count", len(vouchers))
+	if err := r.db.WithContext(ctx).CreateInBatches(vouchers, 500).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to create vouchers", "error", err)
+		return fmt.Errorf("failed to create vouchers: %w", err)
+	}
+	return nil
+}
+
+// ListByPromotion retrieves the vouchers of a promotion with pagination
+func (r *voucherRepository) ListByPromotion(ctx context.Context, promotionID uint, page, limit int) ([]entities.Voucher, int64, error) {
+	r.logger.InfoContext(ctx, "listing vouchers", "promotion_id", promotionID, "page", page, "limit", limit)
+
+	var vouchers []entities.Voucher
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Voucher{}).Where("promotion_id = ? AND tenant_id = ?", promotionID, ctx.Value("tenant_id"))
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to count vouchers", "error", err)
+		return nil, 0, fmt.Errorf("failed to count vouchers: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&vouchers).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list vouchers", "error", err)
+		return nil, 0, fmt.Errorf("failed to list vouchers: %w", err)
+	}
+
+	return vouchers, total, nil
+}
+
+// ListAllByPromotion retrieves every voucher of a promotion, for export
+func (r *voucherRepository) ListAllByPromotion(ctx context.Context, promotionID uint) ([]entities.Voucher, error) {
+	r.logger.InfoContext(ctx, "listing all vouchers", "promotion_id", promotionID)
+
+	var vouchers []entities.Voucher
+	if err := r.db.WithContext(ctx).Where("promotion_id = ? AND tenant_id = ?", promotionID, ctx.Value("tenant_id")).Order("id").Find(&vouchers).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to list vouchers", "error", err)
+		return nil, fmt.Errorf("failed to list vouchers: %w", err)
+	}
+
+	return vouchers, nil
+}
+
+// ExistingCodes returns which of the codes are already used by the tenant's vouchers
+func (r *voucherRepository) ExistingCodes(ctx context.Context, codes []string) ([]string, error) {
+	var existing []string
+	if err := r.db.WithContext(ctx).Model(&entities.Voucher{}).
+		Where("code IN ? AND tenant_id = ?", codes, ctx.Value("tenant_id")).
+		Pluck("code", &existing).Error; err != nil {
+		r.logger.ErrorContext(ctx, "failed to check voucher codes", "error", err)
+		return nil, fmt.Errorf("failed to check voucher codes: %w", err)
+	}
+	return existing, nil
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"net"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
@@ -10,6 +11,8 @@ import (
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"github.com/usernamesalah/rh-pos/internal/config"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"github.com/usernamesalah/rh-pos/internal/handler"
 	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
 	adminMiddleware "github.com/usernamesalah/rh-pos/internal/pkg/middleware"
@@ -25,20 +28,58 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return cv.validator.Struct(i)
 }
 
+// ipExtractor uses the connection address as the client IP, or the X-Forwarded-For
+// header when requests arrive through one of the trusted proxy ranges
+func ipExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// Only the configured ranges are trusted, not echo's default loopback and private ranges
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, ipRange := range trustedProxies {
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 // SetupRouter configures the Echo router with all routes and middleware
 func SetupRouter(
 	cfg *config.Config,
+	authService interfaces.AuthService,
+	roleService interfaces.RoleService,
 	authHandler *handler.AuthHandler,
 	productHandler *handler.ProductHandler,
 	transactionHandler *handler.TransactionHandler,
 	reportHandler *handler.ReportHandler,
 	adminHandler *handler.AdminHandler,
+	stockOpnameHandler *handler.StockOpnameHandler,
+	supplierHandler *handler.SupplierHandler,
+	purchaseOrderHandler *handler.PurchaseOrderHandler,
+	categoryHandler *handler.CategoryHandler,
+	modifierHandler *handler.ModifierHandler,
+	promotionHandler *handler.PromotionHandler,
+	customerHandler *handler.CustomerHandler,
+	loyaltyHandler *handler.LoyaltyHandler,
+	orderHandler *handler.OrderHandler,
+	shiftHandler *handler.ShiftHandler,
+	roleHandler *handler.RoleHandler,
+	userHandler *handler.UserHandler,
+	terminalHandler *handler.TerminalHandler,
 ) *echo.Echo {
 	e := echo.New()
 
 	// Set custom validator
 	e.Validator = &CustomValidator{validator: validator.New()}
 
+	// Client IPs feed login throttling and the auth audit log, so forwarding headers
+	// are only believed when they come from a configured proxy
+	e.IPExtractor = ipExtractor(cfg.Server.TrustedProxies)
+
 	// Middleware
 	e.Use(echoMiddleware.Logger())
 	e.Use(echoMiddleware.Recover())
@@ -57,6 +98,10 @@ func SetupRouter(
 	// Auth routes
 	auth := e.Group("/auth")
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/logout", authHandler.Logout)
+	auth.POST("/pin-login", authHandler.PINLogin)
+	auth.GET("/terminal/users", authHandler.TerminalUsers)
 
 	// Admin routes (protected by Basic Auth)
 	admin := e.Group("/admin")
@@ -66,6 +111,7 @@ func SetupRouter(
 	admin.GET("/tenants/:id", adminHandler.GetTenant)
 	admin.PUT("/tenants/:id", adminHandler.UpdateTenant)
 	admin.POST("/users", adminHandler.CreateUser)
+	admin.POST("/login-locks/unlock", adminHandler.UnlockLogin)
 
 	// Protected routes
 	api := e.Group("/api")
@@ -77,6 +123,7 @@ func SetupRouter(
 			claims := user.Claims.(jwt.MapClaims)
 			userID := uint(claims["user_id"].(float64))
 			c.Set("user_id", userID)
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), "user_id", userID)))
 
 			// Safely handle tenant_id claim
 			if tenantID, ok := claims["tenant_id"]; ok {
@@ -98,32 +145,182 @@ func SetupRouter(
 			}
 		},
 	}))
+	api.Use(adminMiddleware.RejectRevokedTokens(authService))
+	api.Use(adminMiddleware.LoadPermissions(authService, roleService))
+
+	// Route permissions; services check them again
+	manageProducts := adminMiddleware.RequirePermission(entities.PermProductsManage)
+	manageStock := adminMiddleware.RequirePermission(entities.PermStockManage)
+	managePurchasing := adminMiddleware.RequirePermission(entities.PermPurchasingManage)
+	createSales := adminMiddleware.RequirePermission(entities.PermSalesCreate)
+	viewSales := adminMiddleware.RequirePermission(entities.PermSalesView)
+	voidSales := adminMiddleware.RequirePermission(entities.PermSalesVoid)
+	viewReports := adminMiddleware.RequirePermission(entities.PermReportsView)
+	managePromotions := adminMiddleware.RequirePermission(entities.PermPromotionsManage)
+	manageCustomers := adminMiddleware.RequirePermission(entities.PermCustomersManage)
+	manageLoyalty := adminMiddleware.RequirePermission(entities.PermLoyaltyManage)
+	manageShifts := adminMiddleware.RequirePermission(entities.PermShiftsManage)
+	manageRoles := adminMiddleware.RequirePermission(entities.PermRolesManage)
+	manageUsers := adminMiddleware.RequirePermission(entities.PermUsersManage)
+	manageTerminals := adminMiddleware.RequirePermission(entities.PermTerminalsManage)
 
 	// User routes
 	api.GET("/profile", authHandler.GetProfile)
 	api.GET("/my-tenant", authHandler.GetMyTenant)
 	api.PUT("/update-password", authHandler.UpdatePassword)
+	api.POST("/logout-all", authHandler.LogoutAll)
+	api.PUT("/pin", authHandler.SetPIN)
 
 	// Product routes
 	products := api.Group("/products")
 	products.GET("", productHandler.ListProducts)
-	products.POST("", productHandler.CreateProduct)
+	products.POST("", productHandler.CreateProduct, manageProducts)
+	products.GET("/low-stock", productHandler.ListLowStockProducts)
+	products.GET("/tags", productHandler.ListTags)
+	products.GET("/barcode/:code", productHandler.LookupBarcode)
 	products.GET("/:id", productHandler.GetProduct)
-	products.PUT("/:id", productHandler.UpdateProduct)
-	products.PUT("/:id/stock", productHandler.UpdateStock)
-	products.POST("/:id/upload-url", productHandler.GetUploadURL)
+	products.PUT("/:id", productHandler.UpdateProduct, manageProducts)
+	products.PUT("/:id/stock", productHandler.UpdateStock, manageStock)
+	products.GET("/:id/stock-history", productHandler.GetStockHistory, manageStock)
+	products.POST("/:id/barcodes", productHandler.AddBarcode, manageProducts)
+	products.POST("/:id/variants", productHandler.GenerateVariants, manageProducts)
+	products.PUT("/:id/recipe", productHandler.SetRecipe, manageProducts)
+	products.PUT("/:id/modifier-groups", modifierHandler.SetProductModifierGroups, manageProducts)
+	products.DELETE("/:id/barcodes/:barcodeId", productHandler.RemoveBarcode, manageProducts)
+	products.POST("/:id/upload-url", productHandler.GetUploadURL, manageProducts)
 	products.GET("/:id/image/bytes", productHandler.GetProductImageBytes)
-	products.POST("/:id/image", productHandler.UploadProductImage)
+	products.POST("/:id/image", productHandler.UploadProductImage, manageProducts)
+
+	// Category routes
+	categories := api.Group("/categories")
+	categories.POST("", categoryHandler.CreateCategory, manageProducts)
+	categories.GET("", categoryHandler.ListCategories)
+	categories.GET("/:id", categoryHandler.GetCategory)
+	categories.PUT("/:id", categoryHandler.UpdateCategory, manageProducts)
+	categories.DELETE("/:id", categoryHandler.DeleteCategory, manageProducts)
+
+	// Modifier group routes
+	modifierGroups := api.Group("/modifier-groups")
+	modifierGroups.POST("", modifierHandler.CreateModifierGroup, manageProducts)
+	modifierGroups.GET("", modifierHandler.ListModifierGroups)
+	modifierGroups.GET("/:id", modifierHandler.GetModifierGroup)
+	modifierGroups.PUT("/:id", modifierHandler.UpdateModifierGroup, manageProducts)
+	modifierGroups.DELETE("/:id", modifierHandler.DeleteModifierGroup, manageProducts)
+
+	// Promotion routes
+	promotions := api.Group("/promotions")
+	promotions.POST("", promotionHandler.CreatePromotion, managePromotions)
+	promotions.GET("", promotionHandler.ListPromotions)
+	promotions.GET("/:id", promotionHandler.GetPromotion)
+	promotions.PUT("/:id", promotionHandler.UpdatePromotion, managePromotions)
+	promotions.DELETE("/:id", promotionHandler.DeletePromotion, managePromotions)
+	promotions.POST("/:id/vouchers", promotionHandler.GenerateVouchers, managePromotions)
+	promotions.GET("/:id/vouchers", promotionHandler.ListVouchers, managePromotions)
+	promotions.GET("/:id/vouchers/export", promotionHandler.ExportVouchers, managePromotions)
+
+	// Customer routes
+	customers := api.Group("/customers")
+	customers.POST("", customerHandler.CreateCustomer)
+	customers.GET("", customerHandler.ListCustomers)
+	customers.GET("/phone/:phone", customerHandler.LookupPhone)
+	customers.GET("/:id", customerHandler.GetCustomer)
+	customers.PUT("/:id", customerHandler.UpdateCustomer)
+	customers.DELETE("/:id", customerHandler.DeleteCustomer, manageCustomers)
+	customers.GET("/:id/transactions", customerHandler.ListCustomerTransactions)
+
+	// Shift routes
+	shifts := api.Group("/shifts")
+	shifts.POST("", shiftHandler.OpenShift)
+	shifts.GET("", shiftHandler.ListShifts, manageShifts)
+	shifts.GET("/current", shiftHandler.GetCurrentShift)
+	shifts.GET("/:id", shiftHandler.GetShift)
+	shifts.POST("/:id/cash-in", shiftHandler.CashIn)
+	shifts.POST("/:id/cash-out", shiftHandler.CashOut)
+	shifts.POST("/:id/close", shiftHandler.CloseShift)
+	shifts.GET("/:id/report", shiftHandler.GetShiftReport)
+
+	// Tenant user routes
+	users := api.Group("/users", manageUsers)
+	users.GET("", userHandler.ListUsers)
+	users.POST("", userHandler.CreateUser)
+	users.GET("/:id", userHandler.GetUser)
+	users.PUT("/:id", userHandler.UpdateUser)
+	users.DELETE("/:id", userHandler.DeleteUser)
+	users.POST("/:id/reset-password", userHandler.ResetPassword)
+	users.POST("/:id/unlock", userHandler.UnlockUser)
+
+	// Role routes
+	roles := api.Group("/roles", manageRoles)
+	roles.GET("", roleHandler.ListRoles)
+	roles.GET("/permissions", roleHandler.ListPermissions)
+	roles.POST("", roleHandler.CreateRole)
+	roles.GET("/:id", roleHandler.GetRole)
+	roles.PUT("/:id", roleHandler.UpdateRole)
+	roles.DELETE("/:id", roleHandler.DeleteRole)
+
+	// Terminal routes
+	terminals := api.Group("/terminals", manageTerminals)
+	terminals.GET("", terminalHandler.ListTerminals)
+	terminals.POST("", terminalHandler.RegisterTerminal)
+	terminals.DELETE("/:id", terminalHandler.RevokeTerminal)
+
+	// Open order routes
+	orders := api.Group("/orders", createSales)
+	orders.POST("", orderHandler.OpenOrder)
+	orders.GET("", orderHandler.ListOrders)
+	orders.PUT("/:id", orderHandler.UpdateOrder)
+	orders.POST("/:id/items", orderHandler.AddOrderItem)
+	orders.DELETE("/:id/items/:itemId", orderHandler.RemoveOrderItem)
+	orders.POST("/:id/hold", orderHandler.HoldOrder)
+	orders.POST("/:id/resume", orderHandler.ResumeOrder)
+	orders.POST("/:id/cancel", orderHandler.CancelOrder)
+	orders.POST("/:id/settle", orderHandler.SettleOrder)
+
+	// Loyalty routes
+	loyalty := api.Group("/loyalty")
+	loyalty.GET("/program", loyaltyHandler.GetProgram)
+	loyalty.PUT("/program", loyaltyHandler.UpdateProgram, manageLoyalty)
+	loyalty.GET("/accounts/:phone", loyaltyHandler.GetAccount)
+	loyalty.GET("/accounts/:phone/ledger", loyaltyHandler.ListEntries)
 
 	// Transaction routes
 	transactions := api.Group("/transactions")
-	transactions.POST("", transactionHandler.CreateTransaction)
-	transactions.GET("", transactionHandler.ListTransactions)
-	transactions.GET("/:id", transactionHandler.GetTransaction)
+	transactions.POST("", transactionHandler.CreateTransaction, createSales)
+	transactions.GET("", transactionHandler.ListTransactions, viewSales)
+	transactions.GET("/:id", transactionHandler.GetTransaction, viewSales)
+	transactions.POST("/:id/void", transactionHandler.VoidTransaction, voidSales)
+	transactions.POST("/:id/refund", transactionHandler.RefundTransaction, voidSales)
 
 	// Report routes
-	reports := api.Group("/reports")
+	reports := api.Group("/reports", viewReports)
 	reports.GET("", reportHandler.GetSalesReport)
 
+	// Stock opname routes
+	stockOpnames := api.Group("/stock-opnames", manageStock)
+	stockOpnames.POST("", stockOpnameHandler.OpenSession)
+	stockOpnames.GET("", stockOpnameHandler.ListSessions)
+	stockOpnames.GET("/:id", stockOpnameHandler.GetSession)
+	stockOpnames.POST("/:id/counts", stockOpnameHandler.RecordCounts)
+	stockOpnames.GET("/:id/variance", stockOpnameHandler.GetVariance)
+	stockOpnames.POST("/:id/commit", stockOpnameHandler.CommitSession)
+	stockOpnames.POST("/:id/cancel", stockOpnameHandler.CancelSession)
+
+	// Supplier routes
+	suppliers := api.Group("/suppliers", managePurchasing)
+	suppliers.POST("", supplierHandler.CreateSupplier)
+	suppliers.GET("", supplierHandler.ListSuppliers)
+	suppliers.GET("/:id", supplierHandler.GetSupplier)
+	suppliers.PUT("/:id", supplierHandler.UpdateSupplier)
+	suppliers.DELETE("/:id", supplierHandler.DeleteSupplier)
+
+	// Purchase order routes
+	purchaseOrders := api.Group("/purchase-orders", managePurchasing)
+	purchaseOrders.POST("", purchaseOrderHandler.CreatePurchaseOrder)
+	purchaseOrders.GET("", purchaseOrderHandler.ListPurchaseOrders)
+	purchaseOrders.GET("/outstanding", purchaseOrderHandler.GetOutstandingReport)
+	purchaseOrders.GET("/:id", purchaseOrderHandler.GetPurchaseOrder)
+	purchaseOrders.POST("/:id/receive", purchaseOrderHandler.ReceiveGoods)
+	purchaseOrders.POST("/:id/cancel", purchaseOrderHandler.CancelPurchaseOrder)
+
 	return e
 }
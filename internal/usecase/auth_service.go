@@ -2,55 +2,584 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
 	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/loginguard"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// pinPattern matches the 4 to 6 digit PINs used to sign in on terminals
+var pinPattern = regexp.MustCompile(`^[0-9]{4,6}$`)
+
+// PIN lockout: wrong PINs in a row before the PIN locks, and for how long
+const (
+	maxPINAttempts = 5
+	pinLockout     = 15 * time.Minute
 )
 
 type authService struct {
-	userRepo  interfaces.UserRepository
-	jwtSecret string
-	logger    *slog.Logger
+	userRepo        interfaces.UserRepository
+	sessionRepo     interfaces.SessionRepository
+	terminalRepo    interfaces.TerminalRepository
+	auditRepo       interfaces.AuthAuditRepository
+	loginGuard      *loginguard.Guard
+	jwtSecret       string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	logger          *slog.Logger
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userRepo interfaces.UserRepository, jwtSecret string, logger *slog.Logger) interfaces.AuthService {
+func NewAuthService(userRepo interfaces.UserRepository, sessionRepo interfaces.SessionRepository, terminalRepo interfaces.TerminalRepository, auditRepo interfaces.AuthAuditRepository, loginGuard *loginguard.Guard, jwtSecret string, accessTokenTTL, refreshTokenTTL time.Duration, logger *slog.Logger) interfaces.AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		logger:    logger,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		terminalRepo:    terminalRepo,
+		auditRepo:       auditRepo,
+		loginGuard:      loginGuard,
+		jwtSecret:       jwtSecret,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+		logger:          logger,
 	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *authService) Login(ctx context.Context, username, password string) (string, *entities.User, error) {
+// Login authenticates a user and starts a session with an access and refresh token.
+// Failed attempts are counted per account and per IP address; repeated failures
+// delay further attempts and then lock the account or address out for a while.
+func (s *authService) Login(ctx context.Context, username, password string, client interfaces.ClientInfo) (*interfaces.AuthTokens, *entities.User, error) {
 	s.logger.InfoContext(ctx, "attempting login", "username", username)
 
-	// Get user by username
-	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err := s.loginGuard.Check(ctx, client.IPAddress); err != nil {
+		return nil, nil, s.loginRefused(ctx, username, client, err)
+	}
+
+	// Usernames are unique per tenant, so without a tenant several users may match
+	candidates, err := s.userRepo.ListByUsername(ctx, username)
 	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if len(candidates) == 0 {
+		// A name no user has is counted like an account, so delays do not reveal which usernames exist
+		tenantID, _ := ctx.Value("tenant_id").(uint)
+		unknown := loginguard.Account{TenantID: tenantID, Username: username}
+		if err := s.loginGuard.Check(ctx, "", unknown); err != nil {
+			return nil, nil, s.loginRefused(ctx, username, client, err)
+		}
 		s.logger.WarnContext(ctx, "login failed: user not found", "username", username)
-		return "", nil, fmt.Errorf("invalid credentials")
+		return nil, nil, s.recordLoginFailure(ctx, username, nil, client, "unknown username", unknown)
 	}
 
-	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+	// Blocked accounts are left out so their password cannot be tried through a login naming no tenant
+	var (
+		open    []*entities.User
+		refused error
+	)
+	for i := range candidates {
+		err := s.loginGuard.Check(ctx, "", loginAccount(&candidates[i]))
+		var blocked *loginguard.BlockedError
+		if errors.As(err, &blocked) {
+			if refused == nil {
+				refused = err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		open = append(open, &candidates[i])
+	}
+	if len(open) == 0 {
+		return nil, nil, s.loginRefused(ctx, username, client, refused)
+	}
+
+	// Check the password before revealing that the username is ambiguous
+	var matched []*entities.User
+	for _, candidate := range open {
+		if bcrypt.CompareHashAndPassword([]byte(candidate.Password), []byte(password)) == nil {
+			matched = append(matched, candidate)
+		}
+	}
+	if len(matched) == 0 {
 		s.logger.WarnContext(ctx, "login failed: invalid password", "username", username)
-		return "", nil, fmt.Errorf("invalid credentials")
+
+		// The password was tried against every open account, so the failure counts for each
+		var user *entities.User
+		accounts := make([]loginguard.Account, len(open))
+		for i, candidate := range open {
+			accounts[i] = loginAccount(candidate)
+		}
+		if len(open) == 1 {
+			user = open[0]
+		}
+		return nil, nil, s.recordLoginFailure(ctx, username, user, client, "invalid password", accounts...)
+	}
+	if len(matched) > 1 {
+		s.logger.WarnContext(ctx, "login failed: username in several tenants", "username", username)
+		return nil, nil, entities.ErrTenantRequired
+	}
+	user := matched[0]
+
+	if err := s.loginGuard.RecordSuccess(ctx, loginAccount(user)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to reset login attempts", "error", err, "username", username)
+	}
+
+	if !user.Active {
+		s.logger.WarnContext(ctx, "login failed: user deactivated", "username", username)
+		s.audit(ctx, entities.AuthEventLoginFailed, username, user, client, "user deactivated")
+		return nil, nil, entities.ErrUserInactive
+	}
+
+	tokens, err := s.startSession(ctx, user, nil, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.audit(ctx, entities.AuthEventLoginSucceeded, username, user, client, "")
+	s.logger.InfoContext(ctx, "login successful", "username", username)
+	return tokens, user, nil
+}
+
+// loginAccount returns the account failed logins of user are counted against
+func loginAccount(user *entities.User) loginguard.Account {
+	account := loginguard.Account{Username: user.Username}
+	if user.TenantID != nil {
+		account.TenantID = *user.TenantID
+	}
+	return account
+}
+
+// loginRefused logs and audits a login refused because of earlier failures and returns err
+func (s *authService) loginRefused(ctx context.Context, username string, client interfaces.ClientInfo, err error) error {
+	var blocked *loginguard.BlockedError
+	if errors.As(err, &blocked) {
+		s.logger.WarnContext(ctx, "login refused: too many failures", "username", username, "ip_address", client.IPAddress, "until", blocked.Until)
+		s.audit(ctx, entities.AuthEventLoginBlocked, username, nil, client, err.Error())
+	}
+	return err
+}
+
+// recordLoginFailure counts and audits a failed login against the address and accounts.
+// It returns the error for the caller: the lockout if this failure caused one, otherwise
+// invalid credentials.
+func (s *authService) recordLoginFailure(ctx context.Context, username string, user *entities.User, client interfaces.ClientInfo, reason string, accounts ...loginguard.Account) error {
+	s.audit(ctx, entities.AuthEventLoginFailed, username, user, client, reason)
+
+	err := s.loginGuard.RecordFailure(ctx, client.IPAddress, accounts...)
+	var blocked *loginguard.BlockedError
+	if errors.As(err, &blocked) {
+		s.logger.WarnContext(ctx, "login locked after repeated failures", "username", username, "ip_address", client.IPAddress, "until", blocked.Until)
+		s.audit(ctx, entities.AuthEventAccountLocked, username, user, client, err.Error())
+		return err
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to record login attempt", "error", err, "username", username)
+	}
+
+	return fmt.Errorf("invalid credentials")
+}
+
+// UnlockLogin clears the failed login counters, backoff and lockout of a username
+// and/or an IP address. The username is unlocked for every user that has it, within
+// the tenant in context if there is one.
+func (s *authService) UnlockLogin(ctx context.Context, username, ipAddress string) error {
+	s.logger.InfoContext(ctx, "unlocking login", "username", username, "ip_address", ipAddress)
+
+	var accounts []loginguard.Account
+	if username != "" {
+		users, err := s.userRepo.ListByUsername(ctx, username)
+		if err != nil {
+			return fmt.Errorf("failed to unlock login: %w", err)
+		}
+		for i := range users {
+			accounts = append(accounts, loginAccount(&users[i]))
+		}
+
+		// Failures for the name while no user had it are counted apart
+		tenantID, _ := ctx.Value("tenant_id").(uint)
+		accounts = append(accounts, loginguard.Account{TenantID: tenantID, Username: username})
+	}
+
+	if err := s.loginGuard.Unlock(ctx, ipAddress, accounts...); err != nil {
+		return err
+	}
+
+	detail := "unlocked by administrator"
+	if userID, ok := ctx.Value("user_id").(uint); ok {
+		detail = fmt.Sprintf("unlocked by user %d", userID)
+	}
+	if ipAddress != "" {
+		detail += " for " + ipAddress
+	}
+	s.audit(ctx, entities.AuthEventLoginUnlocked, username, nil, interfaces.ClientInfo{}, detail)
+
+	return nil
+}
+
+// audit records an authentication audit entry. Failing to record it is logged
+// but does not fail the login.
+func (s *authService) audit(ctx context.Context, event, username string, user *entities.User, client interfaces.ClientInfo, detail string) {
+	entry := &entities.AuthAuditLog{
+		Event:     event,
+		Username:  username,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Detail:    detail,
+	}
+	if user != nil {
+		entry.UserID = &user.ID
+		entry.TenantID = user.TenantID
+	} else if tenantID, ok := ctx.Value("tenant_id").(uint); ok {
+		entry.TenantID = &tenantID
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record auth audit entry", "error", err, "event", event, "username", username)
+	}
+}
+
+// PINLogin signs a user in on a registered terminal with their PIN. Only users of the
+// terminal's tenant can sign in, and signing in switches the terminal to the user by
+// ending the session of whoever was signed in on it before.
+func (s *authService) PINLogin(ctx context.Context, deviceToken, username, pin string, client interfaces.ClientInfo) (*interfaces.AuthTokens, *entities.User, error) {
+	s.logger.InfoContext(ctx, "attempting PIN login", "username", username)
+
+	terminal, err := s.activeTerminal(ctx, deviceToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx = context.WithValue(ctx, "tenant_id", *terminal.TenantID)
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		s.logger.WarnContext(ctx, "PIN login failed: user not found", "username", username, "terminal_id", terminal.ID)
+		return nil, nil, fmt.Errorf("invalid credentials")
+	}
+	if err := s.checkPIN(ctx, user, pin); err != nil {
+		return nil, nil, err
+	}
+	if !user.Active {
+		s.logger.WarnContext(ctx, "PIN login failed: user deactivated", "username", username)
+		return nil, nil, entities.ErrUserInactive
+	}
+
+	if _, err := s.sessionRepo.RevokeAllForTerminal(ctx, terminal.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to switch user: %w", err)
+	}
+
+	tokens, err := s.startSession(ctx, user, &terminal.ID, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	terminal.LastSeenAt = &now
+	if err := s.terminalRepo.Update(ctx, terminal); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record terminal activity", "error", err, "terminal_id", terminal.ID)
+	}
+
+	s.logger.InfoContext(ctx, "PIN login successful", "username", username, "terminal_id", terminal.ID)
+	return tokens, user, nil
+}
+
+// TerminalUsers lists the users who can sign in on a registered terminal with a PIN
+func (s *authService) TerminalUsers(ctx context.Context, deviceToken string) ([]entities.User, error) {
+	terminal, err := s.activeTerminal(ctx, deviceToken)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "tenant_id", *terminal.TenantID)
+
+	users, err := s.userRepo.ListPINUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terminal users: %w", err)
+	}
+
+	return users, nil
+}
+
+// SetPIN sets the PIN the user signs in with on terminals. The password confirms
+// the change, and setting a PIN clears any PIN lockout.
+func (s *authService) SetPIN(ctx context.Context, userID uint, password, pin string) error {
+	s.logger.InfoContext(ctx, "setting PIN", "user_id", userID)
+
+	if !pinPattern.MatchString(pin) {
+		return entities.ErrInvalidPIN
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		s.logger.WarnContext(ctx, "set PIN failed: invalid password", "user_id", userID)
+		return fmt.Errorf("invalid current password")
+	}
+
+	hashedPIN, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+
+	user.PINHash = string(hashedPIN)
+	user.PINFailedAttempts = 0
+	user.PINLockedUntil = nil
+	if err := s.userRepo.UpdatePIN(ctx, user); err != nil {
+		return fmt.Errorf("failed to set PIN: %w", err)
+	}
+
+	return nil
+}
+
+// activeTerminal retrieves the registered terminal a device token belongs to
+func (s *authService) activeTerminal(ctx context.Context, deviceToken string) (*entities.Terminal, error) {
+	if deviceToken == "" {
+		return nil, entities.ErrInvalidTerminal
+	}
+
+	terminal, err := s.terminalRepo.GetByTokenHash(ctx, hashToken(deviceToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WarnContext(ctx, "unknown terminal token")
+			return nil, entities.ErrInvalidTerminal
+		}
+		return nil, fmt.Errorf("failed to get terminal: %w", err)
+	}
+	if terminal.RevokedAt != nil {
+		s.logger.WarnContext(ctx, "revoked terminal used", "terminal_id", terminal.ID)
+		return nil, entities.ErrInvalidTerminal
+	}
+
+	return terminal, nil
+}
+
+// checkPIN verifies a user's PIN. After maxPINAttempts wrong PINs in a row the PIN
+// is locked for pinLockout; a correct PIN resets the count. Attempts are counted on
+// the locked user row so parallel guesses cannot slip past the limit.
+func (s *authService) checkPIN(ctx context.Context, user *entities.User, pin string) error {
+	if user.PINLockedUntil != nil && time.Now().Before(*user.PINLockedUntil) {
+		s.logger.WarnContext(ctx, "PIN login failed: PIN locked", "username", user.Username)
+		return entities.ErrPINLocked
+	}
+
+	if !user.HasPIN() {
+		s.logger.WarnContext(ctx, "PIN login failed: no PIN set", "username", user.Username)
+		return fmt.Errorf("invalid credentials")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PINHash), []byte(pin)); err != nil {
+		s.logger.WarnContext(ctx, "PIN login failed: invalid PIN", "username", user.Username)
+		lockedUntil, err := s.userRepo.RecordPINFailure(ctx, user.ID, maxPINAttempts, pinLockout)
+		if err != nil {
+			return err
+		}
+		if lockedUntil != nil {
+			s.logger.WarnContext(ctx, "PIN locked after repeated failures", "username", user.Username, "until", *lockedUntil)
+			return entities.ErrPINLocked
+		}
+		return fmt.Errorf("invalid credentials")
+	}
+
+	// A correct PIN is refused if parallel wrong guesses locked the PIN meanwhile
+	if err := s.userRepo.ClearPINFailures(ctx, user.ID); err != nil {
+		if errors.Is(err, entities.ErrPINLocked) {
+			s.logger.WarnContext(ctx, "PIN login failed: PIN locked", "username", user.Username)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// startSession creates a session for user, tied to terminalID when signing in on a
+// terminal, and issues its first access and refresh tokens
+func (s *authService) startSession(ctx context.Context, user *entities.User, terminalID *uint, client interfaces.ClientInfo) (*interfaces.AuthTokens, error) {
+	now := time.Now()
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	accessToken, jti, accessExpiresAt, err := s.generateAccessToken(user, terminalID, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to generate token", "error", err, "user_id", user.ID)
+		return nil, err
+	}
+
+	session := &entities.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshHash,
+		AccessTokenID:    jti,
+		AccessExpiresAt:  accessExpiresAt,
+		TerminalID:       terminalID,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		ExpiresAt:        now.Add(s.refreshTokenTTL),
+		LastUsedAt:       now,
+		TenantID:         user.TenantID,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	return &interfaces.AuthTokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// The presented refresh token stops working; presenting it again is treated as theft
+// and revokes the session.
+func (s *authService) Refresh(ctx context.Context, refreshToken string, client interfaces.ClientInfo) (*interfaces.AuthTokens, *entities.User, error) {
+	hash := hashToken(refreshToken)
+
+	session, err := s.sessionRepo.GetByRefreshTokenHash(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("failed to refresh session: %w", err)
+		}
+		s.revokeReusedToken(ctx, hash)
+		return nil, nil, entities.ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if !session.Active(now) {
+		s.logger.WarnContext(ctx, "refresh failed: session ended", "session_id", session.ID)
+		return nil, nil, entities.ErrInvalidRefreshToken
+	}
+
+	// Users are looked up across tenants because refresh requests carry no access token
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.Active {
+		s.logger.WarnContext(ctx, "refresh failed: user deactivated", "user_id", user.ID)
+		if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, entities.ErrUserInactive
+	}
+
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	accessToken, jti, accessExpiresAt, err := s.generateAccessToken(user, session.TerminalID, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to generate token", "error", err, "user_id", user.ID)
+		return nil, nil, err
+	}
+
+	replaced := entities.RevokedToken{JTI: session.AccessTokenID, ExpiresAt: session.AccessExpiresAt}
+	session.PreviousTokenHash = session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.AccessTokenID = jti
+	session.AccessExpiresAt = accessExpiresAt
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
+	session.LastUsedAt = now
+	if err := s.sessionRepo.Rotate(ctx, session, replaced); err != nil {
+		if !errors.Is(err, entities.ErrInvalidRefreshToken) {
+			return nil, nil, fmt.Errorf("failed to refresh session: %w", err)
+		}
+		// Another request spent the same refresh token first, so treat this one as reuse
+		s.logger.WarnContext(ctx, "refresh token reused concurrently, revoking session", "session_id", session.ID, "user_id", session.UserID)
+		if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+			s.logger.ErrorContext(ctx, "failed to revoke session", "error", err, "session_id", session.ID)
+		}
+		return nil, nil, entities.ErrInvalidRefreshToken
+	}
+
+	s.logger.InfoContext(ctx, "session refreshed", "user_id", user.ID, "session_id", session.ID)
+	return &interfaces.AuthTokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          newToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, user, nil
+}
+
+// revokeReusedToken revokes the session a rotated refresh token belonged to, if any
+func (s *authService) revokeReusedToken(ctx context.Context, hash string) {
+	session, err := s.sessionRepo.GetByPreviousTokenHash(ctx, hash)
+	if err != nil || session.RevokedAt != nil {
+		return
+	}
+
+	s.logger.WarnContext(ctx, "rotated refresh token reused, revoking session", "session_id", session.ID, "user_id", session.UserID)
+	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		s.logger.ErrorContext(ctx, "failed to revoke session", "error", err, "session_id", session.ID)
+	}
+}
+
+// Logout ends the session a refresh token belongs to and revokes its access token.
+// Unknown or already revoked tokens are ignored.
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.sessionRepo.GetByRefreshTokenHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "logging out", "user_id", session.UserID, "session_id", session.ID)
+	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return fmt.Errorf("failed to log out: %w", err)
 	}
 
-	// Generate JWT token
+	return nil
+}
+
+// LogoutAll ends every session of a user on all devices
+func (s *authService) LogoutAll(ctx context.Context, userID uint) error {
+	revoked, err := s.sessionRepo.RevokeAllForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to log out all devices: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "logged out all devices", "user_id", userID, "sessions", revoked)
+	return nil
+}
+
+// IsTokenRevoked reports whether an access token has been revoked by a logout
+func (s *authService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.sessionRepo.IsTokenRevoked(ctx, jti)
+}
+
+// generateAccessToken signs a short-lived access token for user with a random jti.
+// Tokens for terminal sessions also carry the terminal they were issued to.
+func (s *authService) generateAccessToken(user *entities.User, terminalID *uint, now time.Time) (string, string, time.Time, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	expiresAt := now.Add(s.accessTokenTTL)
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":      jti,
 		"user_id":  user.ID,
 		"username": user.Username,
 		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+		"iat":      now.Unix(),
+		"exp":      expiresAt.Unix(),
 	})
 
 	// Add tenant_id to claims if it exists
@@ -59,15 +588,41 @@ func (s *authService) Login(ctx context.Context, username, password string) (str
 		hashedTenantID := hash.HashID(*user.TenantID)
 		token.Claims.(jwt.MapClaims)["tenant_id"] = hashedTenantID
 	}
+	if terminalID != nil {
+		token.Claims.(jwt.MapClaims)["terminal_id"] = hash.HashID(*terminalID)
+	}
 
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
-		s.logger.ErrorContext(ctx, "failed to generate token", "error", err, "username", username)
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+		return "", "", time.Time{}, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	s.logger.InfoContext(ctx, "login successful", "username", username)
-	return tokenString, user, nil
+	return tokenString, jti, expiresAt, nil
+}
+
+// newRefreshToken returns a random refresh token and the hash stored for it
+func newRefreshToken() (string, string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, hashToken(token), nil
+}
+
+// hashToken hashes a refresh or terminal device token for storage. The tokens are
+// random, so a fast hash is enough and lets their records be looked up by it.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken returns n random bytes encoded for use in URLs and headers
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
 // ValidateToken validates a JWT token and returns the user
@@ -0,0 +1,295 @@
+//go:build integration
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/loginguard"
+	"github.com/usernamesalah/rh-pos/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// testLoginPolicy locks an account out after a few failures without delaying the ones before
+var testLoginPolicy = loginguard.Policy{
+	Window:          time.Hour,
+	BackoffAfter:    3,
+	LockoutAfter:    3,
+	LockoutDuration: time.Hour,
+}
+
+func newTestAuthService(t *testing.T) (interfaces.AuthService, *gorm.DB) {
+	db, logger := openTestDB(t)
+	guard := loginguard.NewGuard(loginguard.NewMemoryStore(time.Hour), testLoginPolicy, loginguard.DefaultIPPolicy)
+	svc := NewAuthService(
+		repository.NewUserRepository(db, logger),
+		repository.NewSessionRepository(db, logger),
+		repository.NewTerminalRepository(db, logger),
+		repository.NewAuthAuditRepository(db, logger),
+		guard,
+		"integration-test-secret",
+		15*time.Minute,
+		time.Hour,
+		logger,
+	)
+	return svc, db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, tenantID uint, username, password string) *entities.User {
+	t.Helper()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	user := &entities.User{
+		Username: username,
+		Password: string(hashed),
+		Role:     entities.RoleCashier,
+		Active:   true,
+		TenantID: &tenantID,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// runConcurrently calls fn from n goroutines released at the same moment and returns their errors
+func runConcurrently(n int, fn func() error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, n)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = fn()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+func TestRefreshTokenIsSingleUseUnderConcurrency(t *testing.T) {
+	svc, db := newTestAuthService(t)
+	ctx, tenantID := newTestTenant(t, db)
+	createTestUser(t, db, tenantID, "refresher", "secret123")
+
+	client := interfaces.ClientInfo{UserAgent: "integration-test", IPAddress: "192.0.2.10"}
+	tokens, _, err := svc.Login(ctx, "refresher", "secret123", client)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		rotated []string
+	)
+	errs := runConcurrently(10, func() error {
+		refreshed, _, err := svc.Refresh(context.Background(), tokens.RefreshToken, client)
+		if err == nil {
+			mu.Lock()
+			rotated = append(rotated, refreshed.RefreshToken)
+			mu.Unlock()
+		}
+		return err
+	})
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, entities.ErrInvalidRefreshToken) {
+			t.Errorf("refresh failed with an unexpected error: %v", err)
+		}
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("%d concurrent refreshes succeeded, want 1", len(rotated))
+	}
+
+	// The losing requests count as reuse of a spent token, which ends the session
+	if _, _, err := svc.Refresh(context.Background(), rotated[0], client); !errors.Is(err, entities.ErrInvalidRefreshToken) {
+		t.Errorf("refresh with the rotated token after reuse: got %v, want %v", err, entities.ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshTokenReuseAfterRotationRevokesSession(t *testing.T) {
+	svc, db := newTestAuthService(t)
+	ctx, tenantID := newTestTenant(t, db)
+	createTestUser(t, db, tenantID, "rotator", "secret123")
+
+	client := interfaces.ClientInfo{UserAgent: "integration-test", IPAddress: "192.0.2.11"}
+	tokens, _, err := svc.Login(ctx, "rotator", "secret123", client)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	refreshed, _, err := svc.Refresh(context.Background(), tokens.RefreshToken, client)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	if _, _, err := svc.Refresh(context.Background(), tokens.RefreshToken, client); !errors.Is(err, entities.ErrInvalidRefreshToken) {
+		t.Fatalf("reusing the old token: got %v, want %v", err, entities.ErrInvalidRefreshToken)
+	}
+	if _, _, err := svc.Refresh(context.Background(), refreshed.RefreshToken, client); !errors.Is(err, entities.ErrInvalidRefreshToken) {
+		t.Errorf("refresh after reuse: got %v, want %v", err, entities.ErrInvalidRefreshToken)
+	}
+
+	revoked, err := svc.IsTokenRevoked(context.Background(), jtiOf(t, refreshed.AccessToken))
+	if err != nil {
+		t.Fatalf("failed to check revoked token: %v", err)
+	}
+	if !revoked {
+		t.Error("access token of the reused session is not revoked")
+	}
+}
+
+// jtiOf returns the token ID claim of an access token issued by the test service
+func jtiOf(t *testing.T, accessToken string) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(accessToken, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("integration-test-secret"), nil
+	}); err != nil {
+		t.Fatalf("failed to parse access token: %v", err)
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		t.Fatal("access token has no jti")
+	}
+	return jti
+}
+
+func createTestPINUser(t *testing.T, db *gorm.DB, tenantID uint, username, pin string) *entities.User {
+	t.Helper()
+
+	user := createTestUser(t, db, tenantID, username, "secret123")
+	hashed, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash PIN: %v", err)
+	}
+	user.PINHash = string(hashed)
+	if err := db.Model(user).Update("pin_hash", user.PINHash).Error; err != nil {
+		t.Fatalf("failed to set PIN: %v", err)
+	}
+	return user
+}
+
+func pinState(t *testing.T, db *gorm.DB, id uint) entities.User {
+	t.Helper()
+
+	var user entities.User
+	if err := db.Select("pin_failed_attempts", "pin_locked_until").Where("id = ?", id).First(&user).Error; err != nil {
+		t.Fatalf("failed to read PIN state: %v", err)
+	}
+	return user
+}
+
+func TestPINFailuresAreCountedUnderConcurrency(t *testing.T) {
+	svc, db := newTestAuthService(t)
+	auth := svc.(*authService)
+	ctx, tenantID := newTestTenant(t, db)
+
+	tests := []struct {
+		name       string
+		guesses    int
+		wantLocked bool
+		wantCount  int
+	}{
+		{"below the limit", maxPINAttempts - 1, false, maxPINAttempts - 1},
+		{"far past the limit", 4 * maxPINAttempts, true, 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := createTestPINUser(t, db, tenantID, fmt.Sprintf("pin-user-%d", i), "1234")
+
+			// Every goroutine works from the same stale copy, as parallel requests would
+			errs := runConcurrently(tt.guesses, func() error {
+				stale := *user
+				return auth.checkPIN(ctx, &stale, "9999")
+			})
+			for _, err := range errs {
+				if err == nil {
+					t.Fatal("a wrong PIN was accepted")
+				}
+			}
+
+			state := pinState(t, db, user.ID)
+			locked := state.PINLockedUntil != nil && time.Now().Before(*state.PINLockedUntil)
+			if locked != tt.wantLocked {
+				t.Errorf("locked = %v, want %v", locked, tt.wantLocked)
+			}
+			if state.PINFailedAttempts != tt.wantCount {
+				t.Errorf("failed attempts = %d, want %d", state.PINFailedAttempts, tt.wantCount)
+			}
+
+			// The correct PIN from the same stale copy must not get past a lock set meanwhile
+			stale := *user
+			err := auth.checkPIN(ctx, &stale, "1234")
+			if tt.wantLocked && !errors.Is(err, entities.ErrPINLocked) {
+				t.Errorf("correct PIN while locked: got %v, want %v", err, entities.ErrPINLocked)
+			}
+			if !tt.wantLocked && err != nil {
+				t.Errorf("correct PIN: got %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestTenantUnlockLiftsLockoutOfLoginsNamingNoTenant(t *testing.T) {
+	svc, db := newTestAuthService(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	users := NewUserService(
+		repository.NewUserRepository(db, logger),
+		repository.NewRoleRepository(db, logger),
+		repository.NewSessionRepository(db, logger),
+		svc,
+		logger,
+	)
+	ctx, tenantID := newTestTenant(t, db)
+	user := createTestUser(t, db, tenantID, fmt.Sprintf("locked-%d", tenantID), "secret123")
+
+	// The same username in another tenant keeps its own count
+	_, otherTenantID := newTestTenant(t, db)
+	createTestUser(t, db, otherTenantID, user.Username, "other-secret")
+
+	// Logins naming no tenant try the password against both users and lock both out
+	client := interfaces.ClientInfo{UserAgent: "integration-test", IPAddress: "192.0.2.12"}
+	for i := 0; i < testLoginPolicy.LockoutAfter; i++ {
+		if _, _, err := svc.Login(context.Background(), user.Username, "wrong", client); err == nil {
+			t.Fatal("a wrong password was accepted")
+		}
+	}
+	if _, _, err := svc.Login(context.Background(), user.Username, "secret123", client); !errors.Is(err, entities.ErrAccountLocked) {
+		t.Fatalf("login after repeated failures: got %v, want %v", err, entities.ErrAccountLocked)
+	}
+
+	manager := context.WithValue(ctx, "permissions", entities.NewPermissionSet(entities.BuiltInRoles[entities.RoleOwner]))
+	if err := users.UnlockUser(manager, user.ID); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	if _, _, err := svc.Login(context.Background(), user.Username, "secret123", client); err != nil {
+		t.Errorf("login naming no tenant after unlock: %v", err)
+	}
+	otherCtx := context.WithValue(context.Background(), "tenant_id", otherTenantID)
+	if _, _, err := svc.Login(otherCtx, user.Username, "other-secret", client); !errors.Is(err, entities.ErrAccountLocked) {
+		t.Errorf("login of the other tenant's user: got %v, want %v", err, entities.ErrAccountLocked)
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+)
+
+type categoryService struct {
+	categoryRepo interfaces.CategoryRepository
+	logger       *slog.Logger
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo interfaces.CategoryRepository, logger *slog.Logger) interfaces.CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		logger:       logger,
+	}
+}
+
+// CreateCategory creates a new category for the current tenant
+func (s *categoryService) CreateCategory(ctx context.Context, category *entities.Category) error {
+	s.logger.InfoContext(ctx, "creating category", "name", category.Name)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+	category.TenantID = &tenantID
+
+	// The parent must belong to the same tenant
+	if category.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *category.ParentID); err != nil {
+			return fmt.Errorf("failed to get parent category: %w", err)
+		}
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return nil
+}
+
+// GetCategory retrieves a category by ID
+func (s *categoryService) GetCategory(ctx context.Context, id uint) (*entities.Category, error) {
+	s.logger.InfoContext(ctx, "getting category", "id", id)
+
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// ListCategories retrieves all categories of the current tenant
+func (s *categoryService) ListCategories(ctx context.Context) ([]entities.Category, error) {
+	s.logger.InfoContext(ctx, "listing categories")
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// UpdateCategory updates a category with the provided fields.
+// A nil parent_id moves the category to the top level.
+func (s *categoryService) UpdateCategory(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Category, error) {
+	s.logger.InfoContext(ctx, "updating category", "id", id)
+
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	for field, value := range updates {
+		switch field {
+		case "name":
+			category.Name = value.(string)
+		case "parent_id":
+			category.ParentID = value.(*uint)
+		}
+	}
+
+	if category.ParentID != nil {
+		// The parent must belong to the same tenant
+		if _, err := s.categoryRepo.GetByID(ctx, *category.ParentID); err != nil {
+			return nil, fmt.Errorf("failed to get parent category: %w", err)
+		}
+
+		categories, err := s.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list categories: %w", err)
+		}
+
+		// Reparenting under itself or one of its descendants would create a cycle
+		for _, descendant := range descendantCategoryIDs(categories, id) {
+			if descendant == *category.ParentID {
+				return nil, entities.ErrCategoryCycle
+			}
+		}
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return category, nil
+}
+
+// DeleteCategory deletes a category that has no subcategories or products
+func (s *categoryService) DeleteCategory(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting category", "id", id)
+
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list categories: %w", err)
+	}
+	if len(descendantCategoryIDs(categories, id)) > 1 {
+		return entities.ErrCategoryInUse
+	}
+
+	products, err := s.categoryRepo.CountProducts(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to count category products: %w", err)
+	}
+	if products > 0 {
+		return entities.ErrCategoryInUse
+	}
+
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return nil
+}
+
+// descendantCategoryIDs returns rootID followed by the IDs of all categories nested below it
+func descendantCategoryIDs(categories []entities.Category, rootID uint) []uint {
+	children := make(map[uint][]uint)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []uint{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	return ids
+}
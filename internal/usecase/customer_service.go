@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type customerService struct {
+	customerRepo    interfaces.CustomerRepository
+	transactionRepo interfaces.TransactionRepository
+	logger          *slog.Logger
+}
+
+// NewCustomerService creates a new customer service
+func NewCustomerService(customerRepo interfaces.CustomerRepository, transactionRepo interfaces.TransactionRepository, logger *slog.Logger) interfaces.CustomerService {
+	return &customerService{
+		customerRepo:    customerRepo,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// CreateCustomer creates a new customer for the current tenant
+func (s *customerService) CreateCustomer(ctx context.Context, customer *entities.Customer) error {
+	s.logger.InfoContext(ctx, "creating customer", "name", customer.Name)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+	customer.TenantID = &tenantID
+
+	customer.Phone = normalizePhone(customer.Phone)
+	if err := s.ensurePhoneAvailable(ctx, 0, customer.Phone); err != nil {
+		return err
+	}
+
+	if err := s.customerRepo.Create(ctx, customer); err != nil {
+		return fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return nil
+}
+
+// GetCustomer retrieves a customer by ID
+func (s *customerService) GetCustomer(ctx context.Context, id uint) (*entities.Customer, error) {
+	s.logger.InfoContext(ctx, "getting customer", "id", id)
+
+	customer, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// GetCustomerByPhone retrieves a customer by phone number
+func (s *customerService) GetCustomerByPhone(ctx context.Context, phone string) (*entities.Customer, error) {
+	s.logger.InfoContext(ctx, "looking up customer by phone")
+
+	customer, err := s.customerRepo.GetByPhone(ctx, normalizePhone(phone))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// ListCustomers retrieves customers with pagination, optionally filtered by name or phone
+func (s *customerService) ListCustomers(ctx context.Context, search string, page, limit int) ([]entities.Customer, int64, error) {
+	s.logger.InfoContext(ctx, "listing customers", "search", search, "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	customers, total, err := s.customerRepo.List(ctx, strings.TrimSpace(search), page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+	}
+
+	return customers, total, nil
+}
+
+// UpdateCustomer updates a customer with the provided fields
+func (s *customerService) UpdateCustomer(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Customer, error) {
+	s.logger.InfoContext(ctx, "updating customer", "id", id)
+
+	customer, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	for field, value := range updates {
+		switch field {
+		case "name":
+			customer.Name = value.(string)
+		case "phone":
+			customer.Phone = normalizePhone(value.(string))
+			if err := s.ensurePhoneAvailable(ctx, id, customer.Phone); err != nil {
+				return nil, err
+			}
+		case "email":
+			customer.Email = value.(string)
+		case "notes":
+			customer.Notes = value.(string)
+		}
+	}
+
+	if err := s.customerRepo.Update(ctx, customer); err != nil {
+		return nil, fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// DeleteCustomer deletes a customer; their sales stay in history without the link
+func (s *customerService) DeleteCustomer(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting customer", "id", id)
+
+	if err := authorize(ctx, entities.PermCustomersManage); err != nil {
+		return err
+	}
+
+	if _, err := s.customerRepo.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	if err := s.customerRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+
+	return nil
+}
+
+// ListCustomerTransactions retrieves a customer's purchase history with pagination
+func (s *customerService) ListCustomerTransactions(ctx context.Context, id uint, page, limit int) ([]entities.Transaction, int64, error) {
+	s.logger.InfoContext(ctx, "listing customer transactions", "id", id, "page", page, "limit", limit)
+
+	if _, err := s.customerRepo.GetByID(ctx, id); err != nil {
+		return nil, 0, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	transactions, total, err := s.transactionRepo.ListByCustomer(ctx, id, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list customer transactions: %w", err)
+	}
+
+	return transactions, total, nil
+}
+
+// ensurePhoneAvailable rejects a phone number already registered to another customer.
+// id is the customer being updated, or zero.
+func (s *customerService) ensurePhoneAvailable(ctx context.Context, id uint, phone string) error {
+	existing, err := s.customerRepo.GetByPhone(ctx, phone)
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("phone %s: %w", phone, entities.ErrCustomerPhoneTaken)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check customer phone: %w", err)
+	}
+	return nil
+}
+
+// normalizePhone strips the spaces and punctuation people type in phone numbers,
+// keeping a leading + for international numbers
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
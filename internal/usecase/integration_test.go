@@ -0,0 +1,66 @@
+//go:build integration
+
+package usecase
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/database"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// Integration tests run against a real MySQL database, since the guarantees they check
+// rely on its row locks. Point TEST_DATABASE_DSN at a throwaway schema, e.g.
+//
+//	TEST_DATABASE_DSN='root:secret@tcp(localhost:3306)/rh_pos_test?parseTime=true' go test -tags integration ./internal/usecase/
+//
+// The schema is auto-migrated and every test writes to a tenant of its own.
+
+// openTestDB connects to TEST_DATABASE_DSN and migrates it, skipping the test when it is unset
+func openTestDB(t *testing.T) (*gorm.DB, *slog.Logger) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := database.NewConnection(dsn, logger)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := db.AutoMigrate(&entities.Tenant{}); err != nil {
+		t.Fatalf("failed to migrate tenants: %v", err)
+	}
+	if err := database.AutoMigrate(db, logger); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db, logger
+}
+
+// newTestTenant creates a tenant without tax, service charge or rounding and returns a context scoped to it
+func newTestTenant(t *testing.T, db *gorm.DB) (context.Context, uint) {
+	t.Helper()
+
+	tenant := entities.Tenant{
+		Name:         t.Name(),
+		RoundingMode: money.RoundNearest,
+	}
+	if err := db.Create(&tenant).Error; err != nil {
+		t.Fatalf("failed to create tenant: %v", err)
+	}
+	return context.WithValue(context.Background(), "tenant_id", tenant.ID), tenant.ID
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loyaltyTierWindow is how far back earned points count towards a tier
+const loyaltyTierWindow = 365 * 24 * time.Hour
+
+// loadLoyaltyProgram returns the tenant's loyalty program with its tiers, or nil when it has none
+func loadLoyaltyProgram(tx *gorm.DB, tenantID uint) (*entities.LoyaltyProgram, error) {
+	var program entities.LoyaltyProgram
+	err := tx.Preload("Tiers").Where("tenant_id = ?", tenantID).First(&program).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+	return &program, nil
+}
+
+// lockLoyaltyLedger loads a customer's points ledger in the order it was written, locking it
+// until tx ends so concurrent sales cannot spend the same points
+func lockLoyaltyLedger(tx *gorm.DB, tenantID uint, phone string) ([]entities.LoyaltyLedgerEntry, error) {
+	var entries []entities.LoyaltyLedgerEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND phone = ?", tenantID, phone).
+		Order("id").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get loyalty ledger: %w", err)
+	}
+	return entries, nil
+}
+
+// loyaltyTier returns the highest tier reached with the points earned in the year before now,
+// and those points. It returns a nil tier when none is reached.
+func loyaltyTier(program *entities.LoyaltyProgram, entries []entities.LoyaltyLedgerEntry, now time.Time) (*entities.LoyaltyTier, int) {
+	since := now.Add(-loyaltyTierWindow)
+	earned := 0
+	for _, e := range entries {
+		if e.CreatedAt.Before(since) {
+			continue
+		}
+		if e.Type == entities.LoyaltyEntryEarn || e.Type == entities.LoyaltyEntryReverseEarn {
+			earned += e.Points
+		}
+	}
+
+	var tier *entities.LoyaltyTier
+	for i := range program.Tiers {
+		t := &program.Tiers[i]
+		if earned >= t.MinPoints && (tier == nil || t.MinPoints > tier.MinPoints) {
+			tier = t
+		}
+	}
+	return tier, earned
+}
+
+// loyaltyExpiry returns when points credited at now expire, or nil when they do not
+func loyaltyExpiry(program *entities.LoyaltyProgram, now time.Time) *time.Time {
+	if program == nil || program.ExpiryDays <= 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, program.ExpiryDays)
+	return &expiresAt
+}
+
+// applyLoyalty works out the points a sale redeems through points tenders and the points it
+// earns on the rest of its total. It returns the program when the sale moves points.
+func applyLoyalty(tx *gorm.DB, tenantID uint, transaction *entities.Transaction, now time.Time) (*entities.LoyaltyProgram, error) {
+	var paidWithPoints money.Money
+	for _, p := range transaction.Payments {
+		if p.Method == entities.PaymentMethodPoints {
+			paidWithPoints += p.Amount - p.ChangeAmount
+		}
+	}
+
+	program, err := loadLoyaltyProgram(tx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if program != nil && !program.Enabled {
+		program = nil
+	}
+
+	if paidWithPoints > 0 {
+		switch {
+		case program == nil:
+			return nil, fmt.Errorf("loyalty program is not enabled: %w", entities.ErrInvalidPointsPayment)
+		case transaction.CustomerPhone == "":
+			return nil, fmt.Errorf("customer phone is required to pay with points: %w", entities.ErrInvalidPointsPayment)
+		case paidWithPoints%program.RedeemValue != 0:
+			return nil, fmt.Errorf("points payments must be a multiple of %s: %w", program.RedeemValue, entities.ErrInvalidPointsPayment)
+		}
+	}
+	if program == nil || transaction.CustomerPhone == "" {
+		return nil, nil
+	}
+
+	entries, err := lockLoyaltyLedger(tx, tenantID, transaction.CustomerPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.PointsRedeemed = int(paidWithPoints / program.RedeemValue)
+	if balance := entities.LoyaltyBalance(entries, now); transaction.PointsRedeemed > balance {
+		return nil, fmt.Errorf("redeeming %d points, balance %d: %w", transaction.PointsRedeemed, balance, entities.ErrInsufficientPoints)
+	}
+
+	// The part paid with points earns nothing
+	multiplier := 1.0
+	if tier, _ := loyaltyTier(program, entries, now); tier != nil {
+		multiplier = tier.EarnMultiplier
+	}
+	units := int64(transaction.TotalPrice-paidWithPoints) / int64(program.EarnSpend)
+	transaction.PointsEarned = int(math.Floor(float64(units) * multiplier))
+
+	return program, nil
+}
+
+// recordLoyalty appends a sale's redeemed and earned points to the customer's ledger
+func recordLoyalty(tx *gorm.DB, program *entities.LoyaltyProgram, transaction *entities.Transaction, now time.Time) error {
+	entries := make([]entities.LoyaltyLedgerEntry, 0, 2)
+	if transaction.PointsRedeemed > 0 {
+		entries = append(entries, newLoyaltyEntry(transaction, entities.LoyaltyEntryRedeem, -transaction.PointsRedeemed, nil))
+	}
+	if transaction.PointsEarned > 0 {
+		entries = append(entries, newLoyaltyEntry(transaction, entities.LoyaltyEntryEarn, transaction.PointsEarned, loyaltyExpiry(program, now)))
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to record loyalty points: %w", err)
+	}
+	return nil
+}
+
+// reverseLoyalty takes back the points a reversed sale earned, in proportion to the amount
+// returned and fully once nothing is left, and gives back the points a voided sale redeemed
+func reverseLoyalty(tx *gorm.DB, transaction *entities.Transaction, refundAmount money.Money, status string) error {
+	if transaction.PointsEarned == 0 && transaction.PointsRedeemed == 0 {
+		return nil
+	}
+
+	var previous []entities.LoyaltyLedgerEntry
+	if err := tx.Where("transaction_id = ?", transaction.ID).Find(&previous).Error; err != nil {
+		return fmt.Errorf("failed to get loyalty ledger: %w", err)
+	}
+	reversedEarn, reversedRedeem := 0, 0
+	for _, e := range previous {
+		switch e.Type {
+		case entities.LoyaltyEntryReverseEarn:
+			reversedEarn -= e.Points
+		case entities.LoyaltyEntryReverseRedeem:
+			reversedRedeem += e.Points
+		}
+	}
+
+	finished := status == entities.TransactionStatusRefunded || status == entities.TransactionStatusVoided
+	takeBack := transaction.PointsEarned - reversedEarn
+	if !finished && transaction.TotalPrice > 0 {
+		share := int(int64(transaction.PointsEarned) * int64(refundAmount) / int64(transaction.TotalPrice))
+		takeBack = min(share, takeBack)
+	}
+	giveBack := 0
+	if status == entities.TransactionStatusVoided {
+		giveBack = transaction.PointsRedeemed - reversedRedeem
+	}
+
+	entries := make([]entities.LoyaltyLedgerEntry, 0, 2)
+	if takeBack > 0 {
+		entries = append(entries, newLoyaltyEntry(transaction, entities.LoyaltyEntryReverseEarn, -takeBack, nil))
+	}
+	if giveBack > 0 {
+		program, err := loadLoyaltyProgram(tx, *transaction.TenantID)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, newLoyaltyEntry(transaction, entities.LoyaltyEntryReverseRedeem, giveBack, loyaltyExpiry(program, time.Now())))
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to reverse loyalty points: %w", err)
+	}
+	return nil
+}
+
+// newLoyaltyEntry builds a ledger entry for a sale's customer
+func newLoyaltyEntry(transaction *entities.Transaction, entryType string, points int, expiresAt *time.Time) entities.LoyaltyLedgerEntry {
+	return entities.LoyaltyLedgerEntry{
+		Phone:         transaction.CustomerPhone,
+		CustomerID:    transaction.CustomerID,
+		TransactionID: &transaction.ID,
+		Type:          entryType,
+		Points:        points,
+		ExpiresAt:     expiresAt,
+		TenantID:      transaction.TenantID,
+	}
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type loyaltyService struct {
+	loyaltyRepo interfaces.LoyaltyRepository
+	logger      *slog.Logger
+}
+
+// NewLoyaltyService creates a new loyalty service
+func NewLoyaltyService(loyaltyRepo interfaces.LoyaltyRepository, logger *slog.Logger) interfaces.LoyaltyService {
+	return &loyaltyService{
+		loyaltyRepo: loyaltyRepo,
+		logger:      logger,
+	}
+}
+
+// GetProgram retrieves the tenant's loyalty program
+func (s *loyaltyService) GetProgram(ctx context.Context) (*entities.LoyaltyProgram, error) {
+	s.logger.InfoContext(ctx, "getting loyalty program")
+
+	program, err := s.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	return program, nil
+}
+
+// UpdateProgram creates or replaces the tenant's loyalty program settings and tiers.
+// Points already in the ledger keep their expiry.
+func (s *loyaltyService) UpdateProgram(ctx context.Context, program *entities.LoyaltyProgram) (*entities.LoyaltyProgram, error) {
+	s.logger.InfoContext(ctx, "updating loyalty program", "enabled", program.Enabled)
+
+	if err := authorize(ctx, entities.PermLoyaltyManage); err != nil {
+		return nil, err
+	}
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	if err := validateLoyaltyProgram(program); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.loyaltyRepo.GetProgram(ctx)
+	switch {
+	case err == nil:
+		program.ID = existing.ID
+		program.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+	program.TenantID = &tenantID
+
+	if err := s.loyaltyRepo.SaveProgram(ctx, program); err != nil {
+		return nil, fmt.Errorf("failed to save loyalty program: %w", err)
+	}
+
+	return s.loyaltyRepo.GetProgram(ctx)
+}
+
+// GetAccount summarises the points of the customer with the phone number
+func (s *loyaltyService) GetAccount(ctx context.Context, phone string) (*interfaces.LoyaltyAccount, error) {
+	s.logger.InfoContext(ctx, "getting loyalty account")
+
+	program, err := s.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	phone = normalizePhone(phone)
+	entries, err := s.loyaltyRepo.AllEntries(ctx, phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty ledger: %w", err)
+	}
+
+	now := time.Now()
+	account := &interfaces.LoyaltyAccount{
+		Phone:   phone,
+		Balance: entities.LoyaltyBalance(entries, now),
+	}
+	account.BalanceValue = program.RedeemValue.Mul(max(account.Balance, 0))
+
+	var tier *entities.LoyaltyTier
+	tier, account.TierPoints = loyaltyTier(program, entries, now)
+	if tier != nil {
+		account.Tier = tier.Name
+	}
+
+	return account, nil
+}
+
+// ListEntries retrieves the points ledger of the customer with the phone number
+func (s *loyaltyService) ListEntries(ctx context.Context, phone string, page, limit int) ([]entities.LoyaltyLedgerEntry, int64, error) {
+	s.logger.InfoContext(ctx, "listing loyalty ledger", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	entries, total, err := s.loyaltyRepo.ListEntries(ctx, normalizePhone(phone), page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list loyalty ledger: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// validateLoyaltyProgram checks the earn and redeem rates, expiry and tiers
+func validateLoyaltyProgram(program *entities.LoyaltyProgram) error {
+	if program.EarnSpend <= 0 || program.RedeemValue <= 0 {
+		return fmt.Errorf("earn_spend and redeem_value must be greater than zero: %w", entities.ErrInvalidLoyaltyProgram)
+	}
+	if program.ExpiryDays < 0 {
+		return fmt.Errorf("expiry_days cannot be negative: %w", entities.ErrInvalidLoyaltyProgram)
+	}
+
+	names := make(map[string]bool, len(program.Tiers))
+	thresholds := make(map[int]bool, len(program.Tiers))
+	for _, tier := range program.Tiers {
+		if tier.Name == "" || names[tier.Name] {
+			return fmt.Errorf("tier names must be set and unique: %w", entities.ErrInvalidLoyaltyProgram)
+		}
+		if tier.MinPoints < 0 || thresholds[tier.MinPoints] {
+			return fmt.Errorf("tier min_points must be unique and not negative: %w", entities.ErrInvalidLoyaltyProgram)
+		}
+		if tier.EarnMultiplier <= 0 {
+			return fmt.Errorf("tier earn_multiplier must be greater than zero: %w", entities.ErrInvalidLoyaltyProgram)
+		}
+		names[tier.Name] = true
+		thresholds[tier.MinPoints] = true
+	}
+
+	return nil
+}
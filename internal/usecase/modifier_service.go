@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+type modifierService struct {
+	modifierGroupRepo interfaces.ModifierGroupRepository
+	productRepo       interfaces.ProductRepository
+	logger            *slog.Logger
+}
+
+// NewModifierService creates a new modifier service
+func NewModifierService(modifierGroupRepo interfaces.ModifierGroupRepository, productRepo interfaces.ProductRepository, logger *slog.Logger) interfaces.ModifierService {
+	return &modifierService{
+		modifierGroupRepo: modifierGroupRepo,
+		productRepo:       productRepo,
+		logger:            logger,
+	}
+}
+
+// CreateModifierGroup creates a new modifier group with its options for the current tenant
+func (s *modifierService) CreateModifierGroup(ctx context.Context, group *entities.ModifierGroup) error {
+	s.logger.InfoContext(ctx, "creating modifier group", "name", group.Name)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+	group.TenantID = &tenantID
+
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	for i := range group.Options {
+		group.Options[i].ID = 0
+		group.Options[i].Position = i
+	}
+
+	if err := s.modifierGroupRepo.Create(ctx, group); err != nil {
+		return fmt.Errorf("failed to create modifier group: %w", err)
+	}
+
+	return nil
+}
+
+// GetModifierGroup retrieves a modifier group by ID
+func (s *modifierService) GetModifierGroup(ctx context.Context, id uint) (*entities.ModifierGroup, error) {
+	s.logger.InfoContext(ctx, "getting modifier group", "id", id)
+
+	group, err := s.modifierGroupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifier group: %w", err)
+	}
+
+	return group, nil
+}
+
+// ListModifierGroups retrieves all modifier groups of the current tenant
+func (s *modifierService) ListModifierGroups(ctx context.Context) ([]entities.ModifierGroup, error) {
+	s.logger.InfoContext(ctx, "listing modifier groups")
+
+	groups, err := s.modifierGroupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modifier groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// UpdateModifierGroup replaces a modifier group's rules and options.
+// Options sent with their ID keep it, so past sales and reports stay linked to them.
+func (s *modifierService) UpdateModifierGroup(ctx context.Context, id uint, group *entities.ModifierGroup) (*entities.ModifierGroup, error) {
+	s.logger.InfoContext(ctx, "updating modifier group", "id", id)
+
+	existing, err := s.modifierGroupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifier group: %w", err)
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint]bool, len(existing.Options))
+	for _, option := range existing.Options {
+		known[option.ID] = true
+	}
+	for i := range group.Options {
+		if group.Options[i].ID != 0 && !known[group.Options[i].ID] {
+			return nil, fmt.Errorf("option %d: %w", group.Options[i].ID, gorm.ErrRecordNotFound)
+		}
+		group.Options[i].Position = i
+	}
+
+	existing.Name = group.Name
+	existing.MinSelect = group.MinSelect
+	existing.MaxSelect = group.MaxSelect
+	existing.Options = group.Options
+
+	if err := s.modifierGroupRepo.Update(ctx, existing); err != nil {
+		return nil, fmt.Errorf("failed to update modifier group: %w", err)
+	}
+
+	return s.modifierGroupRepo.GetByID(ctx, id)
+}
+
+// DeleteModifierGroup deletes a modifier group and removes it from every product
+func (s *modifierService) DeleteModifierGroup(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting modifier group", "id", id)
+
+	if err := s.modifierGroupRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete modifier group: %w", err)
+	}
+
+	return nil
+}
+
+// SetProductModifierGroups replaces the modifier groups offered on a product
+func (s *modifierService) SetProductModifierGroups(ctx context.Context, productID uint, groupIDs []uint) (*entities.Product, error) {
+	s.logger.InfoContext(ctx, "setting product modifier groups", "id", productID, "groups", len(groupIDs))
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	groups := []entities.ModifierGroup{}
+	if len(groupIDs) > 0 {
+		groups, err = s.modifierGroupRepo.GetByIDs(ctx, groupIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get modifier groups: %w", err)
+		}
+
+		found := make(map[uint]bool, len(groups))
+		for _, g := range groups {
+			found[g.ID] = true
+		}
+		for _, id := range groupIDs {
+			if !found[id] {
+				return nil, fmt.Errorf("modifier group %d: %w", id, gorm.ErrRecordNotFound)
+			}
+		}
+	}
+
+	if err := s.productRepo.ReplaceModifierGroups(ctx, product, groups); err != nil {
+		return nil, fmt.Errorf("failed to set product modifier groups: %w", err)
+	}
+
+	return s.productRepo.GetByID(ctx, productID)
+}
+
+// validateModifierGroup checks the selection rules against the group's options
+func validateModifierGroup(group *entities.ModifierGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("modifier group name is required: %w", entities.ErrInvalidModifierGroup)
+	}
+	if len(group.Options) == 0 {
+		return fmt.Errorf("modifier group needs at least one option: %w", entities.ErrInvalidModifierGroup)
+	}
+	if group.MinSelect < 0 || group.MaxSelect < 0 {
+		return fmt.Errorf("selection limits cannot be negative: %w", entities.ErrInvalidModifierGroup)
+	}
+	if group.MaxSelect > 0 && group.MaxSelect < group.MinSelect {
+		return fmt.Errorf("max_select %d is below min_select %d: %w", group.MaxSelect, group.MinSelect, entities.ErrInvalidModifierGroup)
+	}
+	if group.MinSelect > len(group.Options) {
+		return fmt.Errorf("min_select %d exceeds the %d options: %w", group.MinSelect, len(group.Options), entities.ErrInvalidModifierGroup)
+	}
+
+	names := make(map[string]bool, len(group.Options))
+	for _, option := range group.Options {
+		if option.Name == "" {
+			return fmt.Errorf("modifier option name is required: %w", entities.ErrInvalidModifierGroup)
+		}
+		if option.Price < 0 {
+			return fmt.Errorf("option %s: price cannot be negative: %w", option.Name, entities.ErrInvalidModifierGroup)
+		}
+		if names[option.Name] {
+			return fmt.Errorf("option %s is listed twice: %w", option.Name, entities.ErrInvalidModifierGroup)
+		}
+		names[option.Name] = true
+	}
+
+	return nil
+}
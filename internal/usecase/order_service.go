@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OpenOrder starts an unpaid order, such as a table's tab, with optional first lines.
+// Nothing is deducted from stock until the order is settled.
+func (s *transactionService) OpenOrder(ctx context.Context, req interfaces.OpenOrderRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "opening order", "user", req.User, "label", req.Label)
+
+	items, err := s.resolveBarcodes(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderID uint
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tenantID, ok := ctx.Value("tenant_id").(uint)
+		if !ok {
+			return fmt.Errorf("tenant_id not found in context")
+		}
+
+		order := &entities.Transaction{
+			User:     req.User,
+			Label:    req.Label,
+			Notes:    req.Notes,
+			Status:   entities.TransactionStatusOpen,
+			TenantID: &tenantID,
+			Items:    make([]entities.TransactionItem, 0, len(items)),
+		}
+		for _, item := range items {
+			line, err := newOrderItem(tx, tenantID, item)
+			if err != nil {
+				return err
+			}
+			order.Items = append(order.Items, line)
+			order.Subtotal += line.Price.Mul(line.Quantity)
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		orderID = order.ID
+		return nil
+	})
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "open order failed", "error", err)
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(ctx, orderID)
+}
+
+// ListOrders retrieves unsettled orders with pagination. An empty status lists open and held orders.
+func (s *transactionService) ListOrders(ctx context.Context, status string, page, limit int) ([]entities.Transaction, int64, error) {
+	s.logger.InfoContext(ctx, "listing orders", "status", status, "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	statuses := []string{entities.TransactionStatusOpen, entities.TransactionStatusHeld}
+	if status != "" {
+		if !slices.Contains(entities.OrderStatuses, status) {
+			return nil, 0, fmt.Errorf("%s is not an order status: %w", status, entities.ErrInvalidTransactionState)
+		}
+		statuses = []string{status}
+	}
+
+	orders, total, err := s.transactionRepo.ListOrders(ctx, statuses, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// UpdateOrder assigns an open or held order to a table or label
+func (s *transactionService) UpdateOrder(ctx context.Context, id uint, req interfaces.UpdateOrderRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "updating order", "id", id, "label", req.Label)
+
+	return s.changeOrder(ctx, id, func(tx *gorm.DB, order *entities.Transaction) error {
+		if err := tx.Model(order).Updates(map[string]interface{}{
+			"label": req.Label,
+			"notes": req.Notes,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		return nil
+	}, entities.TransactionStatusOpen, entities.TransactionStatusHeld)
+}
+
+// AddOrderItem adds a line to an open order at the current product and modifier prices
+func (s *transactionService) AddOrderItem(ctx context.Context, id uint, item interfaces.TransactionItemRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "adding order item", "id", id, "product_id", item.ProductID, "quantity", item.Quantity)
+
+	items, err := s.resolveBarcodes(ctx, []interfaces.TransactionItemRequest{item})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeOrder(ctx, id, func(tx *gorm.DB, order *entities.Transaction) error {
+		line, err := newOrderItem(tx, *order.TenantID, items[0])
+		if err != nil {
+			return err
+		}
+		line.TransactionID = order.ID
+		if err := tx.Create(&line).Error; err != nil {
+			return fmt.Errorf("failed to add order item: %w", err)
+		}
+
+		order.Items = append(order.Items, line)
+		return updateOrderSubtotal(tx, order)
+	}, entities.TransactionStatusOpen)
+}
+
+// RemoveOrderItem removes a line from an open order
+func (s *transactionService) RemoveOrderItem(ctx context.Context, id, itemID uint) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "removing order item", "id", id, "item_id", itemID)
+
+	return s.changeOrder(ctx, id, func(tx *gorm.DB, order *entities.Transaction) error {
+		index := slices.IndexFunc(order.Items, func(item entities.TransactionItem) bool {
+			return item.ID == itemID
+		})
+		if index < 0 {
+			return fmt.Errorf("item %d in order %d: %w", itemID, order.ID, entities.ErrTransactionItemNotFound)
+		}
+
+		if err := deleteOrderItems(tx, []uint{itemID}); err != nil {
+			return err
+		}
+
+		order.Items = slices.Delete(order.Items, index, index+1)
+		return updateOrderSubtotal(tx, order)
+	}, entities.TransactionStatusOpen)
+}
+
+// HoldOrder parks an open order so it cannot change until it is resumed
+func (s *transactionService) HoldOrder(ctx context.Context, id uint) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "holding order", "id", id)
+
+	return s.changeOrder(ctx, id, setOrderStatus(entities.TransactionStatusHeld), entities.TransactionStatusOpen)
+}
+
+// ResumeOrder reopens a held order
+func (s *transactionService) ResumeOrder(ctx context.Context, id uint) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "resuming order", "id", id)
+
+	return s.changeOrder(ctx, id, setOrderStatus(entities.TransactionStatusOpen), entities.TransactionStatusHeld)
+}
+
+// CancelOrder discards an open or held order. Its lines are kept for reference.
+func (s *transactionService) CancelOrder(ctx context.Context, id uint) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "cancelling order", "id", id)
+
+	return s.changeOrder(ctx, id, setOrderStatus(entities.TransactionStatusCancelled), entities.TransactionStatusOpen, entities.TransactionStatusHeld)
+}
+
+// SettleOrder takes payment for an open order. Its lines are repriced, discounted, taxed and
+// deducted from stock exactly as a new transaction would be, and the order becomes a completed sale.
+func (s *transactionService) SettleOrder(ctx context.Context, id uint, req interfaces.SettleOrderRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "settling order", "id", id)
+
+	var settled *entities.Transaction
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(ctx, tx, id, entities.TransactionStatusOpen)
+		if err != nil {
+			return err
+		}
+		if len(order.Items) == 0 {
+			return fmt.Errorf("order %d has no items: %w", order.ID, entities.ErrInvalidTransactionState)
+		}
+
+		checkoutReq := interfaces.CreateTransactionRequest{
+			User:          order.User,
+			PaymentMethod: req.PaymentMethod,
+			Discount:      req.Discount,
+			TotalPrice:    req.TotalPrice,
+			Notes:         order.Notes,
+			Payments:      req.Payments,
+			VoucherCode:   req.VoucherCode,
+			CustomerID:    req.CustomerID,
+			CustomerPhone: req.CustomerPhone,
+			Items:         make([]interfaces.TransactionItemRequest, len(order.Items)),
+		}
+		lineIDs := make([]uint, len(order.Items))
+		for i, line := range order.Items {
+			lineIDs[i] = line.ID
+			checkoutReq.Items[i] = interfaces.TransactionItemRequest{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+				Modifiers: make([]uint, len(line.Modifiers)),
+			}
+			for j, m := range line.Modifiers {
+				checkoutReq.Items[i].Modifiers[j] = m.ModifierOptionID
+			}
+		}
+
+		// The order's draft lines are replaced by the priced lines of the sale
+		if err := deleteOrderItems(tx, lineIDs); err != nil {
+			return err
+		}
+
+		transaction := &entities.Transaction{
+			ID:            order.ID,
+			User:          order.User,
+			Label:         order.Label,
+			PaymentMethod: req.PaymentMethod,
+			Discount:      req.Discount,
+			Notes:         order.Notes,
+			CustomerPhone: normalizePhone(req.CustomerPhone),
+			TenantID:      order.TenantID,
+			CreatedAt:     order.CreatedAt,
+		}
+		if err := s.checkout(ctx, tx, *order.TenantID, transaction, checkoutReq); err != nil {
+			return err
+		}
+
+		settled = transaction
+		return nil
+	})
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "settle order failed", "error", err, "id", id)
+		return nil, err
+	}
+
+	s.checkStockAlerts(ctx, settled)
+
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.VoucherNotApplied = settled.VoucherNotApplied
+	return transaction, nil
+}
+
+// changeOrder locks an order in one of statuses and applies change to it within a database transaction
+func (s *transactionService) changeOrder(ctx context.Context, id uint, change func(tx *gorm.DB, order *entities.Transaction) error, statuses ...string) (*entities.Transaction, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(ctx, tx, id, statuses...)
+		if err != nil {
+			return err
+		}
+		return change(tx, order)
+	})
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "order change failed", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(ctx, id)
+}
+
+// lockOrder loads an order with its lines, locking the row until tx ends, and checks it is in one of statuses
+func (s *transactionService) lockOrder(ctx context.Context, tx *gorm.DB, id uint, statuses ...string) (*entities.Transaction, error) {
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	var order entities.Transaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items.Modifiers").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("order not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !slices.Contains(statuses, order.Status) {
+		return nil, fmt.Errorf("cannot change %s order: %w", order.Status, entities.ErrInvalidTransactionState)
+	}
+
+	return &order, nil
+}
+
+// setOrderStatus returns an order change that moves the order to status
+func setOrderStatus(status string) func(tx *gorm.DB, order *entities.Transaction) error {
+	return func(tx *gorm.DB, order *entities.Transaction) error {
+		if err := tx.Model(order).Update("status", status).Error; err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		return nil
+	}
+}
+
+// newOrderItem prices a line of an open order at the current product and modifier prices.
+// Stock is only checked when the order is settled.
+func newOrderItem(tx *gorm.DB, tenantID uint, item interfaces.TransactionItemRequest) (entities.TransactionItem, error) {
+	if item.Quantity < 1 {
+		return entities.TransactionItem{}, fmt.Errorf("item quantity must be at least 1")
+	}
+
+	var product entities.Product
+	if err := tx.Where("id = ? AND tenant_id = ?", item.ProductID, tenantID).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.TransactionItem{}, fmt.Errorf("product %d: %w", item.ProductID, err)
+		}
+		return entities.TransactionItem{}, fmt.Errorf("failed to get product: %w", err)
+	}
+	if err := rejectVariantParents(tx, []uint{product.ID}); err != nil {
+		return entities.TransactionItem{}, err
+	}
+
+	groups, err := loadModifierGroups(tx, tenantID, map[uint]*entities.Product{product.ID: &product})
+	if err != nil {
+		return entities.TransactionItem{}, err
+	}
+	modifiers, err := selectModifiers(&product, groups[product.ID], item.Modifiers)
+	if err != nil {
+		return entities.TransactionItem{}, err
+	}
+
+	price := product.HargaJual
+	for _, m := range modifiers {
+		price += m.Price
+	}
+
+	return entities.TransactionItem{
+		ProductID: product.ID,
+		Quantity:  item.Quantity,
+		Price:     price,
+		Cost:      product.HargaModal,
+		TaxExempt: product.TaxExempt,
+		Modifiers: modifiers,
+	}, nil
+}
+
+// updateOrderSubtotal stores the running subtotal of an order's lines
+func updateOrderSubtotal(tx *gorm.DB, order *entities.Transaction) error {
+	order.Subtotal = 0
+	for _, item := range order.Items {
+		order.Subtotal += item.Price.Mul(item.Quantity)
+	}
+	if err := tx.Model(order).Update("subtotal", order.Subtotal).Error; err != nil {
+		return fmt.Errorf("failed to update order subtotal: %w", err)
+	}
+	return nil
+}
+
+// deleteOrderItems removes draft order lines and their selected modifiers
+func deleteOrderItems(tx *gorm.DB, itemIDs []uint) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("transaction_item_id IN ?", itemIDs).Delete(&entities.TransactionItemModifier{}).Error; err != nil {
+		return fmt.Errorf("failed to remove order item modifiers: %w", err)
+	}
+	if err := tx.Where("id IN ?", itemIDs).Delete(&entities.TransactionItem{}).Error; err != nil {
+		return fmt.Errorf("failed to remove order items: %w", err)
+	}
+	return nil
+}
@@ -2,29 +2,44 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 	"github.com/usernamesalah/rh-pos/internal/pkg/storage"
 	"github.com/usernamesalah/rh-pos/internal/pkg/storage/minio"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productService struct {
-	productRepo interfaces.ProductRepository
-	storage     minio.StorageClient
-	logger      *slog.Logger
+	productRepo       interfaces.ProductRepository
+	categoryRepo      interfaces.CategoryRepository
+	tagRepo           interfaces.TagRepository
+	barcodeRepo       interfaces.ProductBarcodeRepository
+	stockMovementRepo interfaces.StockMovementRepository
+	storage           minio.StorageClient
+	db                *gorm.DB
+	logger            *slog.Logger
 }
 
 // NewProductService creates a new product service
-func NewProductService(productRepo interfaces.ProductRepository, storage minio.StorageClient, logger *slog.Logger) interfaces.ProductService {
+func NewProductService(productRepo interfaces.ProductRepository, categoryRepo interfaces.CategoryRepository, tagRepo interfaces.TagRepository, barcodeRepo interfaces.ProductBarcodeRepository, stockMovementRepo interfaces.StockMovementRepository, storage minio.StorageClient, db *gorm.DB, logger *slog.Logger) interfaces.ProductService {
 	return &productService{
-		productRepo: productRepo,
-		storage:     storage,
-		logger:      logger,
+		productRepo:       productRepo,
+		categoryRepo:      categoryRepo,
+		tagRepo:           tagRepo,
+		barcodeRepo:       barcodeRepo,
+		stockMovementRepo: stockMovementRepo,
+		storage:           storage,
+		db:                db,
+		logger:            logger,
 	}
 }
 
@@ -40,19 +55,28 @@ func (s *productService) GetProduct(ctx context.Context, id uint) (*entities.Pro
 	return product, nil
 }
 
-// ListProducts retrieves products with pagination
-func (s *productService) ListProducts(ctx context.Context, page, limit int) ([]entities.Product, int64, error) {
-	s.logger.InfoContext(ctx, "listing products", "page", page, "limit", limit)
+// ListProducts retrieves products matching the query with pagination
+func (s *productService) ListProducts(ctx context.Context, query interfaces.ProductListQuery) ([]entities.Product, int64, error) {
+	s.logger.InfoContext(ctx, "listing products", "page", query.Page, "limit", query.Limit, "search", query.Search)
 
 	// Validate pagination parameters
-	if page < 1 {
-		page = 1
+	if query.Page < 1 {
+		query.Page = 1
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 10
+	}
+
+	// A category filter also matches products in its subcategories
+	if query.CategoryID != nil {
+		categories, err := s.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list categories: %w", err)
+		}
+		query.CategoryIDs = descendantCategoryIDs(categories, *query.CategoryID)
 	}
 
-	products, total, err := s.productRepo.List(ctx, page, limit)
+	products, total, err := s.productRepo.List(ctx, query)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list products: %w", err)
 	}
@@ -60,10 +84,26 @@ func (s *productService) ListProducts(ctx context.Context, page, limit int) ([]e
 	return products, total, nil
 }
 
+// ListTags retrieves all product tags of the current tenant
+func (s *productService) ListTags(ctx context.Context) ([]entities.Tag, error) {
+	s.logger.InfoContext(ctx, "listing tags")
+
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
 // UpdateProduct updates a product with the provided fields
 func (s *productService) UpdateProduct(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Product, error) {
 	s.logger.InfoContext(ctx, "updating product", "id", id)
 
+	if err := authorize(ctx, entities.PermProductsManage); err != nil {
+		return nil, err
+	}
+
 	// Get tenant_id from context
 	tenantID, ok := ctx.Value("tenant_id").(uint)
 	if !ok {
@@ -76,6 +116,10 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, updates map
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
+	// Stock is owned by the ledger, so it is applied separately as an adjustment
+	stock, hasStock := updates["stock"]
+	tagNames, hasTags := updates["tags"]
+
 	// Update fields
 	for field, value := range updates {
 		switch field {
@@ -86,14 +130,29 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, updates map
 		case "sku":
 			product.SKU = value.(string)
 		case "harga_modal":
-			product.HargaModal = value.(float64)
+			product.HargaModal = value.(money.Money)
 		case "harga_jual":
-			product.HargaJual = value.(float64)
-		case "stock":
-			product.Stock = value.(int)
+			product.HargaJual = value.(money.Money)
+		case "reorder_point":
+			product.ReorderPoint = value.(int)
+		case "reorder_qty":
+			product.ReorderQty = value.(int)
+		case "tax_exempt":
+			product.TaxExempt = value.(bool)
+		case "category_id":
+			product.CategoryID = value.(*uint)
+			product.Category = nil
 		}
 	}
 
+	if product.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *product.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+		product.Category = category
+	}
+
 	// Ensure tenant_id is set
 	product.TenantID = &tenantID
 
@@ -102,6 +161,21 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, updates map
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
+	if hasTags {
+		tags, err := s.tagRepo.FindOrCreate(ctx, normalizeTagNames(tagNames.([]string)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tags: %w", err)
+		}
+		if err := s.productRepo.ReplaceTags(ctx, product, tags); err != nil {
+			return nil, fmt.Errorf("failed to update product tags: %w", err)
+		}
+		product.Tags = tags
+	}
+
+	if hasStock {
+		return s.UpdateStock(ctx, id, stock.(int))
+	}
+
 	return product, nil
 }
 
@@ -109,34 +183,52 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, updates map
 func (s *productService) UpdateStock(ctx context.Context, id uint, stock int) (*entities.Product, error) {
 	s.logger.InfoContext(ctx, "updating product stock", "id", id, "stock", stock)
 
+	if err := authorize(ctx, entities.PermStockManage); err != nil {
+		return nil, err
+	}
+
 	// Get tenant_id from context
 	tenantID, ok := ctx.Value("tenant_id").(uint)
 	if !ok {
 		return nil, fmt.Errorf("tenant_id not found in context")
 	}
 
-	// Get existing product
-	product, err := s.productRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get product: %w", err)
+	if stock < 0 {
+		return nil, fmt.Errorf("stock cannot be negative")
 	}
 
-	// Update stock
-	product.Stock = stock
-	product.TenantID = &tenantID
+	// Record the difference from the locked current stock as an adjustment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product entities.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&product).Error; err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+		if err := rejectVariantParents(tx, []uint{id}); err != nil {
+			return err
+		}
 
-	// Save changes
-	if err := s.productRepo.Update(ctx, product); err != nil {
+		return adjustStock(ctx, tx, &entities.StockMovement{
+			ProductID: id,
+			Delta:     stock - product.Stock,
+			Reason:    entities.StockReasonAdjustment,
+			TenantID:  &tenantID,
+		})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update product stock: %w", err)
 	}
 
-	return product, nil
+	return s.productRepo.GetByID(ctx, id)
 }
 
 // CreateProduct creates a new product
 func (s *productService) CreateProduct(ctx context.Context, product *entities.Product) error {
 	s.logger.InfoContext(ctx, "creating product", "sku", product.SKU)
 
+	if err := authorize(ctx, entities.PermProductsManage); err != nil {
+		return err
+	}
+
 	// Get tenant_id from context
 	tenantID, ok := ctx.Value("tenant_id").(uint)
 	if !ok {
@@ -152,14 +244,367 @@ func (s *productService) CreateProduct(ctx context.Context, product *entities.Pr
 		return fmt.Errorf("product with SKU %s already exists", product.SKU)
 	}
 
-	// Create product
-	if err := s.productRepo.Create(ctx, product); err != nil {
-		return fmt.Errorf("failed to create product: %w", err)
+	if product.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *product.CategoryID); err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+	}
+
+	// Tags arrive by name and are matched against the tenant's existing tags
+	if len(product.Tags) > 0 {
+		names := make([]string, len(product.Tags))
+		for i, tag := range product.Tags {
+			names[i] = tag.Name
+		}
+		tags, err := s.tagRepo.FindOrCreate(ctx, normalizeTagNames(names))
+		if err != nil {
+			return fmt.Errorf("failed to resolve tags: %w", err)
+		}
+		product.Tags = tags
+	}
+
+	// Create product and book its opening stock in the ledger
+	openingStock := product.Stock
+	product.Stock = 0
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("failed to create product: %w", err)
+		}
+
+		return adjustStock(ctx, tx, &entities.StockMovement{
+			ProductID: product.ID,
+			Delta:     openingStock,
+			Reason:    entities.StockReasonAdjustment,
+			TenantID:  &tenantID,
+		})
+	})
+	if err != nil {
+		return err
 	}
+	product.Stock = openingStock
 
 	return nil
 }
 
+// LookupBarcode finds the product for a scanned code and the quantity one scan represents.
+// Codes without a registered barcode fall back to the product SKU with a quantity of one.
+func (s *productService) LookupBarcode(ctx context.Context, code string) (*entities.Product, int, error) {
+	s.logger.InfoContext(ctx, "looking up barcode", "code", code)
+
+	barcode, err := s.barcodeRepo.GetByCode(ctx, code)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, fmt.Errorf("failed to get barcode: %w", err)
+		}
+
+		product, err := s.productRepo.GetBySKU(ctx, code)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get product: %w", err)
+		}
+		return product, 1, nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, barcode.ProductID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, barcode.Multiplier, nil
+}
+
+// AddBarcode registers a barcode for a product
+func (s *productService) AddBarcode(ctx context.Context, productID uint, barcode *entities.ProductBarcode) error {
+	s.logger.InfoContext(ctx, "adding product barcode", "product_id", productID, "code", barcode.Code)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if existing, err := s.barcodeRepo.GetByCode(ctx, barcode.Code); err == nil && existing != nil {
+		return entities.ErrBarcodeTaken
+	}
+
+	barcode.ProductID = productID
+	barcode.TenantID = &tenantID
+	if barcode.Multiplier < 1 {
+		barcode.Multiplier = 1
+	}
+
+	if err := s.barcodeRepo.Create(ctx, barcode); err != nil {
+		return fmt.Errorf("failed to add barcode: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveBarcode deletes a barcode from a product
+func (s *productService) RemoveBarcode(ctx context.Context, productID, barcodeID uint) error {
+	s.logger.InfoContext(ctx, "removing product barcode", "product_id", productID, "barcode_id", barcodeID)
+
+	if err := s.barcodeRepo.Delete(ctx, productID, barcodeID); err != nil {
+		return fmt.Errorf("failed to remove barcode: %w", err)
+	}
+
+	return nil
+}
+
+// maxProductVariants caps how many variants one option set may generate
+const maxProductVariants = 100
+
+// GenerateVariants sets the option dimensions of a parent product and creates a variant
+// for every combination of option values that does not exist yet. New variants copy the
+// parent's prices, image and category and start with zero stock. Existing variants are kept.
+func (s *productService) GenerateVariants(ctx context.Context, productID uint, options []interfaces.ProductOptionInput) (*entities.Product, error) {
+	s.logger.InfoContext(ctx, "generating product variants", "product_id", productID, "options", len(options))
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	parent, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if parent.ParentID != nil {
+		return nil, entities.ErrNestedVariant
+	}
+	if !parent.HasVariants() && parent.Stock > 0 {
+		return nil, fmt.Errorf("product still holds %d in stock; set it to zero before adding variants", parent.Stock)
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("at least one option is required")
+	}
+	combinations := [][]string{{}}
+	for _, option := range options {
+		if strings.TrimSpace(option.Name) == "" || len(option.Values) == 0 {
+			return nil, fmt.Errorf("every option needs a name and at least one value")
+		}
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				next = append(next, append(append([]string{}, combination...), strings.TrimSpace(value)))
+			}
+		}
+		combinations = next
+	}
+	if len(combinations) > maxProductVariants {
+		return nil, fmt.Errorf("options would generate %d variants, the maximum is %d", len(combinations), maxProductVariants)
+	}
+
+	existing := make(map[string]bool, len(parent.Variants))
+	for _, v := range parent.Variants {
+		existing[v.VariantName] = true
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Replace the option definitions
+		var optionIDs []uint
+		if err := tx.Model(&entities.ProductOption{}).Where("product_id = ?", productID).Pluck("id", &optionIDs).Error; err != nil {
+			return fmt.Errorf("failed to get product options: %w", err)
+		}
+		if len(optionIDs) > 0 {
+			if err := tx.Where("option_id IN ?", optionIDs).Delete(&entities.ProductOptionValue{}).Error; err != nil {
+				return fmt.Errorf("failed to delete product option values: %w", err)
+			}
+			if err := tx.Where("id IN ?", optionIDs).Delete(&entities.ProductOption{}).Error; err != nil {
+				return fmt.Errorf("failed to delete product options: %w", err)
+			}
+		}
+		for i, option := range options {
+			productOption := entities.ProductOption{
+				ProductID: productID,
+				Name:      strings.TrimSpace(option.Name),
+				Position:  i,
+				Values:    make([]entities.ProductOptionValue, len(option.Values)),
+			}
+			for j, value := range option.Values {
+				productOption.Values[j] = entities.ProductOptionValue{Value: strings.TrimSpace(value), Position: j}
+			}
+			if err := tx.Create(&productOption).Error; err != nil {
+				return fmt.Errorf("failed to create product option: %w", err)
+			}
+		}
+
+		for _, combination := range combinations {
+			variantName := strings.Join(combination, " / ")
+			if existing[variantName] {
+				continue
+			}
+
+			variant := &entities.Product{
+				Name:         parent.Name + " - " + variantName,
+				SKU:          parent.SKU + "-" + strings.ToUpper(strings.ReplaceAll(strings.Join(combination, "-"), " ", "")),
+				Image:        parent.Image,
+				HargaModal:   parent.HargaModal,
+				HargaJual:    parent.HargaJual,
+				ReorderPoint: parent.ReorderPoint,
+				ReorderQty:   parent.ReorderQty,
+				TaxExempt:    parent.TaxExempt,
+				CategoryID:   parent.CategoryID,
+				ParentID:     &parent.ID,
+				VariantName:  variantName,
+				TenantID:     &tenantID,
+			}
+
+			var taken int64
+			if err := tx.Model(&entities.Product{}).Where("sku = ?", variant.SKU).Count(&taken).Error; err != nil {
+				return fmt.Errorf("failed to check SKU: %w", err)
+			}
+			if taken > 0 {
+				return fmt.Errorf("product with SKU %s already exists", variant.SKU)
+			}
+
+			if err := tx.Omit(clause.Associations).Create(variant).Error; err != nil {
+				return fmt.Errorf("failed to create variant: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.GetByID(ctx, productID)
+}
+
+// SetRecipe replaces the recipe of a product. Selling a product with a recipe deducts
+// the component stock, and also its own stock when deductOwnStock is set.
+// An empty component list turns the product back into a plain stocked product.
+func (s *productService) SetRecipe(ctx context.Context, productID uint, components []interfaces.ProductComponentInput, deductOwnStock bool) (*entities.Product, error) {
+	s.logger.InfoContext(ctx, "setting product recipe", "product_id", productID, "components", len(components))
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product.HasVariants() {
+		return nil, entities.ErrProductHasVariants
+	}
+
+	seen := make(map[uint]bool, len(components))
+	for _, c := range components {
+		if c.Quantity < 1 {
+			return nil, fmt.Errorf("component quantity must be at least 1")
+		}
+		if c.ProductID == productID || seen[c.ProductID] {
+			return nil, fmt.Errorf("component %d: %w", c.ProductID, entities.ErrInvalidComponent)
+		}
+		seen[c.ProductID] = true
+
+		// Recipes are one level deep: components are stocked items, not menu items
+		component, err := s.productRepo.GetByID(ctx, c.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get component: %w", err)
+		}
+		if component.IsComposite() || component.HasVariants() {
+			return nil, fmt.Errorf("component %s: %w", component.Name, entities.ErrInvalidComponent)
+		}
+	}
+
+	// Products used as a component cannot get a recipe of their own
+	if len(components) > 0 {
+		var usedIn int64
+		if err := s.db.WithContext(ctx).Model(&entities.ProductComponent{}).Where("component_id = ?", productID).Count(&usedIn).Error; err != nil {
+			return nil, fmt.Errorf("failed to check recipes: %w", err)
+		}
+		if usedIn > 0 {
+			return nil, fmt.Errorf("product is a component of another recipe: %w", entities.ErrInvalidComponent)
+		}
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&entities.ProductComponent{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recipe: %w", err)
+		}
+
+		for _, c := range components {
+			if err := tx.Create(&entities.ProductComponent{
+				ProductID:   productID,
+				ComponentID: c.ProductID,
+				Quantity:    c.Quantity,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to create recipe component: %w", err)
+			}
+		}
+
+		if err := tx.Model(&entities.Product{}).Where("id = ?", productID).Update("deduct_own_stock", deductOwnStock && len(components) > 0).Error; err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.GetByID(ctx, productID)
+}
+
+// normalizeTagNames trims tag names and drops blanks and duplicates
+func normalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// GetStockHistory retrieves the stock ledger of a product
+func (s *productService) GetStockHistory(ctx context.Context, productID uint, page, limit int) ([]entities.StockMovement, int64, error) {
+	s.logger.InfoContext(ctx, "getting stock history", "product_id", productID, "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, 0, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	movements, total, err := s.stockMovementRepo.ListByProduct(ctx, productID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get stock history: %w", err)
+	}
+
+	return movements, total, nil
+}
+
+// ListLowStockProducts retrieves products at or below their reorder point with pagination
+func (s *productService) ListLowStockProducts(ctx context.Context, page, limit int) ([]entities.Product, int64, error) {
+	s.logger.InfoContext(ctx, "listing low stock products", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	products, total, err := s.productRepo.ListLowStock(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list low stock products: %w", err)
+	}
+
+	return products, total, nil
+}
+
 // GetProductImageURL generates a presigned GET URL for the product image
 func (s *productService) GetProductImageURL(ctx context.Context, product *entities.Product) (string, error) {
 	if product.Image == "" {
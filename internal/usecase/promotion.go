@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loadPromotions returns the tenant's promotions running at now. Voucher promotions are only
+// included when voucherCode matches their code or voucherPromotionID is the promotion of a
+// redeemed generated voucher, and an unknown voucher code is rejected.
+func loadPromotions(tx *gorm.DB, tenantID uint, voucherCode string, voucherPromotionID uint, now time.Time) ([]entities.Promotion, error) {
+	var promotions []entities.Promotion
+	if err := tx.Preload("Products").Where("tenant_id = ? AND active = ?", tenantID, true).Order("id").Find(&promotions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load promotions: %w", err)
+	}
+
+	running := make([]entities.Promotion, 0, len(promotions))
+	voucherFound := false
+	for _, p := range promotions {
+		if !p.ActiveAt(now) {
+			continue
+		}
+		if p.RequiresVoucher() {
+			matched := (voucherPromotionID != 0 && p.ID == voucherPromotionID) ||
+				(p.Code != "" && voucherCode != "" && strings.EqualFold(p.Code, voucherCode))
+			if !matched {
+				continue
+			}
+			voucherFound = true
+		}
+		running = append(running, p)
+	}
+
+	if voucherCode != "" && !voucherFound {
+		return nil, fmt.Errorf("voucher %s: %w", voucherCode, entities.ErrInvalidVoucher)
+	}
+	return running, nil
+}
+
+// applyPromotions works out promotion discounts for the basket and records them on the items.
+// Item promotions are applied greedily, biggest discount first, and each line takes at most one
+// of them. Minimum-spend promotions then discount the remaining basket, spread over its lines.
+// It returns the promotions that gave a discount.
+func applyPromotions(items []entities.TransactionItem, products map[uint]*entities.Product, promotions []entities.Promotion) map[uint]bool {
+	applied := make(map[uint]bool)
+	claimed := make([]bool, len(items))
+	used := make([]bool, len(promotions))
+
+	for {
+		best := -1
+		var bestDiscounts map[int]money.Money
+		var bestTotal money.Money
+		for i := range promotions {
+			if used[i] || promotions[i].Type == entities.PromotionTypeMinSpend {
+				continue
+			}
+			discounts := evaluatePromotion(&promotions[i], items, products, claimed)
+			var total money.Money
+			for _, d := range discounts {
+				total += d
+			}
+			if total > bestTotal {
+				best, bestDiscounts, bestTotal = i, discounts, total
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		used[best] = true
+		applied[promotions[best].ID] = true
+		for line, amount := range bestDiscounts {
+			recordPromotion(&items[line], &promotions[best], amount)
+			claimed[line] = true
+		}
+	}
+
+	// Only the best basket-level discount applies
+	var basket money.Money
+	for _, item := range items {
+		basket += item.Price.Mul(item.Quantity) - item.Discount
+	}
+	best := -1
+	var bestDiscount money.Money
+	for i := range promotions {
+		p := &promotions[i]
+		if p.Type != entities.PromotionTypeMinSpend || basket < p.MinSpend {
+			continue
+		}
+		discount := min(p.DiscountAmount, basket)
+		if p.DiscountPercent > 0 {
+			discount = basket.Percent(p.DiscountPercent)
+		}
+		if discount > bestDiscount {
+			best, bestDiscount = i, discount
+		}
+	}
+	if best >= 0 {
+		applied[promotions[best].ID] = true
+		remaining := bestDiscount
+		for i := range items {
+			line := items[i].Price.Mul(items[i].Quantity) - items[i].Discount
+			share := money.Money(int64(bestDiscount) * int64(line) / int64(basket))
+			if i == len(items)-1 {
+				share = remaining
+			}
+			remaining -= share
+			if share > 0 {
+				recordPromotion(&items[i], &promotions[best], share)
+			}
+		}
+	}
+
+	return applied
+}
+
+// promotionQualifies reports whether p would discount the basket if it were the only promotion
+func promotionQualifies(p *entities.Promotion, items []entities.TransactionItem, products map[uint]*entities.Product) bool {
+	if p.Type != entities.PromotionTypeMinSpend {
+		return len(evaluatePromotion(p, items, products, make([]bool, len(items)))) > 0
+	}
+
+	var basket money.Money
+	for _, item := range items {
+		basket += item.Price.Mul(item.Quantity)
+	}
+	return basket > 0 && basket >= p.MinSpend && (p.DiscountAmount > 0 || p.DiscountPercent > 0)
+}
+
+// recordPromotion adds a promotion discount to a transaction item
+func recordPromotion(item *entities.TransactionItem, p *entities.Promotion, amount money.Money) {
+	item.Discount += amount
+	item.Promotions = append(item.Promotions, entities.TransactionItemPromotion{
+		PromotionID: p.ID,
+		Name:        p.Name,
+		Amount:      amount,
+	})
+}
+
+// promotionUnit is one unit of an eligible line, for promotions that group units across lines
+type promotionUnit struct {
+	line  int
+	price money.Money
+}
+
+// evaluatePromotion returns the discount an item promotion gives on each unclaimed eligible line
+func evaluatePromotion(p *entities.Promotion, items []entities.TransactionItem, products map[uint]*entities.Product, claimed []bool) map[int]money.Money {
+	discounts := make(map[int]money.Money)
+
+	var units []promotionUnit
+	for i, item := range items {
+		if claimed[i] || !p.AppliesTo(products[item.ProductID]) {
+			continue
+		}
+		if p.Type == entities.PromotionTypeItemDiscount {
+			perUnit := min(p.DiscountAmount, item.Price)
+			if p.DiscountPercent > 0 {
+				perUnit = item.Price.Percent(p.DiscountPercent)
+			}
+			if perUnit > 0 {
+				discounts[i] = perUnit.Mul(item.Quantity)
+			}
+			continue
+		}
+		for n := 0; n < item.Quantity; n++ {
+			units = append(units, promotionUnit{line: i, price: item.Price})
+		}
+	}
+
+	// Group the most expensive units first so customers get the cheaper ones free
+	sort.SliceStable(units, func(a, b int) bool { return units[a].price > units[b].price })
+
+	switch p.Type {
+	case entities.PromotionTypeBuyXGetY:
+		size := p.BuyQty + p.GetQty
+		for start := 0; size > 0 && start+size <= len(units); start += size {
+			for _, u := range units[start+p.BuyQty : start+size] {
+				discount := u.price
+				if p.DiscountPercent > 0 {
+					discount = u.price.Percent(p.DiscountPercent)
+				}
+				discounts[u.line] += discount
+			}
+		}
+	case entities.PromotionTypeBundle:
+		size := p.BuyQty
+		for start := 0; size > 0 && start+size <= len(units); start += size {
+			group := units[start : start+size]
+			var total money.Money
+			for _, u := range group {
+				total += u.price
+			}
+			if total <= p.BundlePrice {
+				continue
+			}
+
+			// Spread the bundle saving over its units by price
+			saving := total - p.BundlePrice
+			remaining := saving
+			for j, u := range group {
+				share := money.Money(int64(saving) * int64(u.price) / int64(total))
+				if j == len(group)-1 {
+					share = remaining
+				}
+				remaining -= share
+				discounts[u.line] += share
+			}
+		}
+	}
+
+	for line, d := range discounts {
+		if d <= 0 {
+			delete(discounts, line)
+		}
+	}
+	return discounts
+}
+
+// lockVoucher loads the tenant's generated voucher with code, locking its row until tx ends so
+// concurrent sales cannot redeem the same use twice. It returns nil when no voucher has the code.
+func lockVoucher(tx *gorm.DB, tenantID uint, code string) (*entities.Voucher, error) {
+	var voucher entities.Voucher
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ? AND tenant_id = ?", code, tenantID).
+		First(&voucher).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voucher: %w", err)
+	}
+	return &voucher, nil
+}
+
+// checkVoucher rejects a voucher that is inactive, expired or used up, overall or by the customer
+func checkVoucher(tx *gorm.DB, v *entities.Voucher, customerPhone string, now time.Time) error {
+	if !v.Active {
+		return fmt.Errorf("voucher %s is inactive: %w", v.Code, entities.ErrInvalidVoucher)
+	}
+	if v.ExpiresAt != nil && !now.Before(*v.ExpiresAt) {
+		return fmt.Errorf("voucher %s expired: %w", v.Code, entities.ErrInvalidVoucher)
+	}
+	if v.Exhausted() {
+		return fmt.Errorf("voucher %s: %w", v.Code, entities.ErrVoucherExhausted)
+	}
+
+	if v.MaxUsesPerCustomer > 0 {
+		if customerPhone == "" {
+			return fmt.Errorf("voucher %s requires the customer phone number: %w", v.Code, entities.ErrInvalidVoucher)
+		}
+		var used int64
+		if err := tx.Model(&entities.VoucherRedemption{}).
+			Where("voucher_id = ? AND customer_phone = ?", v.ID, customerPhone).
+			Count(&used).Error; err != nil {
+			return fmt.Errorf("failed to count voucher redemptions: %w", err)
+		}
+		if int(used) >= v.MaxUsesPerCustomer {
+			return fmt.Errorf("voucher %s already used by this customer: %w", v.Code, entities.ErrVoucherExhausted)
+		}
+	}
+	return nil
+}
+
+// redeemVoucher takes one use of the voucher for the sale and records the discount its promotion gave.
+// A voucher whose promotion discounted nothing in the basket is rejected without taking a use.
+func redeemVoucher(tx *gorm.DB, v *entities.Voucher, transaction *entities.Transaction) error {
+	redemption := entities.VoucherRedemption{
+		VoucherID:     v.ID,
+		TransactionID: transaction.ID,
+		CustomerPhone: transaction.CustomerPhone,
+	}
+	for _, item := range transaction.Items {
+		for _, p := range item.Promotions {
+			if p.PromotionID == v.PromotionID {
+				redemption.Discount += p.Amount
+			}
+		}
+	}
+	if redemption.Discount <= 0 {
+		return fmt.Errorf("voucher %s gives no discount on this basket: %w", v.Code, entities.ErrInvalidVoucher)
+	}
+
+	// The guard in the WHERE clause keeps the count within its limit even without the row lock
+	result := tx.Model(&entities.Voucher{}).
+		Where("id = ? AND (max_uses = 0 OR used_count < max_uses)", v.ID).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to redeem voucher: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("voucher %s: %w", v.Code, entities.ErrVoucherExhausted)
+	}
+
+	if err := tx.Create(&redemption).Error; err != nil {
+		return fmt.Errorf("failed to record voucher redemption: %w", err)
+	}
+	return nil
+}
+
+// releaseVouchers gives back the voucher uses of a voided sale
+func releaseVouchers(tx *gorm.DB, transactionID uint) error {
+	var redemptions []entities.VoucherRedemption
+	if err := tx.Where("transaction_id = ?", transactionID).Find(&redemptions).Error; err != nil {
+		return fmt.Errorf("failed to get voucher redemptions: %w", err)
+	}
+
+	for _, r := range redemptions {
+		if err := tx.Model(&entities.Voucher{}).
+			Where("id = ? AND used_count > 0", r.VoucherID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			return fmt.Errorf("failed to release voucher: %w", err)
+		}
+		if err := tx.Delete(&r).Error; err != nil {
+			return fmt.Errorf("failed to delete voucher redemption: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+// Voucher batches are limited so a single request cannot flood the table
+const (
+	maxVoucherBatch         = 1000
+	defaultVoucherLength    = 8
+	voucherCodeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I look-alikes
+	voucherGenerateAttempts = 5
+)
+
+type promotionService struct {
+	promotionRepo interfaces.PromotionRepository
+	voucherRepo   interfaces.VoucherRepository
+	productRepo   interfaces.ProductRepository
+	logger        *slog.Logger
+}
+
+// NewPromotionService creates a new promotion service
+func NewPromotionService(promotionRepo interfaces.PromotionRepository, voucherRepo interfaces.VoucherRepository, productRepo interfaces.ProductRepository, logger *slog.Logger) interfaces.PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+		voucherRepo:   voucherRepo,
+		productRepo:   productRepo,
+		logger:        logger,
+	}
+}
+
+// CreatePromotion creates a new promotion for the current tenant
+func (s *promotionService) CreatePromotion(ctx context.Context, promotion *entities.Promotion) error {
+	s.logger.InfoContext(ctx, "creating promotion", "name", promotion.Name, "type", promotion.Type)
+
+	if err := authorize(ctx, entities.PermPromotionsManage); err != nil {
+		return err
+	}
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+	promotion.TenantID = &tenantID
+
+	if err := s.validate(ctx, 0, promotion); err != nil {
+		return err
+	}
+
+	if err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	return nil
+}
+
+// GetPromotion retrieves a promotion by ID
+func (s *promotionService) GetPromotion(ctx context.Context, id uint) (*entities.Promotion, error) {
+	s.logger.InfoContext(ctx, "getting promotion", "id", id)
+
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// ListPromotions retrieves promotions with pagination
+func (s *promotionService) ListPromotions(ctx context.Context, page, limit int) ([]entities.Promotion, int64, error) {
+	s.logger.InfoContext(ctx, "listing promotions", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	promotions, total, err := s.promotionRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	return promotions, total, nil
+}
+
+// UpdatePromotion replaces a promotion's rules, schedule and products
+func (s *promotionService) UpdatePromotion(ctx context.Context, id uint, promotion *entities.Promotion) (*entities.Promotion, error) {
+	s.logger.InfoContext(ctx, "updating promotion", "id", id)
+
+	if err := authorize(ctx, entities.PermPromotionsManage); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	promotion.ID = existing.ID
+	promotion.TenantID = existing.TenantID
+	promotion.CreatedAt = existing.CreatedAt
+
+	if err := s.validate(ctx, id, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionRepo.Update(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	return s.promotionRepo.GetByID(ctx, id)
+}
+
+// DeletePromotion deletes a promotion; past sales keep the discounts it gave
+func (s *promotionService) DeletePromotion(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting promotion", "id", id)
+
+	if err := authorize(ctx, entities.PermPromotionsManage); err != nil {
+		return err
+	}
+
+	if err := s.promotionRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateVouchers creates a batch of unique voucher codes for a voucher-only promotion
+func (s *promotionService) GenerateVouchers(ctx context.Context, promotionID uint, req interfaces.GenerateVouchersRequest) ([]entities.Voucher, error) {
+	s.logger.InfoContext(ctx, "generating vouchers", "promotion_id", promotionID, "count", req.Count)
+
+	if err := authorize(ctx, entities.PermPromotionsManage); err != nil {
+		return nil, err
+	}
+
+	promotion, err := s.promotionRepo.GetByID(ctx, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	if !promotion.VoucherOnly {
+		return nil, fmt.Errorf("vouchers can only be generated for voucher_only promotions: %w", entities.ErrInvalidPromotion)
+	}
+
+	if req.Length == 0 {
+		req.Length = defaultVoucherLength
+	}
+	if req.Count < 1 || req.Count > maxVoucherBatch {
+		return nil, fmt.Errorf("count must be between 1 and %d: %w", maxVoucherBatch, entities.ErrInvalidPromotion)
+	}
+	if req.Length < 6 || req.Length > 16 {
+		return nil, fmt.Errorf("length must be between 6 and 16: %w", entities.ErrInvalidPromotion)
+	}
+	if req.MaxUses < 0 || req.MaxUsesPerCustomer < 0 || req.MinBasket < 0 {
+		return nil, fmt.Errorf("limits cannot be negative: %w", entities.ErrInvalidPromotion)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future: %w", entities.ErrInvalidPromotion)
+	}
+
+	codes, err := s.uniqueVoucherCodes(ctx, strings.ToUpper(strings.TrimSpace(req.Prefix)), req.Length, req.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	vouchers := make([]entities.Voucher, len(codes))
+	for i, code := range codes {
+		vouchers[i] = entities.Voucher{
+			PromotionID:        promotion.ID,
+			Code:               code,
+			MaxUses:            req.MaxUses,
+			MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+			MinBasket:          req.MinBasket,
+			ExpiresAt:          req.ExpiresAt,
+			Active:             true,
+			TenantID:           promotion.TenantID,
+		}
+	}
+
+	if err := s.voucherRepo.CreateBatch(ctx, vouchers); err != nil {
+		return nil, fmt.Errorf("failed to create vouchers: %w", err)
+	}
+
+	return vouchers, nil
+}
+
+// ListVouchers retrieves the vouchers of a promotion with pagination
+func (s *promotionService) ListVouchers(ctx context.Context, promotionID uint, page, limit int) ([]entities.Voucher, int64, error) {
+	s.logger.InfoContext(ctx, "listing vouchers", "promotion_id", promotionID, "page", page, "limit", limit)
+
+	if _, err := s.promotionRepo.GetByID(ctx, promotionID); err != nil {
+		return nil, 0, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	vouchers, total, err := s.voucherRepo.ListByPromotion(ctx, promotionID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list vouchers: %w", err)
+	}
+
+	return vouchers, total, nil
+}
+
+// ExportVouchers retrieves every voucher of a promotion
+func (s *promotionService) ExportVouchers(ctx context.Context, promotionID uint) ([]entities.Voucher, error) {
+	s.logger.InfoContext(ctx, "exporting vouchers", "promotion_id", promotionID)
+
+	if _, err := s.promotionRepo.GetByID(ctx, promotionID); err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	vouchers, err := s.voucherRepo.ListAllByPromotion(ctx, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export vouchers: %w", err)
+	}
+
+	return vouchers, nil
+}
+
+// uniqueVoucherCodes generates count random codes that the tenant does not use yet
+func (s *promotionService) uniqueVoucherCodes(ctx context.Context, prefix string, length, count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	taken := make(map[string]bool, count)
+
+	for attempt := 0; attempt < voucherGenerateAttempts && len(codes) < count; attempt++ {
+		batch := make([]string, 0, count-len(codes))
+		for len(batch) < cap(batch) {
+			code, err := generateVoucherCode(prefix, length)
+			if err != nil {
+				return nil, err
+			}
+			if taken[code] {
+				continue
+			}
+			taken[code] = true
+			batch = append(batch, code)
+		}
+
+		existing, err := s.voucherRepo.ExistingCodes(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		used := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			used[code] = true
+		}
+		for _, code := range batch {
+			if !used[code] {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	if len(codes) < count {
+		return nil, fmt.Errorf("could not generate %d unique codes, use a longer length: %w", count, entities.ErrInvalidPromotion)
+	}
+	return codes, nil
+}
+
+// generateVoucherCode returns prefix followed by length random characters
+func generateVoucherCode(prefix string, length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate voucher code: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = voucherCodeAlphabet[int(b)%len(voucherCodeAlphabet)]
+	}
+	return prefix + string(buf), nil
+}
+
+// validate checks the rule fields the promotion type needs, its schedule, its voucher code
+// and that its products belong to the tenant. id is the promotion being updated, or zero.
+func (s *promotionService) validate(ctx context.Context, id uint, p *entities.Promotion) error {
+	if p.Name == "" {
+		return fmt.Errorf("promotion name is required: %w", entities.ErrInvalidPromotion)
+	}
+	if p.DiscountAmount < 0 || p.DiscountPercent < 0 || p.DiscountPercent > 100 || p.BundlePrice < 0 || p.MinSpend < 0 {
+		return fmt.Errorf("amounts cannot be negative and percentages cannot exceed 100: %w", entities.ErrInvalidPromotion)
+	}
+
+	switch p.Type {
+	case entities.PromotionTypeBuyXGetY:
+		if p.BuyQty < 1 || p.GetQty < 1 {
+			return fmt.Errorf("buy_qty and get_qty must be at least 1: %w", entities.ErrInvalidPromotion)
+		}
+	case entities.PromotionTypeBundle:
+		if p.BuyQty < 2 || p.BundlePrice <= 0 {
+			return fmt.Errorf("bundles need buy_qty of at least 2 and a bundle_price: %w", entities.ErrInvalidPromotion)
+		}
+	case entities.PromotionTypeItemDiscount, entities.PromotionTypeMinSpend:
+		if (p.DiscountAmount > 0) == (p.DiscountPercent > 0) {
+			return fmt.Errorf("set either discount_amount or discount_percent: %w", entities.ErrInvalidPromotion)
+		}
+		if p.Type == entities.PromotionTypeMinSpend && p.MinSpend <= 0 {
+			return fmt.Errorf("min_spend must be greater than zero: %w", entities.ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("unknown promotion type %q: %w", p.Type, entities.ErrInvalidPromotion)
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at: %w", entities.ErrInvalidPromotion)
+	}
+	if (p.DailyStart == "") != (p.DailyEnd == "") {
+		return fmt.Errorf("set both daily_start and daily_end: %w", entities.ErrInvalidPromotion)
+	}
+	for _, clock := range []string{p.DailyStart, p.DailyEnd} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return fmt.Errorf("invalid time %q, use HH:MM: %w", clock, entities.ErrInvalidPromotion)
+		}
+	}
+
+	days, err := normalizeDays(p.Days)
+	if err != nil {
+		return err
+	}
+	p.Days = days
+
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if p.Code != "" {
+		existing, err := s.promotionRepo.GetByCode(ctx, p.Code)
+		if err == nil && existing.ID != id {
+			return fmt.Errorf("voucher code %s is already used: %w", p.Code, entities.ErrInvalidPromotion)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check voucher code: %w", err)
+		}
+	}
+
+	// Products must belong to the same tenant
+	for _, product := range p.Products {
+		if _, err := s.productRepo.GetByID(ctx, product.ID); err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeDays validates a comma-separated list of weekday numbers (0 is Sunday) and
+// returns it without spaces or duplicates
+func normalizeDays(days string) (string, error) {
+	if strings.TrimSpace(days) == "" {
+		return "", nil
+	}
+
+	seen := make(map[int]bool)
+	normalized := make([]string, 0, 7)
+	for _, part := range strings.Split(days, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || day < 0 || day > 6 {
+			return "", fmt.Errorf("invalid weekday %q, use 0 (Sunday) to 6: %w", part, entities.ErrInvalidPromotion)
+		}
+		if seen[day] {
+			continue
+		}
+		seen[day] = true
+		normalized = append(normalized, strconv.Itoa(day))
+	}
+
+	return strings.Join(normalized, ","), nil
+}
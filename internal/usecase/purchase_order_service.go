@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type purchaseOrderService struct {
+	purchaseOrderRepo interfaces.PurchaseOrderRepository
+	supplierRepo      interfaces.SupplierRepository
+	productRepo       interfaces.ProductRepository
+	db                *gorm.DB
+	logger            *slog.Logger
+}
+
+// NewPurchaseOrderService creates a new purchase order service
+func NewPurchaseOrderService(purchaseOrderRepo interfaces.PurchaseOrderRepository, supplierRepo interfaces.SupplierRepository, productRepo interfaces.ProductRepository, db *gorm.DB, logger *slog.Logger) interfaces.PurchaseOrderService {
+	return &purchaseOrderService{
+		purchaseOrderRepo: purchaseOrderRepo,
+		supplierRepo:      supplierRepo,
+		productRepo:       productRepo,
+		db:                db,
+		logger:            logger,
+	}
+}
+
+// CreatePurchaseOrder creates an open purchase order for a supplier
+func (s *purchaseOrderService) CreatePurchaseOrder(ctx context.Context, req interfaces.CreatePurchaseOrderRequest) (*entities.PurchaseOrder, error) {
+	s.logger.InfoContext(ctx, "creating purchase order", "supplier_id", req.SupplierID, "items", len(req.Items))
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("purchase order must have at least one item")
+	}
+
+	if _, err := s.supplierRepo.GetByID(ctx, req.SupplierID); err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	order := &entities.PurchaseOrder{
+		SupplierID: req.SupplierID,
+		Status:     entities.PurchaseOrderStatusOpen,
+		ExpectedAt: req.ExpectedAt,
+		Notes:      req.Notes,
+		CreatedBy:  userID,
+		TenantID:   &tenantID,
+		Items:      make([]entities.PurchaseOrderItem, len(req.Items)),
+	}
+
+	for i, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be greater than zero")
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("unit cost cannot be negative")
+		}
+
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		if product.HasVariants() {
+			return nil, fmt.Errorf("product %d: %w", item.ProductID, entities.ErrProductHasVariants)
+		}
+
+		order.Items[i] = entities.PurchaseOrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		}
+		order.TotalCost += item.UnitCost.Mul(item.Quantity)
+	}
+
+	if err := s.purchaseOrderRepo.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	return s.purchaseOrderRepo.GetByID(ctx, order.ID)
+}
+
+// GetPurchaseOrder retrieves a purchase order by ID
+func (s *purchaseOrderService) GetPurchaseOrder(ctx context.Context, id uint) (*entities.PurchaseOrder, error) {
+	s.logger.InfoContext(ctx, "getting purchase order", "id", id)
+
+	order, err := s.purchaseOrderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	return order, nil
+}
+
+// ListPurchaseOrders retrieves purchase orders with pagination
+func (s *purchaseOrderService) ListPurchaseOrders(ctx context.Context, page, limit int) ([]entities.PurchaseOrder, int64, error) {
+	s.logger.InfoContext(ctx, "listing purchase orders", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	orders, total, err := s.purchaseOrderRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// ReceiveGoods books a delivery against a purchase order: stock is incremented through the ledger,
+// the cost paid is kept on the receipt and, when requested, HargaModal moves to the weighted average cost.
+func (s *purchaseOrderService) ReceiveGoods(ctx context.Context, id uint, req interfaces.ReceiveGoodsRequest) (*entities.PurchaseOrder, error) {
+	s.logger.InfoContext(ctx, "receiving goods", "id", id, "items", len(req.Items), "update_cost", req.UpdateCost)
+
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("at least one received item is required")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOpenPurchaseOrder(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		items := make(map[uint]*entities.PurchaseOrderItem, len(order.Items))
+		for i := range order.Items {
+			items[order.Items[i].ID] = &order.Items[i]
+		}
+
+		receipt := &entities.GoodsReceipt{
+			PurchaseOrderID: order.ID,
+			Notes:           req.Notes,
+			UserID:          userID,
+			TenantID:        order.TenantID,
+		}
+
+		for _, reqItem := range req.Items {
+			item, ok := items[reqItem.PurchaseOrderItemID]
+			if !ok {
+				return fmt.Errorf("purchase order item %d: %w", reqItem.PurchaseOrderItemID, entities.ErrPurchaseOrderItemNotFound)
+			}
+			if reqItem.Quantity <= 0 || reqItem.Quantity > item.OutstandingQty() {
+				return fmt.Errorf("purchase order item %d: %w", item.ID, entities.ErrInvalidReceiveQuantity)
+			}
+
+			unitCost := item.UnitCost
+			if reqItem.UnitCost != nil {
+				if *reqItem.UnitCost < 0 {
+					return fmt.Errorf("unit cost cannot be negative")
+				}
+				unitCost = *reqItem.UnitCost
+			}
+
+			var product entities.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND tenant_id = ?", item.ProductID, order.TenantID).
+				First(&product).Error; err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+
+			// Updating the model also assigns the new cost to it, so keep the old one for the receipt
+			previousCost := product.HargaModal
+			newCost := product.HargaModal
+			if req.UpdateCost {
+				newCost = weightedAverageCost(product.HargaModal, product.Stock, unitCost, reqItem.Quantity)
+				if err := tx.Model(&product).Update("harga_modal", newCost).Error; err != nil {
+					return fmt.Errorf("failed to update product cost: %w", err)
+				}
+			}
+
+			if err := adjustStock(ctx, tx, &entities.StockMovement{
+				ProductID:   item.ProductID,
+				Delta:       reqItem.Quantity,
+				Reason:      entities.StockReasonReceiving,
+				ReferenceID: &order.ID,
+				TenantID:    order.TenantID,
+			}); err != nil {
+				return err
+			}
+
+			item.ReceivedQty += reqItem.Quantity
+			if err := tx.Model(item).Update("received_qty", item.ReceivedQty).Error; err != nil {
+				return fmt.Errorf("failed to update received quantity: %w", err)
+			}
+
+			receipt.Items = append(receipt.Items, entities.GoodsReceiptItem{
+				PurchaseOrderItemID: item.ID,
+				ProductID:           item.ProductID,
+				Quantity:            reqItem.Quantity,
+				UnitCost:            unitCost,
+				PreviousHargaModal:  previousCost,
+				NewHargaModal:       newCost,
+			})
+		}
+
+		if err := tx.Create(receipt).Error; err != nil {
+			return fmt.Errorf("failed to create goods receipt: %w", err)
+		}
+
+		status := entities.PurchaseOrderStatusReceived
+		for _, item := range order.Items {
+			if item.OutstandingQty() > 0 {
+				status = entities.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+
+		if err := tx.Model(order).Update("status", status).Error; err != nil {
+			return fmt.Errorf("failed to update purchase order status: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to receive goods", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.purchaseOrderRepo.GetByID(ctx, id)
+}
+
+// CancelPurchaseOrder closes a purchase order; anything not yet received is no longer expected
+func (s *purchaseOrderService) CancelPurchaseOrder(ctx context.Context, id uint) (*entities.PurchaseOrder, error) {
+	s.logger.InfoContext(ctx, "cancelling purchase order", "id", id)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOpenPurchaseOrder(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(order).Update("status", entities.PurchaseOrderStatusCancelled).Error; err != nil {
+			return fmt.Errorf("failed to cancel purchase order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to cancel purchase order", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.purchaseOrderRepo.GetByID(ctx, id)
+}
+
+// GetOutstandingReport retrieves ordered but not yet received stock per supplier
+func (s *purchaseOrderService) GetOutstandingReport(ctx context.Context) ([]interfaces.OutstandingPurchaseOrders, error) {
+	s.logger.InfoContext(ctx, "getting outstanding purchase orders report")
+
+	results, err := s.purchaseOrderRepo.GetOutstandingBySupplier(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding purchase orders: %w", err)
+	}
+
+	return results, nil
+}
+
+// lockOpenPurchaseOrder locks a purchase order that can still receive goods, with its items
+func lockOpenPurchaseOrder(ctx context.Context, tx *gorm.DB, id uint) (*entities.PurchaseOrder, error) {
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	var order entities.PurchaseOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("purchase order not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	if order.Status != entities.PurchaseOrderStatusOpen && order.Status != entities.PurchaseOrderStatusPartiallyReceived {
+		return nil, fmt.Errorf("purchase order %d is %s: %w", id, order.Status, entities.ErrPurchaseOrderClosed)
+	}
+
+	return &order, nil
+}
+
+// weightedAverageCost values stock on hand and received goods together
+func weightedAverageCost(currentCost money.Money, stock int, unitCost money.Money, quantity int) money.Money {
+	if stock <= 0 {
+		return unitCost
+	}
+	return (currentCost.Mul(stock) + unitCost.Mul(quantity)).Div(stock + quantity)
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"github.com/usernamesalah/rh-pos/internal/repository"
+)
+
+func TestReceiveGoodsKeepsCostHistory(t *testing.T) {
+	db, logger := openTestDB(t)
+	svc := NewPurchaseOrderService(
+		repository.NewPurchaseOrderRepository(db, logger),
+		repository.NewSupplierRepository(db, logger),
+		repository.NewProductRepository(db, logger),
+		db,
+		logger,
+	)
+	ctx, tenantID := newTestTenant(t, db)
+	ctx = context.WithValue(ctx, "user_id", uint(1))
+
+	supplier := &entities.Supplier{Name: "supplier", TenantID: &tenantID}
+	if err := db.Create(supplier).Error; err != nil {
+		t.Fatalf("failed to create supplier: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		updateCost bool
+		wantCost   money.Money
+	}{
+		// 10 on hand at 1000 and 10 received at 2000 average to 1500
+		{"update cost", true, money.FromInt(1500)},
+		{"keep cost", false, money.FromInt(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createTestProduct(t, db, tenantID, "received", 10, money.FromInt(3000))
+			if err := db.Model(product).Update("harga_modal", money.FromInt(1000)).Error; err != nil {
+				t.Fatalf("failed to set cost: %v", err)
+			}
+
+			order, err := svc.CreatePurchaseOrder(ctx, interfaces.CreatePurchaseOrderRequest{
+				SupplierID: supplier.ID,
+				Items:      []interfaces.PurchaseOrderItemRequest{{ProductID: product.ID, Quantity: 10, UnitCost: money.FromInt(2000)}},
+			})
+			if err != nil {
+				t.Fatalf("failed to create purchase order: %v", err)
+			}
+
+			if _, err := svc.ReceiveGoods(ctx, order.ID, interfaces.ReceiveGoodsRequest{
+				UpdateCost: tt.updateCost,
+				Items:      []interfaces.ReceiveItemRequest{{PurchaseOrderItemID: order.Items[0].ID, Quantity: 10}},
+			}); err != nil {
+				t.Fatalf("failed to receive goods: %v", err)
+			}
+
+			var item entities.GoodsReceiptItem
+			if err := db.Where("product_id = ?", product.ID).First(&item).Error; err != nil {
+				t.Fatalf("failed to get receipt item: %v", err)
+			}
+			if item.PreviousHargaModal != money.FromInt(1000) {
+				t.Errorf("previous cost = %s, want 1000.00", item.PreviousHargaModal)
+			}
+			if item.NewHargaModal != tt.wantCost {
+				t.Errorf("new cost = %s, want %s", item.NewHargaModal, tt.wantCost)
+			}
+		})
+	}
+}
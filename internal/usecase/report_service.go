@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 )
 
 type reportService struct {
@@ -26,33 +28,100 @@ func NewReportService(transactionRepo interfaces.TransactionRepository, logger *
 func (s *reportService) GetSalesReport(ctx context.Context, startDate, endDate time.Time) (*interfaces.ReportResponse, error) {
 	s.logger.InfoContext(ctx, "generating sales report", "start_date", startDate, "end_date", endDate)
 
+	if err := authorize(ctx, entities.PermReportsView); err != nil {
+		return nil, err
+	}
+
 	// Get report data from repository
 	details, err := s.transactionRepo.GetReportData(ctx, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get report data: %w", err)
 	}
 
+	payments, err := s.transactionRepo.GetPaymentBreakdown(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment breakdown: %w", err)
+	}
+
+	modifiers, err := s.transactionRepo.GetModifierBreakdown(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifier breakdown: %w", err)
+	}
+
+	taxes, err := s.transactionRepo.GetTaxSummary(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax summary: %w", err)
+	}
+
+	promotions, err := s.transactionRepo.GetPromotionBreakdown(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion breakdown: %w", err)
+	}
+
 	// Calculate aggregated metrics
-	var totalRevenue float64
+	var totalRevenue, totalCost money.Money
 	var itemsSold int
 
 	for _, detail := range details {
 		totalRevenue += detail.TotalPrice
+		totalCost += detail.TotalCost
 		itemsSold += detail.Total
 	}
 
+	var totalTax, totalServiceCharge money.Money
+	for _, t := range taxes {
+		totalTax += t.Tax
+		totalServiceCharge += t.ServiceCharge
+	}
+
 	// Calculate average transaction value
-	var averageTransaction float64
+	var averageTransaction money.Money
 	if len(details) > 0 {
-		averageTransaction = totalRevenue / float64(len(details))
+		averageTransaction = totalRevenue.Div(len(details))
 	}
 
 	response := &interfaces.ReportResponse{
 		TotalRevenue:       totalRevenue,
+		TotalCost:          totalCost,
+		GrossMargin:        totalRevenue - totalCost,
+		TotalTax:           totalTax,
+		TotalServiceCharge: totalServiceCharge,
 		ItemsSold:          itemsSold,
 		AverageTransaction: averageTransaction,
 		Details:            details,
+		Products:           rollUpVariants(details),
+		Payments:           payments,
+		Modifiers:          modifiers,
+		Taxes:              taxes,
+		Promotions:         promotions,
 	}
 
 	return response, nil
 }
+
+// rollUpVariants merges variant rows into one row per parent product, keeping the order of first appearance
+func rollUpVariants(details []interfaces.ReportDetail) []interfaces.ReportDetail {
+	rolled := make([]interfaces.ReportDetail, 0, len(details))
+	index := make(map[uint]int, len(details))
+	for _, detail := range details {
+		if detail.ParentProductID != nil {
+			detail = interfaces.ReportDetail{
+				ProductID:   *detail.ParentProductID,
+				ProductName: detail.ParentProductName,
+				Total:       detail.Total,
+				TotalPrice:  detail.TotalPrice,
+				TotalCost:   detail.TotalCost,
+			}
+		}
+
+		if i, ok := index[detail.ProductID]; ok {
+			rolled[i].Total += detail.Total
+			rolled[i].TotalPrice += detail.TotalPrice
+			rolled[i].TotalCost += detail.TotalCost
+			continue
+		}
+		index[detail.ProductID] = len(rolled)
+		rolled = append(rolled, detail)
+	}
+	return rolled
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+)
+
+// roleNamePattern limits custom role names to what fits users.role and reads well in tokens
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// builtInRoleDescriptions describes the built-in roles in role listings
+var builtInRoleDescriptions = map[string]string{
+	entities.RoleOwner:      "Full access, including users and roles",
+	entities.RoleManager:    "Runs the store: catalogue, pricing, stock, purchasing, voids, reports, promotions and terminals",
+	entities.RoleCashier:    "Rings up sales and open orders",
+	entities.RoleStockClerk: "Stock adjustments, stock opname, suppliers and purchase orders",
+}
+
+type roleService struct {
+	roleRepo interfaces.RoleRepository
+	logger   *slog.Logger
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo interfaces.RoleRepository, logger *slog.Logger) interfaces.RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		logger:   logger,
+	}
+}
+
+// ListRoles lists the built-in roles followed by the tenant's custom roles
+func (s *roleService) ListRoles(ctx context.Context) ([]interfaces.RoleSummary, error) {
+	s.logger.InfoContext(ctx, "listing roles")
+
+	if err := authorize(ctx, entities.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	custom, err := s.roleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	roles := make([]interfaces.RoleSummary, 0, len(entities.BuiltInRoles)+len(custom))
+	for _, name := range []string{entities.RoleOwner, entities.RoleManager, entities.RoleCashier, entities.RoleStockClerk} {
+		roles = append(roles, interfaces.RoleSummary{
+			Name:        name,
+			Description: builtInRoleDescriptions[name],
+			BuiltIn:     true,
+			Permissions: entities.BuiltInRoles[name],
+		})
+	}
+	for _, role := range custom {
+		id := role.ID
+		roles = append(roles, interfaces.RoleSummary{
+			ID:          &id,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.PermissionNames(),
+		})
+	}
+
+	return roles, nil
+}
+
+// GetRole retrieves a custom role by ID
+func (s *roleService) GetRole(ctx context.Context, id uint) (*entities.Role, error) {
+	s.logger.InfoContext(ctx, "getting role", "id", id)
+
+	if err := authorize(ctx, entities.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+// CreateRole creates a custom role for the current tenant
+func (s *roleService) CreateRole(ctx context.Context, req interfaces.RoleRequest) (*entities.Role, error) {
+	s.logger.InfoContext(ctx, "creating role", "name", req.Name)
+
+	if err := authorize(ctx, entities.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	role := &entities.Role{TenantID: &tenantID}
+	if err := s.applyRequest(ctx, role, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return role, nil
+}
+
+// UpdateRole updates a custom role and replaces its permissions
+func (s *roleService) UpdateRole(ctx context.Context, id uint, req interfaces.RoleRequest) (*entities.Role, error) {
+	s.logger.InfoContext(ctx, "updating role", "id", id)
+
+	if err := authorize(ctx, entities.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	if err := s.applyRequest(ctx, role, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return role, nil
+}
+
+// DeleteRole deletes a custom role that no user holds
+func (s *roleService) DeleteRole(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting role", "id", id)
+
+	if err := authorize(ctx, entities.PermRolesManage); err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get role: %w", err)
+	}
+
+	users, err := s.roleRepo.CountUsers(ctx, role.Name)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if users > 0 {
+		return entities.ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+// ResolvePermissions returns the permissions granted to a built-in or custom role.
+// Unknown roles resolve to no permissions.
+func (s *roleService) ResolvePermissions(ctx context.Context, role string) (entities.PermissionSet, error) {
+	if permissions, ok := entities.BuiltInRoles[role]; ok {
+		return entities.NewPermissionSet(permissions), nil
+	}
+
+	custom, err := s.roleRepo.GetByName(ctx, role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WarnContext(ctx, "unknown role", "role", role)
+			return entities.PermissionSet{}, nil
+		}
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	return entities.NewPermissionSet(custom.PermissionNames()), nil
+}
+
+// applyRequest validates a role request and copies it onto role.
+// Every permission in the request must be held by the caller.
+func (s *roleService) applyRequest(ctx context.Context, role *entities.Role, req interfaces.RoleRequest) error {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !roleNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: name must be lowercase letters, digits and underscores", entities.ErrInvalidRole)
+	}
+	if _, ok := entities.BuiltInRoles[name]; ok {
+		return entities.ErrRoleNameTaken
+	}
+	if name != role.Name {
+		existing, err := s.roleRepo.GetByName(ctx, name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check role name: %w", err)
+		}
+		if existing != nil {
+			return entities.ErrRoleNameTaken
+		}
+	}
+
+	if len(req.Permissions) == 0 {
+		return fmt.Errorf("%w: at least one permission is required", entities.ErrInvalidRole)
+	}
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, p := range req.Permissions {
+		if !slices.Contains(entities.AllPermissions, p) {
+			return fmt.Errorf("%w: unknown permission %q", entities.ErrInvalidRole, p)
+		}
+		// A role cannot grant permissions the caller lacks, the same rule as assigning one
+		if err := authorize(ctx, p); err != nil {
+			return fmt.Errorf("%w: cannot grant permission %q", err, p)
+		}
+		if !slices.Contains(permissions, p) {
+			permissions = append(permissions, p)
+		}
+	}
+	sort.Strings(permissions)
+
+	role.Name = name
+	role.Description = strings.TrimSpace(req.Description)
+	role.Permissions = make([]entities.RolePermission, len(permissions))
+	for i, p := range permissions {
+		role.Permissions[i] = entities.RolePermission{RoleID: role.ID, Permission: p}
+	}
+
+	return nil
+}
+
+// authorize checks that the caller's role grants permission. The permissions are
+// resolved once per request by the API middleware and carried in the context.
+func authorize(ctx context.Context, permission string) error {
+	permissions, _ := ctx.Value("permissions").(entities.PermissionSet)
+	if !permissions.Has(permission) {
+		return entities.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type shiftService struct {
+	shiftRepo interfaces.ShiftRepository
+	db        *gorm.DB
+	logger    *slog.Logger
+}
+
+// NewShiftService creates a new shift service
+func NewShiftService(shiftRepo interfaces.ShiftRepository, db *gorm.DB, logger *slog.Logger) interfaces.ShiftService {
+	return &shiftService{
+		shiftRepo: shiftRepo,
+		db:        db,
+		logger:    logger,
+	}
+}
+
+// OpenShift starts a shift for the logged-in user with the cash float put in the drawer
+func (s *shiftService) OpenShift(ctx context.Context, req interfaces.OpenShiftRequest) (*entities.Shift, error) {
+	s.logger.InfoContext(ctx, "opening shift", "opening_float", req.OpeningFloat)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	if req.OpeningFloat < 0 {
+		return nil, fmt.Errorf("opening float cannot be negative")
+	}
+
+	shift := &entities.Shift{
+		UserID:       userID,
+		Status:       entities.ShiftStatusOpen,
+		OpeningFloat: req.OpeningFloat,
+		Notes:        req.Notes,
+		TenantID:     &tenantID,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The user row serializes shift opening so a user never has two open shifts
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&entities.User{}).Error; err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		var open int64
+		if err := tx.Model(&entities.Shift{}).Where("user_id = ? AND status = ? AND tenant_id = ?", userID, entities.ShiftStatusOpen, tenantID).Count(&open).Error; err != nil {
+			return fmt.Errorf("failed to check open shifts: %w", err)
+		}
+		if open > 0 {
+			return entities.ErrShiftAlreadyOpen
+		}
+
+		if err := tx.Create(shift).Error; err != nil {
+			return fmt.Errorf("failed to open shift: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to open shift", "error", err)
+		return nil, err
+	}
+
+	return s.shiftRepo.GetByID(ctx, shift.ID)
+}
+
+// GetCurrentShift retrieves the open shift of the logged-in user
+func (s *shiftService) GetCurrentShift(ctx context.Context) (*entities.Shift, error) {
+	s.logger.InfoContext(ctx, "getting current shift")
+
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	shift, err := s.shiftRepo.GetOpenByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current shift: %w", err)
+	}
+
+	return shift, nil
+}
+
+// GetShift retrieves a shift with its cash events and closing counts
+func (s *shiftService) GetShift(ctx context.Context, id uint) (*entities.Shift, error) {
+	s.logger.InfoContext(ctx, "getting shift", "id", id)
+
+	shift, err := s.shiftRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+
+	if err := authorizeShift(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// ListShifts retrieves shifts with pagination
+func (s *shiftService) ListShifts(ctx context.Context, page, limit int) ([]entities.Shift, int64, error) {
+	s.logger.InfoContext(ctx, "listing shifts", "page", page, "limit", limit)
+
+	if err := authorize(ctx, entities.PermShiftsManage); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	shifts, total, err := s.shiftRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list shifts: %w", err)
+	}
+
+	return shifts, total, nil
+}
+
+// RecordCashEvent records cash put into or taken out of the drawer of an open shift
+func (s *shiftService) RecordCashEvent(ctx context.Context, id uint, req interfaces.CashEventRequest) (*entities.Shift, error) {
+	s.logger.InfoContext(ctx, "recording cash event", "id", id, "type", req.Type, "amount", req.Amount)
+
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	if req.Type != entities.ShiftCashIn && req.Type != entities.ShiftCashOut {
+		return nil, fmt.Errorf("cash event type must be %s or %s", entities.ShiftCashIn, entities.ShiftCashOut)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("cash event amount must be greater than zero")
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("cash event reason is required")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenShift(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entities.ShiftCashEvent{
+			ShiftID: shift.ID,
+			Type:    req.Type,
+			Amount:  req.Amount,
+			Reason:  req.Reason,
+			UserID:  userID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record cash event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to record cash event", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.shiftRepo.GetByID(ctx, id)
+}
+
+// CloseShift records the closing count and snapshots the expected cash and its difference.
+// Sales in progress on the shift finish before it closes.
+func (s *shiftService) CloseShift(ctx context.Context, id uint, req interfaces.CloseShiftRequest) (*entities.Shift, error) {
+	s.logger.InfoContext(ctx, "closing shift", "id", id, "counted_cash", req.CountedCash)
+
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	if req.CountedCash < 0 {
+		return nil, fmt.Errorf("counted cash cannot be negative")
+	}
+	counts := []entities.ShiftCount{{Method: entities.PaymentMethodCash, Amount: req.CountedCash}}
+	for _, c := range req.Counts {
+		if c.Method == "" {
+			return nil, fmt.Errorf("count method is required")
+		}
+		if c.Amount < 0 {
+			return nil, fmt.Errorf("counted amount cannot be negative")
+		}
+		for _, existing := range counts {
+			if existing.Method == c.Method {
+				return nil, fmt.Errorf("%s is counted more than once", c.Method)
+			}
+		}
+		counts = append(counts, entities.ShiftCount{Method: c.Method, Amount: c.Amount})
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenShift(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		shift.Counts = counts
+
+		tenders, err := s.shiftTenders(ctx, shift)
+		if err != nil {
+			return err
+		}
+		cash := tenders[0]
+
+		for i := range counts {
+			counts[i].ShiftID = shift.ID
+		}
+		if err := tx.Create(&counts).Error; err != nil {
+			return fmt.Errorf("failed to record shift counts: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"status":          entities.ShiftStatusClosed,
+			"expected_cash":   cash.Expected,
+			"counted_cash":    req.CountedCash,
+			"cash_difference": req.CountedCash - cash.Expected,
+			"closed_by":       userID,
+			"closed_at":       time.Now(),
+		}
+		if req.Notes != "" {
+			updates["notes"] = req.Notes
+		}
+		if err := tx.Model(shift).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to close shift: %w", err)
+		}
+
+		s.logger.InfoContext(ctx, "shift closed", "id", shift.ID, "expected_cash", cash.Expected, "counted_cash", req.CountedCash)
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to close shift", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.shiftRepo.GetByID(ctx, id)
+}
+
+// GetShiftReport compares what each tender of a shift should hold with what was counted.
+// Open shifts report what is expected so far.
+func (s *shiftService) GetShiftReport(ctx context.Context, id uint) (*interfaces.ShiftReport, error) {
+	s.logger.InfoContext(ctx, "getting shift report", "id", id)
+
+	shift, err := s.shiftRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+
+	if err := authorizeShift(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	tenders, err := s.shiftTenders(ctx, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	return &interfaces.ShiftReport{
+		Shift:   shift,
+		Tenders: tenders,
+	}, nil
+}
+
+// shiftTenders works out the expected amount of every tender a shift used, cash first
+func (s *shiftService) shiftTenders(ctx context.Context, shift *entities.Shift) ([]interfaces.ShiftTenderReport, error) {
+	sales, err := s.shiftRepo.GetTenderSales(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift sales: %w", err)
+	}
+	refunds, err := s.shiftRepo.GetTenderRefunds(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift refunds: %w", err)
+	}
+
+	tenders := map[string]*interfaces.ShiftTenderReport{
+		entities.PaymentMethodCash: {Method: entities.PaymentMethodCash, Float: shift.OpeningFloat},
+	}
+	tender := func(method string) *interfaces.ShiftTenderReport {
+		t, ok := tenders[method]
+		if !ok {
+			t = &interfaces.ShiftTenderReport{Method: method}
+			tenders[method] = t
+		}
+		return t
+	}
+
+	for _, total := range sales {
+		t := tender(total.Method)
+		t.Sales += total.Amount
+		t.Transactions += total.Transactions
+	}
+	for _, total := range refunds {
+		tender(total.Method).Refunds += total.Amount
+	}
+	cash := tenders[entities.PaymentMethodCash]
+	for _, e := range shift.CashEvents {
+		if e.Type == entities.ShiftCashIn {
+			cash.CashIn += e.Amount
+		} else {
+			cash.CashOut += e.Amount
+		}
+	}
+
+	for _, t := range tenders {
+		t.Expected = t.Float + t.Sales - t.Refunds + t.CashIn - t.CashOut
+	}
+	for _, c := range shift.Counts {
+		t := tender(c.Method)
+		counted := c.Amount
+		difference := counted - t.Expected
+		t.Counted = &counted
+		t.Difference = &difference
+	}
+
+	result := make([]interfaces.ShiftTenderReport, 0, len(tenders))
+	for _, t := range tenders {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Method == entities.PaymentMethodCash) != (result[j].Method == entities.PaymentMethodCash) {
+			return result[i].Method == entities.PaymentMethodCash
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result, nil
+}
+
+// authorizeShift lets cashiers work on their own shifts; other shifts need shifts.manage
+func authorizeShift(ctx context.Context, shift *entities.Shift) error {
+	if userID, ok := ctx.Value("user_id").(uint); ok && shift.UserID == userID {
+		return nil
+	}
+	return authorize(ctx, entities.PermShiftsManage)
+}
+
+// lockOpenShift locks an open shift row with its cash events so drawer changes and closing are serialized
+func lockOpenShift(ctx context.Context, tx *gorm.DB, id uint) (*entities.Shift, error) {
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	var shift entities.Shift
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("CashEvents").Where("id = ? AND tenant_id = ?", id, tenantID).First(&shift).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("shift not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+
+	if err := authorizeShift(ctx, &shift); err != nil {
+		return nil, err
+	}
+
+	if shift.Status != entities.ShiftStatusOpen {
+		return nil, fmt.Errorf("shift %d: %w", id, entities.ErrShiftClosed)
+	}
+
+	return &shift, nil
+}
+
+// activeShiftID returns the open shift of the logged-in user, or nil when they have none.
+// The shift row is share-locked until tx ends so it cannot close while the sale or refund is being recorded.
+func activeShiftID(ctx context.Context, tx *gorm.DB, tenantID uint) (*uint, error) {
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, nil
+	}
+
+	var shift entities.Shift
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+		Where("user_id = ? AND status = ? AND tenant_id = ?", userID, entities.ShiftStatusOpen, tenantID).
+		First(&shift).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open shift: %w", err)
+	}
+	return &shift.ID, nil
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"gorm.io/gorm"
+)
+
+// adjustStock applies movement.Delta to the product's stock on tx and appends the movement to the ledger.
+// A negative delta larger than the current stock fails with ErrInsufficientStock.
+// Every path that changes products.stock must go through here.
+func adjustStock(ctx context.Context, tx *gorm.DB, movement *entities.StockMovement) error {
+	if movement.Delta == 0 {
+		return nil
+	}
+
+	query := tx.Model(&entities.Product{}).Where("id = ? AND tenant_id = ?", movement.ProductID, movement.TenantID)
+	if movement.Delta < 0 {
+		query = query.Where("stock >= ?", -movement.Delta)
+	}
+
+	result := query.Update("stock", gorm.Expr("stock + ?", movement.Delta))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update product stock: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if movement.Delta < 0 {
+			return fmt.Errorf("product %d: %w", movement.ProductID, entities.ErrInsufficientStock)
+		}
+		return fmt.Errorf("product %d: %w", movement.ProductID, gorm.ErrRecordNotFound)
+	}
+
+	if err := tx.Model(&entities.Product{}).Select("stock").Where("id = ?", movement.ProductID).Scan(&movement.Balance).Error; err != nil {
+		return fmt.Errorf("failed to read product stock: %w", err)
+	}
+
+	if movement.UserID == nil {
+		if userID, ok := ctx.Value("user_id").(uint); ok {
+			movement.UserID = &userID
+		}
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+
+	return nil
+}
+
+// rejectVariantParents fails with ErrProductHasVariants when any of ids is a parent product.
+// Parents hold no stock of their own; it is sold, counted and received through their variants.
+func rejectVariantParents(tx *gorm.DB, ids []uint) error {
+	var parents []uint
+	if err := tx.Model(&entities.Product{}).Where("parent_id IN ?", ids).Distinct().Pluck("parent_id", &parents).Error; err != nil {
+		return fmt.Errorf("failed to check product variants: %w", err)
+	}
+	if len(parents) > 0 {
+		return fmt.Errorf("product %d: %w", parents[0], entities.ErrProductHasVariants)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/hash"
+	"github.com/usernamesalah/rh-pos/internal/pkg/notifier"
+)
+
+// stockAlertTimeout bounds a background threshold check and its notification
+const stockAlertTimeout = 30 * time.Second
+
+type stockAlertService struct {
+	productRepo interfaces.ProductRepository
+	notifier    notifier.Notifier
+	logger      *slog.Logger
+}
+
+// NewStockAlertService creates a new stock alert service
+func NewStockAlertService(productRepo interfaces.ProductRepository, alertNotifier notifier.Notifier, logger *slog.Logger) interfaces.StockAlertService {
+	return &stockAlertService{
+		productRepo: productRepo,
+		notifier:    alertNotifier,
+		logger:      logger,
+	}
+}
+
+// CheckAfterSale evaluates sold products in a goroutine so the sale is never delayed by a notifier.
+// Only products whose stock crossed the reorder point with this sale are reported, so each drop alerts once.
+func (s *stockAlertService) CheckAfterSale(ctx context.Context, sold map[uint]int) {
+	if len(sold) == 0 {
+		return
+	}
+
+	// Keep tenant and user values but outlive the request
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), stockAlertTimeout)
+
+	go func() {
+		defer cancel()
+		s.check(ctx, sold)
+	}()
+}
+
+func (s *stockAlertService) check(ctx context.Context, sold map[uint]int) {
+	tenantID, _ := ctx.Value("tenant_id").(uint)
+
+	ids := make([]uint, 0, len(sold))
+	for id := range sold {
+		ids = append(ids, id)
+	}
+
+	products, err := s.productRepo.GetLowStockByIDs(ctx, ids)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to evaluate reorder points", "error", err)
+		return
+	}
+
+	var alerts []notifier.LowStockAlert
+	for _, p := range products {
+		if p.Stock+sold[p.ID] <= p.ReorderPoint {
+			continue
+		}
+		alerts = append(alerts, notifier.LowStockAlert{
+			TenantID:     tenantID,
+			ProductID:    hash.HashID(p.ID),
+			ProductName:  p.Name,
+			SKU:          p.SKU,
+			Stock:        p.Stock,
+			ReorderPoint: p.ReorderPoint,
+			ReorderQty:   p.ReorderQty,
+		})
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	if err := s.notifier.NotifyLowStock(ctx, alerts); err != nil {
+		s.logger.ErrorContext(ctx, "failed to send low stock alerts", "error", err, "alerts", len(alerts))
+	}
+}
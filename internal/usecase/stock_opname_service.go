@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockOpnameService struct {
+	opnameRepo interfaces.StockOpnameRepository
+	db         *gorm.DB
+	logger     *slog.Logger
+}
+
+// NewStockOpnameService creates a new stock opname service
+func NewStockOpnameService(opnameRepo interfaces.StockOpnameRepository, db *gorm.DB, logger *slog.Logger) interfaces.StockOpnameService {
+	return &stockOpnameService{
+		opnameRepo: opnameRepo,
+		db:         db,
+		logger:     logger,
+	}
+}
+
+// OpenSession starts a new count session for the current tenant
+func (s *stockOpnameService) OpenSession(ctx context.Context, notes string) (*entities.StockOpname, error) {
+	s.logger.InfoContext(ctx, "opening stock opname")
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	opname := &entities.StockOpname{
+		Status:   entities.StockOpnameStatusOpen,
+		Notes:    notes,
+		OpenedBy: userID,
+		TenantID: &tenantID,
+	}
+	if err := s.opnameRepo.Create(ctx, opname); err != nil {
+		return nil, fmt.Errorf("failed to open stock opname: %w", err)
+	}
+
+	return opname, nil
+}
+
+// GetSession retrieves a count session with its items
+func (s *stockOpnameService) GetSession(ctx context.Context, id uint) (*entities.StockOpname, error) {
+	s.logger.InfoContext(ctx, "getting stock opname", "id", id)
+
+	opname, err := s.opnameRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock opname: %w", err)
+	}
+
+	return opname, nil
+}
+
+// ListSessions retrieves count sessions with pagination
+func (s *stockOpnameService) ListSessions(ctx context.Context, page, limit int) ([]entities.StockOpname, int64, error) {
+	s.logger.InfoContext(ctx, "listing stock opnames", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	opnames, total, err := s.opnameRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list stock opnames: %w", err)
+	}
+
+	return opnames, total, nil
+}
+
+// RecordCounts adds (or with Replace, sets) counted quantities. Several devices can count the same session.
+func (s *stockOpnameService) RecordCounts(ctx context.Context, id uint, req interfaces.RecordStockCountRequest) (*entities.StockOpname, error) {
+	s.logger.InfoContext(ctx, "recording stock counts", "id", id, "items", len(req.Items), "replace", req.Replace)
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("at least one count is required")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		opname, err := lockOpenSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range req.Items {
+			if item.Quantity < 0 {
+				return fmt.Errorf("counted quantity cannot be negative")
+			}
+
+			var count int64
+			if err := tx.Model(&entities.Product{}).Where("id = ? AND tenant_id = ?", item.ProductID, opname.TenantID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+			if count == 0 {
+				return fmt.Errorf("product %d: %w", item.ProductID, gorm.ErrRecordNotFound)
+			}
+			if err := rejectVariantParents(tx, []uint{item.ProductID}); err != nil {
+				return err
+			}
+
+			counted := gorm.Expr("counted_qty + ?", item.Quantity)
+			if req.Replace {
+				counted = gorm.Expr("?", item.Quantity)
+			}
+
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "opname_id"}, {Name: "product_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"counted_qty": counted}),
+			}).Create(&entities.StockOpnameItem{
+				OpnameID:   opname.ID,
+				ProductID:  item.ProductID,
+				CountedQty: item.Quantity,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to record count: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to record stock counts", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.opnameRepo.GetByID(ctx, id)
+}
+
+// GetVariance compares counted quantities with system stock valued at HargaModal.
+// Open sessions use the current stock; committed sessions use the snapshot taken at commit.
+func (s *stockOpnameService) GetVariance(ctx context.Context, id uint) (*interfaces.StockOpnameVariance, error) {
+	s.logger.InfoContext(ctx, "getting stock opname variance", "id", id)
+
+	opname, err := s.opnameRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock opname: %w", err)
+	}
+
+	variance := &interfaces.StockOpnameVariance{
+		OpnameID: opname.ID,
+		Status:   opname.Status,
+		Items:    make([]interfaces.StockOpnameVarianceItem, 0, len(opname.Items)),
+	}
+
+	for _, item := range opname.Items {
+		if opname.Status == entities.StockOpnameStatusOpen {
+			item.SystemQty = item.Product.Stock
+			item.Variance = item.CountedQty - item.SystemQty
+			item.VarianceValue = item.Product.HargaModal.Mul(item.Variance)
+		}
+
+		variance.Items = append(variance.Items, interfaces.StockOpnameVarianceItem{
+			ProductID:     item.ProductID,
+			ProductName:   item.Product.Name,
+			SKU:           item.Product.SKU,
+			SystemQty:     item.SystemQty,
+			CountedQty:    item.CountedQty,
+			Variance:      item.Variance,
+			HargaModal:    item.Product.HargaModal,
+			VarianceValue: item.VarianceValue,
+		})
+		variance.TotalVariance += item.Variance
+		variance.TotalVarianceValue += item.VarianceValue
+	}
+
+	return variance, nil
+}
+
+// CommitSession posts the variance of every counted product as stock adjustments in one DB transaction
+func (s *stockOpnameService) CommitSession(ctx context.Context, id uint) (*entities.StockOpname, error) {
+	s.logger.InfoContext(ctx, "committing stock opname", "id", id)
+
+	userID, ok := ctx.Value("user_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("user_id not found in context")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		opname, err := lockOpenSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		var items []entities.StockOpnameItem
+		if err := tx.Where("opname_id = ?", opname.ID).Order("product_id").Find(&items).Error; err != nil {
+			return fmt.Errorf("failed to get counted items: %w", err)
+		}
+
+		for _, item := range items {
+			var product entities.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND tenant_id = ?", item.ProductID, opname.TenantID).
+				First(&product).Error; err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+
+			variance := item.CountedQty - product.Stock
+			if err := adjustStock(ctx, tx, &entities.StockMovement{
+				ProductID:   item.ProductID,
+				Delta:       variance,
+				Reason:      entities.StockReasonStockOpname,
+				ReferenceID: &opname.ID,
+				TenantID:    opname.TenantID,
+			}); err != nil {
+				return err
+			}
+
+			if err := tx.Model(&item).Updates(map[string]interface{}{
+				"system_qty":     product.Stock,
+				"variance":       variance,
+				"variance_value": product.HargaModal.Mul(variance),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to snapshot variance: %w", err)
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(opname).Updates(map[string]interface{}{
+			"status":       entities.StockOpnameStatusCommitted,
+			"committed_by": userID,
+			"committed_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to commit stock opname: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to commit stock opname", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.opnameRepo.GetByID(ctx, id)
+}
+
+// CancelSession discards an open session without touching stock
+func (s *stockOpnameService) CancelSession(ctx context.Context, id uint) (*entities.StockOpname, error) {
+	s.logger.InfoContext(ctx, "cancelling stock opname", "id", id)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		opname, err := lockOpenSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(opname).Update("status", entities.StockOpnameStatusCancelled).Error; err != nil {
+			return fmt.Errorf("failed to cancel stock opname: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to cancel stock opname", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.opnameRepo.GetByID(ctx, id)
+}
+
+// lockOpenSession locks an open session row so counts and commits are serialized
+func lockOpenSession(ctx context.Context, tx *gorm.DB, id uint) (*entities.StockOpname, error) {
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	var opname entities.StockOpname
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&opname).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("stock opname not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get stock opname: %w", err)
+	}
+
+	if opname.Status != entities.StockOpnameStatusOpen {
+		return nil, fmt.Errorf("stock opname %d is %s: %w", id, opname.Status, entities.ErrStockOpnameClosed)
+	}
+
+	return &opname, nil
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+)
+
+type supplierService struct {
+	supplierRepo interfaces.SupplierRepository
+	logger       *slog.Logger
+}
+
+// NewSupplierService creates a new supplier service
+func NewSupplierService(supplierRepo interfaces.SupplierRepository, logger *slog.Logger) interfaces.SupplierService {
+	return &supplierService{
+		supplierRepo: supplierRepo,
+		logger:       logger,
+	}
+}
+
+// CreateSupplier creates a new supplier for the current tenant
+func (s *supplierService) CreateSupplier(ctx context.Context, supplier *entities.Supplier) error {
+	s.logger.InfoContext(ctx, "creating supplier", "name", supplier.Name)
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return fmt.Errorf("tenant_id not found in context")
+	}
+	supplier.TenantID = &tenantID
+
+	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+		return fmt.Errorf("failed to create supplier: %w", err)
+	}
+
+	return nil
+}
+
+// GetSupplier retrieves a supplier by ID
+func (s *supplierService) GetSupplier(ctx context.Context, id uint) (*entities.Supplier, error) {
+	s.logger.InfoContext(ctx, "getting supplier", "id", id)
+
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	return supplier, nil
+}
+
+// ListSuppliers retrieves suppliers with pagination
+func (s *supplierService) ListSuppliers(ctx context.Context, page, limit int) ([]entities.Supplier, int64, error) {
+	s.logger.InfoContext(ctx, "listing suppliers", "page", page, "limit", limit)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	suppliers, total, err := s.supplierRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list suppliers: %w", err)
+	}
+
+	return suppliers, total, nil
+}
+
+// UpdateSupplier updates a supplier with the provided fields
+func (s *supplierService) UpdateSupplier(ctx context.Context, id uint, updates map[string]interface{}) (*entities.Supplier, error) {
+	s.logger.InfoContext(ctx, "updating supplier", "id", id)
+
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	for field, value := range updates {
+		switch field {
+		case "name":
+			supplier.Name = value.(string)
+		case "contact_name":
+			supplier.ContactName = value.(string)
+		case "phone":
+			supplier.Phone = value.(string)
+		case "email":
+			supplier.Email = value.(string)
+		case "address":
+			supplier.Address = value.(string)
+		case "notes":
+			supplier.Notes = value.(string)
+		}
+	}
+
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		return nil, fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	return supplier, nil
+}
+
+// DeleteSupplier deletes a supplier
+func (s *supplierService) DeleteSupplier(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting supplier", "id", id)
+
+	if _, err := s.supplierRepo.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	if err := s.supplierRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+
+	return nil
+}
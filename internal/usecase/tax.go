@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+)
+
+// charges is the service charge and PPN on an amount after discount
+type charges struct {
+	ServiceCharge money.Money
+	TaxBase       money.Money // DPP: the taxable net amount plus its service charge
+	Tax           money.Money
+	Added         money.Money // service charge and tax charged on top of the amount
+}
+
+// computeCharges applies the transaction's service charge and PPN settings to amount.
+// Service charge is levied on the amount net of PPN and is itself taxable. With inclusive
+// pricing the PPN on the amount is already part of it, so only the service charge and its
+// PPN are added on top.
+func computeCharges(t *entities.Transaction, amount money.Money, taxable bool) charges {
+	taxable = taxable && t.TaxRate > 0
+
+	net := amount
+	if taxable && t.TaxInclusive {
+		net = amount.ExcludePercent(t.TaxRate)
+	}
+
+	c := charges{ServiceCharge: net.Percent(t.ServiceChargeRate)}
+	c.Added = c.ServiceCharge
+	if !taxable {
+		return c
+	}
+
+	c.TaxBase = net + c.ServiceCharge
+	if t.TaxInclusive {
+		serviceTax := c.ServiceCharge.Percent(t.TaxRate)
+		c.Tax = amount - net + serviceTax
+		c.Added += serviceTax
+	} else {
+		c.Tax = c.TaxBase.Percent(t.TaxRate)
+		c.Added += c.Tax
+	}
+	return c
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 )
 
 type tenantService struct {
@@ -25,6 +26,9 @@ func NewTenantService(tenantRepo interfaces.TenantRepository, logger *slog.Logge
 // CreateTenant creates a new tenant
 func (s *tenantService) CreateTenant(ctx context.Context, tenant *entities.Tenant) error {
 	s.logger.InfoContext(ctx, "creating tenant", "name", tenant.Name)
+	if err := validateTenantCharges(tenant); err != nil {
+		return err
+	}
 	if err := s.tenantRepo.Create(ctx, tenant); err != nil {
 		s.logger.ErrorContext(ctx, "failed to create tenant", "error", err)
 		return fmt.Errorf("failed to create tenant: %w", err)
@@ -57,6 +61,9 @@ func (s *tenantService) ListTenants(ctx context.Context) ([]*entities.Tenant, er
 // UpdateTenant updates a tenant
 func (s *tenantService) UpdateTenant(ctx context.Context, tenant *entities.Tenant) error {
 	s.logger.InfoContext(ctx, "updating tenant", "id", tenant.ID)
+	if err := validateTenantCharges(tenant); err != nil {
+		return err
+	}
 	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
 		s.logger.ErrorContext(ctx, "failed to update tenant", "error", err, "id", tenant.ID)
 		return fmt.Errorf("failed to update tenant: %w", err)
@@ -78,3 +85,25 @@ func (s *tenantService) DeleteTenant(ctx context.Context, id uint) error {
 func (s *tenantService) GetTenant(ctx context.Context, id uint) (*entities.Tenant, error) {
 	return s.GetTenantByID(ctx, id)
 }
+
+// validateTenantCharges checks that tax and service charge rates are valid percentages
+// and that total rounding uses a known mode and a non-negative increment.
+// An empty rounding mode defaults to nearest.
+func validateTenantCharges(tenant *entities.Tenant) error {
+	if tenant.RoundingMode == "" {
+		tenant.RoundingMode = money.RoundNearest
+	}
+	if !tenant.RoundingMode.Valid() {
+		return fmt.Errorf("rounding mode must be one of nearest, up or down")
+	}
+	if tenant.RoundingIncrement < 0 {
+		return fmt.Errorf("rounding increment must not be negative")
+	}
+	if tenant.TaxRate < 0 || tenant.TaxRate > 100 {
+		return fmt.Errorf("tax rate must be between 0 and 100")
+	}
+	if tenant.ServiceChargeRate < 0 || tenant.ServiceChargeRate > 100 {
+		return fmt.Errorf("service charge rate must be between 0 and 100")
+	}
+	return nil
+}
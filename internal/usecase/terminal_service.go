@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+)
+
+type terminalService struct {
+	terminalRepo interfaces.TerminalRepository
+	sessionRepo  interfaces.SessionRepository
+	logger       *slog.Logger
+}
+
+// NewTerminalService creates a new terminal service
+func NewTerminalService(terminalRepo interfaces.TerminalRepository, sessionRepo interfaces.SessionRepository, logger *slog.Logger) interfaces.TerminalService {
+	return &terminalService{
+		terminalRepo: terminalRepo,
+		sessionRepo:  sessionRepo,
+		logger:       logger,
+	}
+}
+
+// RegisterTerminal registers a shared till for the current tenant and returns its
+// device token. Only the token's hash is stored, so it is returned this once.
+func (s *terminalService) RegisterTerminal(ctx context.Context, name string) (*entities.Terminal, string, error) {
+	s.logger.InfoContext(ctx, "registering terminal", "name", name)
+
+	if err := authorize(ctx, entities.PermTerminalsManage); err != nil {
+		return nil, "", err
+	}
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, "", fmt.Errorf("tenant_id not found in context")
+	}
+
+	deviceToken, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	terminal := &entities.Terminal{
+		Name:      strings.TrimSpace(name),
+		TokenHash: hashToken(deviceToken),
+		TenantID:  &tenantID,
+	}
+	if err := s.terminalRepo.Create(ctx, terminal); err != nil {
+		return nil, "", fmt.Errorf("failed to register terminal: %w", err)
+	}
+
+	return terminal, deviceToken, nil
+}
+
+// ListTerminals lists the tenant's terminals, including revoked ones
+func (s *terminalService) ListTerminals(ctx context.Context) ([]entities.Terminal, error) {
+	s.logger.InfoContext(ctx, "listing terminals")
+
+	if err := authorize(ctx, entities.PermTerminalsManage); err != nil {
+		return nil, err
+	}
+
+	terminals, err := s.terminalRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terminals: %w", err)
+	}
+
+	return terminals, nil
+}
+
+// RevokeTerminal stops a terminal accepting PIN logins and ends the session signed in on it
+func (s *terminalService) RevokeTerminal(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "revoking terminal", "id", id)
+
+	if err := authorize(ctx, entities.PermTerminalsManage); err != nil {
+		return err
+	}
+
+	terminal, err := s.terminalRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get terminal: %w", err)
+	}
+
+	if terminal.RevokedAt == nil {
+		now := time.Now()
+		terminal.RevokedAt = &now
+		if err := s.terminalRepo.Update(ctx, terminal); err != nil {
+			return fmt.Errorf("failed to revoke terminal: %w", err)
+		}
+	}
+
+	if _, err := s.sessionRepo.RevokeAllForTerminal(ctx, terminal.ID); err != nil {
+		return fmt.Errorf("failed to end terminal sessions: %w", err)
+	}
+
+	return nil
+}
@@ -2,26 +2,37 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/usernamesalah/rh-pos/internal/domain/entities"
 	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type transactionService struct {
 	transactionRepo interfaces.TransactionRepository
 	productRepo     interfaces.ProductRepository
+	barcodeRepo     interfaces.ProductBarcodeRepository
+	tenantRepo      interfaces.TenantRepository
+	stockAlerts     interfaces.StockAlertService
 	db              *gorm.DB
 	logger          *slog.Logger
 }
 
 // NewTransactionService creates a new transaction service
-func NewTransactionService(transactionRepo interfaces.TransactionRepository, productRepo interfaces.ProductRepository, db *gorm.DB, logger *slog.Logger) interfaces.TransactionService {
+func NewTransactionService(transactionRepo interfaces.TransactionRepository, productRepo interfaces.ProductRepository, barcodeRepo interfaces.ProductBarcodeRepository, tenantRepo interfaces.TenantRepository, stockAlerts interfaces.StockAlertService, db *gorm.DB, logger *slog.Logger) interfaces.TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
 		productRepo:     productRepo,
+		barcodeRepo:     barcodeRepo,
+		tenantRepo:      tenantRepo,
+		stockAlerts:     stockAlerts,
 		db:              db,
 		logger:          logger,
 	}
@@ -36,10 +47,16 @@ func (s *transactionService) CreateTransaction(ctx context.Context, req interfac
 		return nil, fmt.Errorf("transaction must have at least one item")
 	}
 
+	items, err := s.resolveBarcodes(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	req.Items = items
+
 	var createdTransaction *entities.Transaction
 
 	// Use database transaction to ensure data consistency
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Get tenant_id from context
 		tenantID, ok := ctx.Value("tenant_id").(uint)
 		if !ok {
@@ -52,75 +69,575 @@ func (s *transactionService) CreateTransaction(ctx context.Context, req interfac
 			PaymentMethod: req.PaymentMethod,
 			Discount:      req.Discount,
 			Notes:         req.Notes,
+			CustomerPhone: normalizePhone(req.CustomerPhone),
 			TenantID:      &tenantID,
-			Items:         make([]entities.TransactionItem, 0, len(req.Items)),
 		}
 
-		// Calculate total price from products
-		var calculatedTotal float64
+		if err := s.checkout(ctx, tx, tenantID, transaction, req); err != nil {
+			return err
+		}
 
-		// Process each item
-		for _, item := range req.Items {
-			// Validate product exists and has sufficient stock
-			product, err := s.productRepo.GetByID(ctx, item.ProductID)
-			if err != nil {
-				return fmt.Errorf("product not found: %w", err)
-			}
+		createdTransaction = transaction
+		return nil
+	})
 
-			if product.Stock < item.Quantity {
-				return fmt.Errorf("insufficient stock for product %s: requested %d, available %d",
-					product.Name, item.Quantity, product.Stock)
-			}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "transaction failed", "error", err)
+		return nil, err
+	}
+
+	s.checkStockAlerts(ctx, createdTransaction)
+
+	// Return transaction with populated items
+	transaction, err := s.transactionRepo.GetByID(ctx, createdTransaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	transaction.VoucherNotApplied = createdTransaction.VoucherNotApplied
+	return transaction, nil
+}
+
+// checkout prices req's items into transaction, takes the payment and deducts stock on tx.
+// transaction carries the header fields; it is created, or saved in place when it already has an ID.
+func (s *transactionService) checkout(ctx context.Context, tx *gorm.DB, tenantID uint, transaction *entities.Transaction, req interfaces.CreateTransactionRequest) error {
+	transaction.Status = entities.TransactionStatusCompleted
+	transaction.Items = make([]entities.TransactionItem, 0, len(req.Items))
+
+	if err := attachCustomer(tx, tenantID, transaction, req.CustomerID); err != nil {
+		return err
+	}
+
+	// The sale is paid into the cashier's open shift
+	shiftID, err := activeShiftID(ctx, tx, tenantID)
+	if err != nil {
+		return err
+	}
+	transaction.ShiftID = shiftID
 
-			// Calculate item total
-			itemTotal := product.HargaJual * float64(item.Quantity)
-			calculatedTotal += itemTotal
+	// Composite items consume their recipe components, so those are locked as well
+	recipes, err := loadRecipes(tx, req.Items)
+	if err != nil {
+		return err
+	}
+
+	// Lock every product in the basket so concurrent checkouts queue up behind this one
+	products, err := lockProducts(tx, tenantID, req.Items, recipes)
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, 0, len(products))
+	for id := range products {
+		ids = append(ids, id)
+	}
+	if err := rejectVariantParents(tx, ids); err != nil {
+		return err
+	}
+
+	modifierGroups, err := loadModifierGroups(tx, tenantID, products)
+	if err != nil {
+		return err
+	}
 
-			// Create transaction item
-			transactionItem := entities.TransactionItem{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     product.HargaJual,
+	// A generated voucher is locked for the rest of the sale so concurrent tills cannot both spend it
+	now := time.Now()
+	voucherCode := strings.ToUpper(strings.TrimSpace(req.VoucherCode))
+	var voucher *entities.Voucher
+	var voucherPromotionID uint
+	if voucherCode != "" {
+		voucher, err = lockVoucher(tx, tenantID, voucherCode)
+		if err != nil {
+			return err
+		}
+		if voucher != nil {
+			if err := checkVoucher(tx, voucher, transaction.CustomerPhone, now); err != nil {
+				return err
 			}
+			voucherPromotionID = voucher.PromotionID
+		}
+	}
+
+	// Promotions running now, including the entered voucher
+	promotions, err := loadPromotions(tx, tenantID, voucherCode, voucherPromotionID, now)
+	if err != nil {
+		return err
+	}
 
-			transaction.Items = append(transaction.Items, transactionItem)
+	// Process each item
+	for _, item := range req.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return fmt.Errorf("product %d: %w", item.ProductID, gorm.ErrRecordNotFound)
+		}
 
-			// Update product stock within the transaction
-			newStock := product.Stock - item.Quantity
-			if err := tx.Model(&entities.Product{}).Where("id = ? AND tenant_id = ?", item.ProductID, tenantID).Update("stock", newStock).Error; err != nil {
-				return fmt.Errorf("failed to update product stock: %w", err)
+		// Selected modifiers are priced server-side on top of the product price
+		modifiers, err := selectModifiers(product, modifierGroups[item.ProductID], item.Modifiers)
+		if err != nil {
+			return err
+		}
+		unitPrice := product.HargaJual
+		for _, m := range modifiers {
+			unitPrice += m.Price
+		}
+
+		// Create transaction item
+		transactionItem := entities.TransactionItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Price:     unitPrice,
+			Cost:      product.HargaModal,
+			TaxExempt: product.TaxExempt,
+			Modifiers: modifiers,
+		}
+
+		if recipe, ok := recipes[item.ProductID]; ok {
+			transactionItem.Cost = 0
+			for _, component := range recipe {
+				transactionItem.Cost += products[component.ComponentID].HargaModal.Mul(component.Quantity)
+				transactionItem.Deductions = append(transactionItem.Deductions, entities.TransactionItemDeduction{
+					ProductID: component.ComponentID,
+					Quantity:  component.Quantity,
+				})
+			}
+			if product.DeductOwnStock {
+				transactionItem.Deductions = append(transactionItem.Deductions, entities.TransactionItemDeduction{
+					ProductID: item.ProductID,
+					Quantity:  1,
+				})
 			}
 		}
 
-		// Apply discount if any
-		if transaction.Discount > 0 {
-			calculatedTotal = calculatedTotal * (1 - transaction.Discount/100)
+		for productID, perUnit := range itemStockUsage(transactionItem) {
+			stocked := products[productID]
+			if stocked.Stock < perUnit*item.Quantity {
+				return fmt.Errorf("product %s: requested %d, available %d: %w",
+					stocked.Name, perUnit*item.Quantity, stocked.Stock, entities.ErrInsufficientStock)
+			}
+			stocked.Stock -= perUnit * item.Quantity
+		}
+
+		transaction.Items = append(transaction.Items, transactionItem)
+	}
+
+	// Promotions discount individual lines before the basket discount, service charge and tax
+	applied := applyPromotions(transaction.Items, products, promotions)
+
+	// Calculate total price from items
+	var calculatedTotal, taxableTotal money.Money
+	for _, item := range transaction.Items {
+		transaction.Subtotal += item.Price.Mul(item.Quantity)
+		transaction.PromotionDiscount += item.Discount
+
+		itemTotal := item.Price.Mul(item.Quantity) - item.Discount
+		calculatedTotal += itemTotal
+		if !item.TaxExempt {
+			taxableTotal += itemTotal
 		}
+	}
+	if voucher != nil && transaction.Subtotal < voucher.MinBasket {
+		return fmt.Errorf("voucher %s needs a basket of at least %s: %w", voucher.Code, voucher.MinBasket, entities.ErrInvalidVoucher)
+	}
 
-		// Validate total price matches calculated total
-		if req.TotalPrice != calculatedTotal {
-			return fmt.Errorf("total price mismatch: provided %.2f, calculated %.2f", req.TotalPrice, calculatedTotal)
+	// A voucher that would discount the basket on its own but lost to a bigger automatic
+	// promotion is left unused, and unredeemed, instead of failing the sale
+	for i := range promotions {
+		p := &promotions[i]
+		if !p.RequiresVoucher() || applied[p.ID] {
+			continue
 		}
+		if !promotionQualifies(p, transaction.Items, products) {
+			return fmt.Errorf("voucher %s does not apply to this basket: %w", voucherCode, entities.ErrInvalidVoucher)
+		}
+		transaction.VoucherNotApplied = voucherCode
+		voucherCode = ""
+		voucher = nil
+	}
+	transaction.VoucherCode = voucherCode
 
-		// Set the validated total price
-		transaction.TotalPrice = calculatedTotal
+	// Apply discount if any
+	if transaction.Discount > 0 {
+		calculatedTotal -= calculatedTotal.Percent(transaction.Discount)
+		taxableTotal -= taxableTotal.Percent(transaction.Discount)
+	}
 
-		// Create transaction within the DB transaction
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	// Service charge and PPN follow the tenant's settings; exempt items only carry service charge
+	transaction.TaxRate = tenant.TaxRate
+	transaction.TaxInclusive = tenant.TaxInclusive
+	transaction.ServiceChargeRate = tenant.ServiceChargeRate
+	taxed := computeCharges(transaction, taxableTotal, true)
+	exempt := computeCharges(transaction, calculatedTotal-taxableTotal, false)
+	transaction.ServiceCharge = taxed.ServiceCharge + exempt.ServiceCharge
+	transaction.TaxBase = taxed.TaxBase
+	transaction.Tax = taxed.Tax
+	calculatedTotal += taxed.Added + exempt.Added
+
+	// Apply the tenant's cash rounding rule
+	calculatedTotal = calculatedTotal.Round(tenant.RoundingIncrement, tenant.RoundingMode)
+
+	// Validate total price matches calculated total
+	if req.TotalPrice != calculatedTotal {
+		return fmt.Errorf("total price mismatch: provided %s, calculated %s", req.TotalPrice, calculatedTotal)
+	}
+
+	// Set the validated total price
+	transaction.TotalPrice = calculatedTotal
+
+	payments, changeDue, err := buildPayments(req, calculatedTotal)
+	if err != nil {
+		return err
+	}
+	transaction.Payments = payments
+	transaction.ChangeDue = changeDue
+	if len(payments) > 1 {
+		transaction.PaymentMethod = entities.PaymentMethodSplit
+	} else {
+		transaction.PaymentMethod = payments[0].Method
+	}
+
+	// Points tenders are checked against the customer's balance, which stays locked until commit
+	loyaltyProgram, err := applyLoyalty(tx, tenantID, transaction, now)
+	if err != nil {
+		return err
+	}
+
+	// Create transaction within the DB transaction; a settled order keeps its row
+	if transaction.ID == 0 {
 		if err := tx.Create(transaction).Error; err != nil {
 			return fmt.Errorf("failed to create transaction: %w", err)
 		}
+	} else if err := tx.Save(transaction).Error; err != nil {
+		return fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	if voucher != nil {
+		if err := redeemVoucher(tx, voucher, transaction); err != nil {
+			return err
+		}
+	}
 
-		createdTransaction = transaction
+	if loyaltyProgram != nil {
+		if err := recordLoyalty(tx, loyaltyProgram, transaction, now); err != nil {
+			return err
+		}
+	}
+
+	// Decrement relative to the current row so a stale read can never oversell
+	for _, item := range transaction.Items {
+		for productID, perUnit := range itemStockUsage(item) {
+			if err := adjustStock(ctx, tx, &entities.StockMovement{
+				ProductID:   productID,
+				Delta:       -perUnit * item.Quantity,
+				Reason:      entities.StockReasonSale,
+				ReferenceID: &transaction.ID,
+				TenantID:    &tenantID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkStockAlerts queues reorder alerts for the stock a committed sale consumed
+func (s *transactionService) checkStockAlerts(ctx context.Context, transaction *entities.Transaction) {
+	sold := make(map[uint]int, len(transaction.Items))
+	for _, item := range transaction.Items {
+		for productID, perUnit := range itemStockUsage(item) {
+			sold[productID] += perUnit * item.Quantity
+		}
+	}
+	s.stockAlerts.CheckAfterSale(ctx, sold)
+}
+
+// attachCustomer links the sale to a customer. Without customerID, a phone number that belongs
+// to a registered customer links the sale to them.
+func attachCustomer(tx *gorm.DB, tenantID uint, transaction *entities.Transaction, customerID *uint) error {
+	var customer entities.Customer
+	query := tx.Where("tenant_id = ?", tenantID)
+	switch {
+	case customerID != nil:
+		query = query.Where("id = ?", *customerID)
+	case transaction.CustomerPhone != "":
+		query = query.Where("phone = ?", transaction.CustomerPhone)
+	default:
 		return nil
-	})
+	}
 
-	if err != nil {
-		s.logger.ErrorContext(ctx, "transaction failed", "error", err)
-		return nil, err
+	if err := query.First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if customerID == nil {
+				return nil
+			}
+			return fmt.Errorf("customer %d: %w", *customerID, err)
+		}
+		return fmt.Errorf("failed to get customer: %w", err)
 	}
 
-	// Return transaction with populated items
-	return s.transactionRepo.GetByID(ctx, createdTransaction.ID)
+	transaction.CustomerID = &customer.ID
+	transaction.CustomerPhone = customer.Phone
+	return nil
+}
+
+// resolveBarcodes maps scanned items to product IDs, multiplying quantities for pack barcodes.
+// Codes without a registered barcode are matched against the product SKU.
+func (s *transactionService) resolveBarcodes(ctx context.Context, items []interfaces.TransactionItemRequest) ([]interfaces.TransactionItemRequest, error) {
+	resolved := make([]interfaces.TransactionItemRequest, len(items))
+	for i, item := range items {
+		resolved[i] = item
+		if item.Barcode == "" {
+			continue
+		}
+
+		barcode, err := s.barcodeRepo.GetByCode(ctx, item.Barcode)
+		if err == nil {
+			resolved[i].ProductID = barcode.ProductID
+			resolved[i].Quantity = item.Quantity * barcode.Multiplier
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("barcode %s: %w", item.Barcode, err)
+		}
+
+		// Products without registered barcodes are scanned by their SKU
+		product, err := s.productRepo.GetBySKU(ctx, item.Barcode)
+		if err != nil {
+			return nil, fmt.Errorf("barcode %s: %w", item.Barcode, err)
+		}
+		resolved[i].ProductID = product.ID
+	}
+	return resolved, nil
+}
+
+// loadRecipes returns the recipe components of the basket's composite products, keyed by product
+func loadRecipes(tx *gorm.DB, items []interfaces.TransactionItemRequest) (map[uint][]entities.ProductComponent, error) {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	var components []entities.ProductComponent
+	if err := tx.Where("product_id IN ?", ids).Find(&components).Error; err != nil {
+		return nil, fmt.Errorf("failed to load product recipes: %w", err)
+	}
+
+	recipes := make(map[uint][]entities.ProductComponent)
+	for _, c := range components {
+		recipes[c.ProductID] = append(recipes[c.ProductID], c)
+	}
+	return recipes, nil
+}
+
+// lockProducts loads the basket's products and their recipe components with SELECT ... FOR UPDATE on tx.
+// Rows are locked in primary key order so two baskets sharing products cannot deadlock.
+func lockProducts(tx *gorm.DB, tenantID uint, items []interfaces.TransactionItemRequest, recipes map[uint][]entities.ProductComponent) (map[uint]*entities.Product, error) {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+		for _, c := range recipes[item.ProductID] {
+			ids = append(ids, c.ComponentID)
+		}
+	}
+
+	var products []entities.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND tenant_id = ?", ids, tenantID).
+		Order("id").
+		Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	locked := make(map[uint]*entities.Product, len(products))
+	for i := range products {
+		locked[products[i].ID] = &products[i]
+	}
+	for _, recipe := range recipes {
+		for _, c := range recipe {
+			if _, ok := locked[c.ComponentID]; !ok {
+				return nil, fmt.Errorf("component %d: %w", c.ComponentID, gorm.ErrRecordNotFound)
+			}
+		}
+	}
+	return locked, nil
+}
+
+// loadModifierGroups returns the modifier groups offered on each locked product, with their options.
+// Variants offer the groups of their parent as well as their own.
+func loadModifierGroups(tx *gorm.DB, tenantID uint, products map[uint]*entities.Product) (map[uint][]entities.ModifierGroup, error) {
+	ids := make([]uint, 0, len(products))
+	for id, p := range products {
+		ids = append(ids, id)
+		if p.ParentID != nil {
+			ids = append(ids, *p.ParentID)
+		}
+	}
+
+	var links []struct {
+		ProductID       uint
+		ModifierGroupID uint
+	}
+	if err := tx.Table("product_modifier_groups").Where("product_id IN ?", ids).Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to load product modifier groups: %w", err)
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	groupIDs := make([]uint, 0, len(links))
+	for _, link := range links {
+		groupIDs = append(groupIDs, link.ModifierGroupID)
+	}
+	var groups []entities.ModifierGroup
+	if err := tx.Preload("Options").Where("id IN ? AND tenant_id = ?", groupIDs, tenantID).Order("id").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to load modifier groups: %w", err)
+	}
+	byID := make(map[uint]entities.ModifierGroup, len(groups))
+	for _, g := range groups {
+		byID[g.ID] = g
+	}
+
+	offered := make(map[uint][]uint, len(links))
+	for _, link := range links {
+		offered[link.ProductID] = append(offered[link.ProductID], link.ModifierGroupID)
+	}
+
+	result := make(map[uint][]entities.ModifierGroup, len(products))
+	for id, p := range products {
+		productGroups := append([]uint{}, offered[id]...)
+		if p.ParentID != nil {
+			productGroups = append(productGroups, offered[*p.ParentID]...)
+		}
+		seen := make(map[uint]bool, len(productGroups))
+		for _, groupID := range productGroups {
+			g, ok := byID[groupID]
+			if !ok || seen[groupID] {
+				continue
+			}
+			seen[groupID] = true
+			result[id] = append(result[id], g)
+		}
+	}
+	return result, nil
+}
+
+// selectModifiers validates the selected option IDs against the product's modifier groups
+// and returns price snapshots of the selection, ordered by group
+func selectModifiers(product *entities.Product, groups []entities.ModifierGroup, optionIDs []uint) ([]entities.TransactionItemModifier, error) {
+	selected := make(map[uint]bool, len(optionIDs))
+	for _, id := range optionIDs {
+		if selected[id] {
+			return nil, fmt.Errorf("product %s: modifier %d selected twice: %w", product.Name, id, entities.ErrInvalidModifier)
+		}
+		selected[id] = true
+	}
+
+	var modifiers []entities.TransactionItemModifier
+	for _, g := range groups {
+		count := 0
+		for _, option := range g.Options {
+			if !selected[option.ID] {
+				continue
+			}
+			delete(selected, option.ID)
+			count++
+			modifiers = append(modifiers, entities.TransactionItemModifier{
+				ModifierOptionID: option.ID,
+				GroupName:        g.Name,
+				Name:             option.Name,
+				Price:            option.Price,
+			})
+		}
+
+		if count < g.MinSelect {
+			return nil, fmt.Errorf("product %s: %s needs at least %d selections: %w", product.Name, g.Name, g.MinSelect, entities.ErrInvalidModifier)
+		}
+		if g.MaxSelect > 0 && count > g.MaxSelect {
+			return nil, fmt.Errorf("product %s: %s allows at most %d selections: %w", product.Name, g.Name, g.MaxSelect, entities.ErrInvalidModifier)
+		}
+	}
+
+	for _, id := range optionIDs {
+		if selected[id] {
+			return nil, fmt.Errorf("product %s: modifier %d is not offered: %w", product.Name, id, entities.ErrInvalidModifier)
+		}
+	}
+
+	return modifiers, nil
+}
+
+// itemStockUsage returns the stock one unit of a transaction item consumes, per product.
+// Items without recorded deductions consume one unit of their own product.
+func itemStockUsage(item entities.TransactionItem) map[uint]int {
+	if len(item.Deductions) == 0 {
+		return map[uint]int{item.ProductID: 1}
+	}
+	usage := make(map[uint]int, len(item.Deductions))
+	for _, d := range item.Deductions {
+		usage[d.ProductID] += d.Quantity
+	}
+	return usage
+}
+
+// buildPayments validates that the tenders cover total and works out the change due.
+// Requests without tenders are treated as a single exact payment with req.PaymentMethod.
+func buildPayments(req interfaces.CreateTransactionRequest, total money.Money) ([]entities.TransactionPayment, money.Money, error) {
+	if len(req.Payments) == 0 {
+		if req.PaymentMethod == "" {
+			return nil, 0, fmt.Errorf("payment method is required")
+		}
+		return []entities.TransactionPayment{{Method: req.PaymentMethod, Amount: total}}, 0, nil
+	}
+
+	payments := make([]entities.TransactionPayment, 0, len(req.Payments))
+	var paid, cash money.Money
+	for _, p := range req.Payments {
+		if p.Method == "" {
+			return nil, 0, fmt.Errorf("payment method is required")
+		}
+		if p.Amount <= 0 {
+			return nil, 0, fmt.Errorf("payment amount must be greater than zero")
+		}
+
+		paid += p.Amount
+		if p.Method == entities.PaymentMethodCash {
+			cash += p.Amount
+		}
+
+		payments = append(payments, entities.TransactionPayment{
+			Method:          p.Method,
+			Amount:          p.Amount,
+			ReferenceNumber: p.ReferenceNumber,
+		})
+	}
+
+	if paid < total {
+		return nil, 0, fmt.Errorf("insufficient payment: paid %s, total %s", paid, total)
+	}
+
+	// Change can only be handed back from cash tenders
+	changeDue := paid - total
+	if changeDue > cash {
+		return nil, 0, fmt.Errorf("non-cash payments exceed total: paid %s, total %s", paid, total)
+	}
+
+	remaining := changeDue
+	for i := range payments {
+		if remaining <= 0 {
+			break
+		}
+		if payments[i].Method != entities.PaymentMethodCash {
+			continue
+		}
+		change := min(remaining, payments[i].Amount)
+		payments[i].ChangeAmount = change
+		remaining -= change
+	}
+
+	return payments, changeDue, nil
 }
 
 // GetTransaction retrieves a transaction by ID
@@ -154,3 +671,277 @@ func (s *transactionService) ListTransactions(ctx context.Context, page, limit i
 
 	return transactions, total, nil
 }
+
+// VoidTransaction cancels a completed sale and restores stock for every item
+func (s *transactionService) VoidTransaction(ctx context.Context, id uint, req interfaces.VoidTransactionRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "voiding transaction", "id", id, "user_id", req.UserID)
+
+	if err := authorize(ctx, entities.PermSalesVoid); err != nil {
+		return nil, err
+	}
+
+	if req.Reason == "" {
+		return nil, fmt.Errorf("void reason is required")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		transaction, err := s.lockTransaction(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != entities.TransactionStatusCompleted {
+			return fmt.Errorf("cannot void %s transaction: %w", transaction.Status, entities.ErrInvalidTransactionState)
+		}
+
+		refund := &entities.TransactionRefund{
+			Type:   entities.RefundTypeVoid,
+			Reason: req.Reason,
+			UserID: req.UserID,
+			Items:  make([]entities.TransactionRefundItem, 0, len(transaction.Items)),
+		}
+		for _, item := range transaction.Items {
+			refund.Items = append(refund.Items, newRefundItem(transaction, item, item.Quantity))
+		}
+		balanceRefund(refund, refundableAmount(transaction), true)
+
+		// A voided sale never used its voucher
+		if err := releaseVouchers(tx, transaction.ID); err != nil {
+			return err
+		}
+
+		return s.applyRefund(ctx, tx, transaction, refund, entities.TransactionStatusVoided)
+	})
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "void failed", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(ctx, id)
+}
+
+// RefundTransaction returns some or all items of a sale and restores their stock
+func (s *transactionService) RefundTransaction(ctx context.Context, id uint, req interfaces.RefundTransactionRequest) (*entities.Transaction, error) {
+	s.logger.InfoContext(ctx, "refunding transaction", "id", id, "user_id", req.UserID)
+
+	if err := authorize(ctx, entities.PermSalesVoid); err != nil {
+		return nil, err
+	}
+
+	if req.Reason == "" {
+		return nil, fmt.Errorf("refund reason is required")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		transaction, err := s.lockTransaction(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != entities.TransactionStatusCompleted && transaction.Status != entities.TransactionStatusPartiallyRefunded {
+			return fmt.Errorf("cannot refund %s transaction: %w", transaction.Status, entities.ErrInvalidTransactionState)
+		}
+
+		refund := &entities.TransactionRefund{
+			Type:   entities.RefundTypeRefund,
+			Reason: req.Reason,
+			UserID: req.UserID,
+		}
+
+		refund.Items, err = buildRefundItems(transaction, req.Items)
+		if err != nil {
+			return err
+		}
+
+		if len(refund.Items) == 0 {
+			return fmt.Errorf("nothing left to refund: %w", entities.ErrInvalidTransactionState)
+		}
+
+		left := refundableAmount(transaction)
+		transaction.Refunds = append(transaction.Refunds, *refund)
+		status := entities.TransactionStatusRefunded
+		for _, item := range transaction.Items {
+			if transaction.RefundedQuantity(item.ID) < item.Quantity {
+				status = entities.TransactionStatusPartiallyRefunded
+				break
+			}
+		}
+		balanceRefund(refund, left, status == entities.TransactionStatusRefunded)
+
+		return s.applyRefund(ctx, tx, transaction, refund, status)
+	})
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "refund failed", "error", err, "id", id)
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(ctx, id)
+}
+
+// lockTransaction loads a transaction with its items and refunds, locking the row until tx ends
+func (s *transactionService) lockTransaction(ctx context.Context, tx *gorm.DB, id uint) (*entities.Transaction, error) {
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	var transaction entities.Transaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items.Deductions").
+		Preload("Refunds.Items").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transaction not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// applyRefund persists the refund, gives the returned stock back and moves the transaction to status
+func (s *transactionService) applyRefund(ctx context.Context, tx *gorm.DB, transaction *entities.Transaction, refund *entities.TransactionRefund, status string) error {
+	refund.TransactionID = transaction.ID
+	refund.TenantID = transaction.TenantID
+	for _, item := range refund.Items {
+		refund.Amount += item.Amount
+	}
+
+	// The money is paid back from the open shift of whoever reverses the sale
+	shiftID, err := activeShiftID(ctx, tx, *transaction.TenantID)
+	if err != nil {
+		return err
+	}
+	refund.ShiftID = shiftID
+
+	if err := tx.Create(refund).Error; err != nil {
+		return fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	// Composite items give back what their recipe consumed at the time of sale
+	usage := make(map[uint]map[uint]int, len(transaction.Items))
+	for _, item := range transaction.Items {
+		usage[item.ID] = itemStockUsage(item)
+	}
+
+	for _, item := range refund.Items {
+		for productID, perUnit := range usage[item.TransactionItemID] {
+			if err := adjustStock(ctx, tx, &entities.StockMovement{
+				ProductID:   productID,
+				Delta:       perUnit * item.Quantity,
+				Reason:      entities.StockReasonRefund,
+				ReferenceID: &transaction.ID,
+				UserID:      &refund.UserID,
+				TenantID:    transaction.TenantID,
+			}); err != nil {
+				return fmt.Errorf("failed to restore product stock: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Model(transaction).Update("status", status).Error; err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	if err := reverseLoyalty(tx, transaction, refund.Amount, status); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "transaction reversed", "id", transaction.ID, "type", refund.Type, "amount", refund.Amount, "status", status)
+	return nil
+}
+
+// buildRefundItems validates requested quantities against what is still refundable.
+// An empty request refunds every remaining quantity.
+func buildRefundItems(transaction *entities.Transaction, requested []interfaces.RefundItemRequest) ([]entities.TransactionRefundItem, error) {
+	remaining := make(map[uint]int, len(transaction.Items))
+	for _, item := range transaction.Items {
+		remaining[item.ID] = item.Quantity - transaction.RefundedQuantity(item.ID)
+	}
+
+	if len(requested) == 0 {
+		items := make([]entities.TransactionRefundItem, 0, len(transaction.Items))
+		for _, item := range transaction.Items {
+			if remaining[item.ID] > 0 {
+				items = append(items, newRefundItem(transaction, item, remaining[item.ID]))
+			}
+		}
+		return items, nil
+	}
+
+	items := make([]entities.TransactionRefundItem, 0, len(requested))
+	for _, req := range requested {
+		if req.Quantity < 1 {
+			return nil, fmt.Errorf("refund quantity must be at least 1: %w", entities.ErrInvalidRefundQuantity)
+		}
+
+		left, ok := remaining[req.TransactionItemID]
+		if !ok {
+			return nil, fmt.Errorf("item %d in transaction %d: %w", req.TransactionItemID, transaction.ID, entities.ErrTransactionItemNotFound)
+		}
+		if req.Quantity > left {
+			return nil, fmt.Errorf("item %d: requested %d, remaining %d: %w", req.TransactionItemID, req.Quantity, left, entities.ErrInvalidRefundQuantity)
+		}
+		remaining[req.TransactionItemID] = left - req.Quantity
+
+		for _, item := range transaction.Items {
+			if item.ID == req.TransactionItemID {
+				items = append(items, newRefundItem(transaction, item, req.Quantity))
+				break
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// newRefundItem prices a returned quantity at the sale price less its promotions and the transaction
+// discount, plus its share of the service charge and tax charged at the time of sale
+func newRefundItem(transaction *entities.Transaction, item entities.TransactionItem, quantity int) entities.TransactionRefundItem {
+	amount := item.Price.Mul(quantity) - item.Discount.Mul(quantity).Div(item.Quantity)
+	if transaction.Discount > 0 {
+		amount -= amount.Percent(transaction.Discount)
+	}
+	c := computeCharges(transaction, amount, !item.TaxExempt)
+
+	return entities.TransactionRefundItem{
+		TransactionItemID: item.ID,
+		ProductID:         item.ProductID,
+		Quantity:          quantity,
+		Amount:            amount + c.Added,
+		ServiceCharge:     c.ServiceCharge,
+		TaxBase:           c.TaxBase,
+		Tax:               c.Tax,
+	}
+}
+
+// refundableAmount returns what the sale took less what earlier refunds paid back
+func refundableAmount(transaction *entities.Transaction) money.Money {
+	left := transaction.TotalPrice
+	for _, refund := range transaction.Refunds {
+		left -= refund.Amount
+	}
+	return left
+}
+
+// balanceRefund keeps refunds within what the sale took. Lines are priced without the tenant's
+// cash rounding, so a refund is capped at left and the refund returning the last items pays back
+// exactly left. The difference is put on the last line.
+func balanceRefund(refund *entities.TransactionRefund, left money.Money, final bool) {
+	if len(refund.Items) == 0 {
+		return
+	}
+
+	var amount money.Money
+	for _, item := range refund.Items {
+		amount += item.Amount
+	}
+	target := amount
+	if final || target > left {
+		target = left
+	}
+	refund.Items[len(refund.Items)-1].Amount += target - amount
+}
@@ -0,0 +1,382 @@
+//go:build integration
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"github.com/usernamesalah/rh-pos/internal/pkg/money"
+	"github.com/usernamesalah/rh-pos/internal/repository"
+	"gorm.io/gorm"
+)
+
+// noStockAlerts ignores reorder checks so tests do not race the background goroutine
+type noStockAlerts struct{}
+
+func (noStockAlerts) CheckAfterSale(ctx context.Context, sold map[uint]int) {}
+
+func newTestTransactionService(t *testing.T) (interfaces.TransactionService, *gorm.DB) {
+	db, logger := openTestDB(t)
+	svc := NewTransactionService(
+		repository.NewTransactionRepository(db, logger),
+		repository.NewProductRepository(db, logger),
+		repository.NewProductBarcodeRepository(db, logger),
+		repository.NewTenantRepository(db, logger),
+		noStockAlerts{},
+		db,
+		logger,
+	)
+	return svc, db
+}
+
+func createTestProduct(t *testing.T, db *gorm.DB, tenantID uint, name string, stock int, price money.Money) *entities.Product {
+	t.Helper()
+
+	product := &entities.Product{
+		Name:      name,
+		SKU:       fmt.Sprintf("%s-%d-%d", name, tenantID, time.Now().UnixNano()),
+		HargaJual: price,
+		Stock:     stock,
+		TenantID:  &tenantID,
+	}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	return product
+}
+
+func productStock(t *testing.T, db *gorm.DB, id uint) int {
+	t.Helper()
+
+	var stock int
+	if err := db.Model(&entities.Product{}).Select("stock").Where("id = ?", id).Scan(&stock).Error; err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	return stock
+}
+
+func countTransactions(t *testing.T, db *gorm.DB, tenantID uint) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&entities.Transaction{}).Where("tenant_id = ?", tenantID).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count transactions: %v", err)
+	}
+	return count
+}
+
+func countSaleMovements(t *testing.T, db *gorm.DB, productID uint) (int64, int) {
+	t.Helper()
+
+	var result struct {
+		Movements int64
+		Units     int
+	}
+	if err := db.Model(&entities.StockMovement{}).
+		Select("COUNT(*) as movements, COALESCE(-SUM(delta), 0) as units").
+		Where("product_id = ? AND reason = ?", productID, entities.StockReasonSale).
+		Scan(&result).Error; err != nil {
+		t.Fatalf("failed to count stock movements: %v", err)
+	}
+	return result.Movements, result.Units
+}
+
+// checkoutConcurrently runs every basket in its own goroutine, released at the same moment,
+// and returns the number of sales that succeeded. Any failure other than insufficient stock fails the test.
+func checkoutConcurrently(t *testing.T, ctx context.Context, svc interfaces.TransactionService, baskets []interfaces.CreateTransactionRequest) int {
+	t.Helper()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		start     = make(chan struct{})
+		errs      = make(chan error, len(baskets))
+	)
+
+	for _, basket := range baskets {
+		wg.Add(1)
+		go func(req interfaces.CreateTransactionRequest) {
+			defer wg.Done()
+			<-start
+
+			_, err := svc.CreateTransaction(ctx, req)
+			switch {
+			case err == nil:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case !errors.Is(err, entities.ErrInsufficientStock):
+				errs <- err
+			}
+		}(basket)
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("checkout failed with an unexpected error: %v", err)
+	}
+	return succeeded
+}
+
+func basket(price money.Money, lines ...[2]uint) interfaces.CreateTransactionRequest {
+	req := interfaces.CreateTransactionRequest{
+		User:          "cashier",
+		PaymentMethod: entities.PaymentMethodCash,
+	}
+	for _, line := range lines {
+		req.Items = append(req.Items, interfaces.TransactionItemRequest{ProductID: line[0], Quantity: int(line[1])})
+		req.TotalPrice += price.Mul(int(line[1]))
+	}
+	return req
+}
+
+func TestCheckoutDoesNotOversellLastUnits(t *testing.T) {
+	svc, db := newTestTransactionService(t)
+	ctx, tenantID := newTestTenant(t, db)
+
+	price := money.FromInt(10000)
+	tests := []struct {
+		name     string
+		stock    int
+		quantity int
+		tills    int
+	}{
+		{"single units", 5, 1, 20},
+		{"multiple units", 10, 3, 12},
+		{"last unit", 1, 1, 10},
+		{"nothing in stock", 0, 1, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createTestProduct(t, db, tenantID, "oversell", tt.stock, price)
+			before := countTransactions(t, db, tenantID)
+
+			baskets := make([]interfaces.CreateTransactionRequest, tt.tills)
+			for i := range baskets {
+				baskets[i] = basket(price, [2]uint{product.ID, uint(tt.quantity)})
+			}
+
+			succeeded := checkoutConcurrently(t, ctx, svc, baskets)
+
+			if want := min(tt.tills, tt.stock/tt.quantity); succeeded != want {
+				t.Errorf("%d checkouts succeeded, want %d", succeeded, want)
+			}
+			if stock := productStock(t, db, product.ID); stock != tt.stock-succeeded*tt.quantity {
+				t.Errorf("stock = %d, want %d", stock, tt.stock-succeeded*tt.quantity)
+			} else if stock < 0 {
+				t.Errorf("stock went negative: %d", stock)
+			}
+
+			// Refused checkouts must leave no sale or stock movement behind
+			if created := countTransactions(t, db, tenantID) - before; created != int64(succeeded) {
+				t.Errorf("%d transactions recorded, want %d", created, succeeded)
+			}
+			movements, units := countSaleMovements(t, db, product.ID)
+			if movements != int64(succeeded) || units != succeeded*tt.quantity {
+				t.Errorf("%d sale movements of %d units recorded, want %d of %d", movements, units, succeeded, succeeded*tt.quantity)
+			}
+		})
+	}
+}
+
+func TestCheckoutSharedProductsInAnyOrder(t *testing.T) {
+	svc, db := newTestTransactionService(t)
+	ctx, tenantID := newTestTenant(t, db)
+
+	price := money.FromInt(5000)
+	a := createTestProduct(t, db, tenantID, "shared-a", 20, price)
+	b := createTestProduct(t, db, tenantID, "shared-b", 15, price)
+
+	// Baskets list the same products in opposite orders; locking in key order must not deadlock
+	var baskets []interfaces.CreateTransactionRequest
+	for i := 0; i < 10; i++ {
+		baskets = append(baskets,
+			basket(price, [2]uint{a.ID, 2}, [2]uint{b.ID, 1}),
+			basket(price, [2]uint{b.ID, 2}, [2]uint{a.ID, 1}),
+		)
+	}
+
+	succeeded := checkoutConcurrently(t, ctx, svc, baskets)
+	if succeeded == 0 {
+		t.Fatal("no checkout succeeded")
+	}
+
+	for _, p := range []*entities.Product{a, b} {
+		stock := productStock(t, db, p.ID)
+		if stock < 0 {
+			t.Errorf("%s stock went negative: %d", p.Name, stock)
+		}
+		_, sold := countSaleMovements(t, db, p.ID)
+		if p.Stock-sold != stock {
+			t.Errorf("%s: started with %d, sold %d, but stock is %d", p.Name, p.Stock, sold, stock)
+		}
+	}
+
+	if created := countTransactions(t, db, tenantID); created != int64(succeeded) {
+		t.Errorf("%d transactions recorded, want %d", created, succeeded)
+	}
+}
+
+func TestCheckoutCompositeComponentsDoNotOversell(t *testing.T) {
+	svc, db := newTestTransactionService(t)
+	ctx, tenantID := newTestTenant(t, db)
+
+	price := money.FromInt(25000)
+	beans := createTestProduct(t, db, tenantID, "beans", 10, 0)
+	latte := createTestProduct(t, db, tenantID, "latte", 0, price)
+	if err := db.Create(&entities.ProductComponent{ProductID: latte.ID, ComponentID: beans.ID, Quantity: 3}).Error; err != nil {
+		t.Fatalf("failed to create recipe: %v", err)
+	}
+
+	baskets := make([]interfaces.CreateTransactionRequest, 8)
+	for i := range baskets {
+		baskets[i] = basket(price, [2]uint{latte.ID, 1})
+	}
+
+	succeeded := checkoutConcurrently(t, ctx, svc, baskets)
+
+	if succeeded != 3 {
+		t.Errorf("%d checkouts succeeded, want 3", succeeded)
+	}
+	if stock := productStock(t, db, beans.ID); stock != 10-succeeded*3 {
+		t.Errorf("component stock = %d, want %d", stock, 10-succeeded*3)
+	}
+}
+
+func TestRefundsAddUpToTheRoundedTotal(t *testing.T) {
+	svc, db := newTestTransactionService(t)
+	ctx, tenantID := newTestTenant(t, db)
+	if err := db.Model(&entities.Tenant{}).Where("id = ?", tenantID).Updates(map[string]interface{}{
+		"rounding_increment": money.FromInt(100),
+		"rounding_mode":      money.RoundDown,
+	}).Error; err != nil {
+		t.Fatalf("failed to set rounding: %v", err)
+	}
+	ctx = context.WithValue(ctx, "permissions", entities.NewPermissionSet([]string{entities.PermSalesVoid}))
+
+	// Three units at 1115 come to 3345, which the tenant rounds down to 3300
+	price := money.FromInt(1115)
+	total := money.FromInt(3300)
+
+	tests := []struct {
+		name    string
+		reverse func(id, itemID uint) error
+		steps   int
+	}{
+		{"void", func(id, itemID uint) error {
+			_, err := svc.VoidTransaction(ctx, id, interfaces.VoidTransactionRequest{Reason: "test"})
+			return err
+		}, 1},
+		{"full refund", func(id, itemID uint) error {
+			_, err := svc.RefundTransaction(ctx, id, interfaces.RefundTransactionRequest{Reason: "test"})
+			return err
+		}, 1},
+		{"one unit at a time", func(id, itemID uint) error {
+			_, err := svc.RefundTransaction(ctx, id, interfaces.RefundTransactionRequest{
+				Reason: "test",
+				Items:  []interfaces.RefundItemRequest{{TransactionItemID: itemID, Quantity: 1}},
+			})
+			return err
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createTestProduct(t, db, tenantID, "rounded", 10, price)
+			req := basket(price, [2]uint{product.ID, 3})
+			req.TotalPrice = total
+			transaction, err := svc.CreateTransaction(ctx, req)
+			if err != nil {
+				t.Fatalf("checkout failed: %v", err)
+			}
+
+			for i := 0; i < tt.steps; i++ {
+				if err := tt.reverse(transaction.ID, transaction.Items[0].ID); err != nil {
+					t.Fatalf("reversal %d failed: %v", i+1, err)
+				}
+			}
+
+			var refunded money.Money
+			if err := db.Model(&entities.TransactionRefund{}).Select("COALESCE(SUM(amount), 0)").
+				Where("transaction_id = ?", transaction.ID).Scan(&refunded).Error; err != nil {
+				t.Fatalf("failed to sum refunds: %v", err)
+			}
+			if refunded != total {
+				t.Errorf("refunded %s, want the %s charged", refunded, total)
+			}
+		})
+	}
+}
+
+func TestCheckoutWithVoucherBeatenByAutomaticPromotion(t *testing.T) {
+	svc, db := newTestTransactionService(t)
+	ctx, tenantID := newTestTenant(t, db)
+
+	price := money.FromInt(10000)
+	coffee := createTestProduct(t, db, tenantID, "coffee", 10, price)
+	tea := createTestProduct(t, db, tenantID, "tea", 10, price)
+
+	promotions := []entities.Promotion{
+		{Name: "half price coffee", Type: entities.PromotionTypeItemDiscount, DiscountPercent: 50, Products: []entities.Product{*coffee}},
+		{Name: "ten off coffee", Type: entities.PromotionTypeItemDiscount, Code: "COFFEE10", DiscountPercent: 10, Products: []entities.Product{*coffee}},
+		{Name: "ten off tea", Type: entities.PromotionTypeItemDiscount, Code: "TEA10", DiscountPercent: 10, Products: []entities.Product{*tea}},
+	}
+	for i := range promotions {
+		promotions[i].Active = true
+		promotions[i].TenantID = &tenantID
+		if err := db.Create(&promotions[i]).Error; err != nil {
+			t.Fatalf("failed to create promotion: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		voucher        string
+		total          money.Money
+		wantErr        error
+		wantNotApplied string
+	}{
+		{"voucher beaten by an automatic promotion", "COFFEE10", money.FromInt(5000), nil, "COFFEE10"},
+		{"voucher for products not in the basket", "TEA10", money.FromInt(5000), entities.ErrInvalidVoucher, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := basket(price, [2]uint{coffee.ID, 1})
+			req.TotalPrice = tt.total
+			req.VoucherCode = tt.voucher
+
+			transaction, err := svc.CreateTransaction(ctx, req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("checkout: got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkout failed: %v", err)
+			}
+			if transaction.VoucherNotApplied != tt.wantNotApplied {
+				t.Errorf("voucher not applied = %q, want %q", transaction.VoucherNotApplied, tt.wantNotApplied)
+			}
+			if transaction.VoucherCode != "" {
+				t.Errorf("voucher code = %q, want none", transaction.VoucherCode)
+			}
+			if transaction.TotalPrice != tt.total {
+				t.Errorf("total = %s, want %s", transaction.TotalPrice, tt.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/usernamesalah/rh-pos/internal/domain/entities"
+	"github.com/usernamesalah/rh-pos/internal/domain/interfaces"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// minPasswordLength matches the length required when users change their own password
+const minPasswordLength = 6
+
+type userService struct {
+	userRepo    interfaces.UserRepository
+	roleRepo    interfaces.RoleRepository
+	sessionRepo interfaces.SessionRepository
+	authService interfaces.AuthService
+	logger      *slog.Logger
+}
+
+// NewUserService creates a new user management service
+func NewUserService(userRepo interfaces.UserRepository, roleRepo interfaces.RoleRepository, sessionRepo interfaces.SessionRepository, authService interfaces.AuthService, logger *slog.Logger) interfaces.UserService {
+	return &userService{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		sessionRepo: sessionRepo,
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// ListUsers retrieves the tenant's users with pagination
+func (s *userService) ListUsers(ctx context.Context, page, limit int) ([]entities.User, int64, error) {
+	s.logger.InfoContext(ctx, "listing users", "page", page, "limit", limit)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	users, total, err := s.userRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// GetUser retrieves a user of the tenant by ID
+func (s *userService) GetUser(ctx context.Context, id uint) (*entities.User, error) {
+	s.logger.InfoContext(ctx, "getting user", "id", id)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// CreateUser creates an active user in the current tenant
+func (s *userService) CreateUser(ctx context.Context, req interfaces.CreateUserRequest) (*entities.User, error) {
+	s.logger.InfoContext(ctx, "creating user", "username", req.Username)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	tenantID, ok := ctx.Value("tenant_id").(uint)
+	if !ok {
+		return nil, fmt.Errorf("tenant_id not found in context")
+	}
+
+	username := strings.TrimSpace(req.Username)
+	if err := s.ensureUsernameAvailable(ctx, 0, username); err != nil {
+		return nil, err
+	}
+	if err := s.ensureCanGrant(ctx, req.Role); err != nil {
+		return nil, err
+	}
+
+	password, err := hashUserPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entities.User{
+		Username: username,
+		Password: password,
+		Role:     req.Role,
+		Active:   true,
+		TenantID: &tenantID,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdateUser renames, reassigns the role of, or activates and deactivates a user.
+// Role changes and deactivation apply from the user's next request; deactivation
+// also ends the user's sessions.
+func (s *userService) UpdateUser(ctx context.Context, id uint, req interfaces.UpdateUserRequest) (*entities.User, error) {
+	s.logger.InfoContext(ctx, "updating user", "id", id)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return nil, err
+	}
+
+	user, err := s.manageableUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	self := isCurrentUser(ctx, user.ID)
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username != user.Username {
+			if err := s.ensureUsernameAvailable(ctx, user.ID, username); err != nil {
+				return nil, err
+			}
+			user.Username = username
+		}
+	}
+
+	if req.Role != nil && *req.Role != user.Role {
+		if self {
+			return nil, entities.ErrUserSelfChange
+		}
+		if err := s.ensureCanGrant(ctx, *req.Role); err != nil {
+			return nil, err
+		}
+		user.Role = *req.Role
+	}
+
+	if req.Active != nil && *req.Active != user.Active {
+		if self {
+			return nil, entities.ErrUserSelfChange
+		}
+		user.Active = *req.Active
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if !user.Active {
+		if _, err := s.sessionRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to end user sessions: %w", err)
+		}
+	}
+
+	return user, nil
+}
+
+// ResetPassword sets a new password for a user without the current one and
+// ends the user's sessions
+func (s *userService) ResetPassword(ctx context.Context, id uint, password string) error {
+	s.logger.InfoContext(ctx, "resetting user password", "id", id)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return err
+	}
+
+	user, err := s.manageableUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := hashUserPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	if _, err := s.sessionRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to end user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockUser lifts the password login lockout and the PIN lockout of a user
+func (s *userService) UnlockUser(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "unlocking user", "id", id)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return err
+	}
+
+	user, err := s.manageableUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Failed logins are counted per user whether or not they named the tenant, so
+	// unlocking the username within the tenant clears exactly this user
+	if user.TenantID == nil {
+		return fmt.Errorf("failed to unlock user %d: user has no tenant", user.ID)
+	}
+	tenantCtx := context.WithValue(ctx, "tenant_id", *user.TenantID)
+	if err := s.authService.UnlockLogin(tenantCtx, user.Username, ""); err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	if user.PINFailedAttempts > 0 || user.PINLockedUntil != nil {
+		user.PINFailedAttempts = 0
+		user.PINLockedUntil = nil
+		if err := s.userRepo.UpdatePIN(ctx, user); err != nil {
+			return fmt.Errorf("failed to unlock user: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user that has no recorded activity.
+// Users with history are deactivated instead so their records stay attributed.
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	s.logger.InfoContext(ctx, "deleting user", "id", id)
+
+	if err := authorize(ctx, entities.PermUsersManage); err != nil {
+		return err
+	}
+
+	user, err := s.manageableUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if isCurrentUser(ctx, user.ID) {
+		return entities.ErrUserSelfChange
+	}
+
+	hasHistory, err := s.userRepo.HasHistory(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if hasHistory {
+		return entities.ErrUserHasHistory
+	}
+
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// manageableUser loads a user the caller may manage: one whose role grants nothing the caller lacks
+func (s *userService) manageableUser(ctx context.Context, id uint) (*entities.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.ensureCanGrant(ctx, user.Role); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ensureCanGrant checks that role exists and grants nothing beyond the caller's own permissions,
+// so managing users cannot be used to escalate privileges
+func (s *userService) ensureCanGrant(ctx context.Context, role string) error {
+	permissions, ok := entities.BuiltInRoles[role]
+	if !ok {
+		custom, err := s.roleRepo.GetByName(ctx, role)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: unknown role %q", entities.ErrInvalidRole, role)
+			}
+			return fmt.Errorf("failed to get role: %w", err)
+		}
+		permissions = custom.PermissionNames()
+	}
+
+	for _, p := range permissions {
+		if err := authorize(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureUsernameAvailable checks that no other user of the tenant has the username
+func (s *userService) ensureUsernameAvailable(ctx context.Context, id uint, username string) error {
+	if username == "" {
+		return fmt.Errorf("%w: username is required", entities.ErrInvalidUser)
+	}
+
+	existing, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if existing.ID != id {
+		return entities.ErrUsernameTaken
+	}
+
+	return nil
+}
+
+// isCurrentUser reports whether id is the logged-in user
+func isCurrentUser(ctx context.Context, id uint) bool {
+	userID, ok := ctx.Value("user_id").(uint)
+	return ok && userID == id
+}
+
+// hashUserPassword validates and hashes a password set by an administrator
+func hashUserPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("%w: password must be at least %d characters", entities.ErrInvalidUser, minPasswordLength)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hashed), nil
+}
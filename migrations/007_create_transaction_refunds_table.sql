@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `transactions` ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'completed' AFTER `total_price`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_refunds` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `transaction_id` int unsigned NOT NULL,
+    `type` varchar(20) NOT NULL,
+    `reason` TEXT NOT NULL,
+    `user_id` int unsigned NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_refunds_transaction_id` (`transaction_id`),
+    KEY `idx_transaction_refunds_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_transaction_refunds_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`),
+    CONSTRAINT `fk_transaction_refunds_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_transaction_refunds_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_refund_items` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `refund_id` int unsigned NOT NULL,
+    `transaction_item_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    `quantity` int NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_refund_items_refund_id` (`refund_id`),
+    KEY `idx_transaction_refund_items_transaction_item_id` (`transaction_item_id`),
+    CONSTRAINT `fk_transaction_refund_items_refund` FOREIGN KEY (`refund_id`) REFERENCES `transaction_refunds` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_transaction_refund_items_item` FOREIGN KEY (`transaction_item_id`) REFERENCES `transaction_items` (`id`),
+    CONSTRAINT `fk_transaction_refund_items_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `transaction_refund_items`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `transaction_refunds`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions` DROP COLUMN `status`;
+-- +goose StatementEnd
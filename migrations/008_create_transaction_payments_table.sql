@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `transactions` ADD COLUMN `change_due` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `total_price`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_payments` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `transaction_id` int unsigned NOT NULL,
+    `method` varchar(50) NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `change_amount` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `reference_number` varchar(100) NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_payments_transaction_id` (`transaction_id`),
+    KEY `idx_transaction_payments_method` (`method`),
+    CONSTRAINT `fk_transaction_payments_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO `transaction_payments` (`transaction_id`, `method`, `amount`, `created_at`, `updated_at`)
+SELECT `id`, `payment_method`, `total_price`, `created_at`, `updated_at` FROM `transactions`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `transaction_payments`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions` DROP COLUMN `change_due`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `tenants`
+ADD COLUMN `rounding_increment` decimal(10,2) NOT NULL DEFAULT 0.00,
+ADD COLUMN `rounding_mode` varchar(20) NOT NULL DEFAULT 'nearest';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `tenants`
+DROP COLUMN `rounding_increment`,
+DROP COLUMN `rounding_mode`;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `stock_movements` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` int unsigned NOT NULL,
+    `delta` int NOT NULL,
+    `balance` int NOT NULL,
+    `reason` varchar(20) NOT NULL,
+    `reference_id` int unsigned NULL,
+    `user_id` int unsigned NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_stock_movements_product_id_created_at` (`product_id`, `created_at`),
+    KEY `idx_stock_movements_tenant_id` (`tenant_id`),
+    KEY `idx_stock_movements_reason_reference_id` (`reason`, `reference_id`),
+    CONSTRAINT `fk_stock_movements_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_stock_movements_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_stock_movements_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO `stock_movements` (`product_id`, `delta`, `balance`, `reason`, `tenant_id`)
+SELECT `id`, `stock`, `stock`, 'adjustment', `tenant_id` FROM `products` WHERE `stock` <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `stock_movements`;
+-- +goose StatementEnd
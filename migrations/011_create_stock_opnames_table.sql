@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `stock_opnames` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `status` varchar(20) NOT NULL DEFAULT 'open',
+    `notes` TEXT NULL,
+    `opened_by` int unsigned NOT NULL,
+    `committed_by` int unsigned NULL,
+    `committed_at` timestamp NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_stock_opnames_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_stock_opnames_opened_by` FOREIGN KEY (`opened_by`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_stock_opnames_committed_by` FOREIGN KEY (`committed_by`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_stock_opnames_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `stock_opname_items` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `opname_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    `counted_qty` int NOT NULL DEFAULT 0,
+    `system_qty` int NOT NULL DEFAULT 0,
+    `variance` int NOT NULL DEFAULT 0,
+    `variance_value` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_stock_opname_items_opname_product` (`opname_id`, `product_id`),
+    CONSTRAINT `fk_stock_opname_items_opname` FOREIGN KEY (`opname_id`) REFERENCES `stock_opnames` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_stock_opname_items_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `stock_opname_items`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `stock_opnames`;
+-- +goose StatementEnd
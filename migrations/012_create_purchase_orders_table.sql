@@ -0,0 +1,112 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `suppliers` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `contact_name` varchar(255) NULL,
+    `phone` varchar(50) NULL,
+    `email` varchar(255) NULL,
+    `address` TEXT NULL,
+    `notes` TEXT NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_suppliers_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_suppliers_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `purchase_orders` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `supplier_id` int unsigned NOT NULL,
+    `status` varchar(20) NOT NULL DEFAULT 'open',
+    `expected_at` timestamp NULL,
+    `notes` TEXT NULL,
+    `total_cost` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `created_by` int unsigned NOT NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_purchase_orders_supplier_id` (`supplier_id`),
+    KEY `idx_purchase_orders_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_purchase_orders_supplier` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`),
+    CONSTRAINT `fk_purchase_orders_created_by` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_purchase_orders_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `purchase_order_items` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `purchase_order_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    `quantity` int NOT NULL,
+    `received_qty` int NOT NULL DEFAULT 0,
+    `unit_cost` decimal(10,2) NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_purchase_order_items_purchase_order_id` (`purchase_order_id`),
+    CONSTRAINT `fk_purchase_order_items_purchase_order` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_purchase_order_items_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `goods_receipts` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `purchase_order_id` int unsigned NOT NULL,
+    `notes` TEXT NULL,
+    `user_id` int unsigned NOT NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_goods_receipts_purchase_order_id` (`purchase_order_id`),
+    KEY `idx_goods_receipts_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_goods_receipts_purchase_order` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_goods_receipts_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_goods_receipts_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `goods_receipt_items` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `receipt_id` int unsigned NOT NULL,
+    `purchase_order_item_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    `quantity` int NOT NULL,
+    `unit_cost` decimal(10,2) NOT NULL,
+    `previous_harga_modal` decimal(10,2) NOT NULL,
+    `new_harga_modal` decimal(10,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_goods_receipt_items_receipt_id` (`receipt_id`),
+    CONSTRAINT `fk_goods_receipt_items_receipt` FOREIGN KEY (`receipt_id`) REFERENCES `goods_receipts` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_goods_receipt_items_purchase_order_item` FOREIGN KEY (`purchase_order_item_id`) REFERENCES `purchase_order_items` (`id`),
+    CONSTRAINT `fk_goods_receipt_items_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `goods_receipt_items`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `goods_receipts`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `purchase_order_items`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `purchase_orders`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `suppliers`;
+-- +goose StatementEnd
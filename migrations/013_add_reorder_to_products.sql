@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `products`
+ADD COLUMN `reorder_point` int NOT NULL DEFAULT 0 AFTER `stock`,
+ADD COLUMN `reorder_qty` int NOT NULL DEFAULT 0 AFTER `reorder_point`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `products`
+DROP COLUMN `reorder_qty`,
+DROP COLUMN `reorder_point`;
+-- +goose StatementEnd
@@ -0,0 +1,98 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `categories` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `parent_id` int unsigned NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_categories_parent_id` (`parent_id`),
+    KEY `idx_categories_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_categories_parent` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`),
+    CONSTRAINT `fk_categories_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `tags` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(100) NOT NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_tags_tenant_name` (`tenant_id`, `name`),
+    CONSTRAINT `fk_tags_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `product_tags` (
+    `product_id` int unsigned NOT NULL,
+    `tag_id` int unsigned NOT NULL,
+    PRIMARY KEY (`product_id`, `tag_id`),
+    KEY `idx_product_tags_tag_id` (`tag_id`),
+    CONSTRAINT `fk_product_tags_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+ADD COLUMN `category_id` int unsigned NULL AFTER `reorder_qty`,
+ADD KEY `idx_products_category_id` (`category_id`),
+ADD CONSTRAINT `fk_products_category` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX `idx_products_tenant_name` ON `products` (`tenant_id`, `name`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX `idx_products_tenant_harga_jual` ON `products` (`tenant_id`, `harga_jual`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX `idx_products_tenant_stock` ON `products` (`tenant_id`, `stock`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX `idx_products_tenant_created_at` ON `products` (`tenant_id`, `created_at`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX `idx_products_tenant_created_at` ON `products`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX `idx_products_tenant_stock` ON `products`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX `idx_products_tenant_harga_jual` ON `products`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX `idx_products_tenant_name` ON `products`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+DROP FOREIGN KEY `fk_products_category`,
+DROP KEY `idx_products_category_id`,
+DROP COLUMN `category_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `product_tags`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `tags`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `categories`;
+-- +goose StatementEnd
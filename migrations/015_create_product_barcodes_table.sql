@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `product_barcodes` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` int unsigned NOT NULL,
+    `code` varchar(64) NOT NULL,
+    `multiplier` int NOT NULL DEFAULT 1,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_product_barcodes_tenant_code` (`tenant_id`, `code`),
+    KEY `idx_product_barcodes_product_id` (`product_id`),
+    CONSTRAINT `fk_product_barcodes_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_barcodes_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `product_barcodes`;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `products`
+ADD COLUMN `parent_id` int unsigned NULL AFTER `category_id`,
+ADD COLUMN `variant_name` varchar(255) NULL AFTER `parent_id`,
+ADD KEY `idx_products_parent_id` (`parent_id`),
+ADD CONSTRAINT `fk_products_parent` FOREIGN KEY (`parent_id`) REFERENCES `products` (`id`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `product_options` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` int unsigned NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `position` int NOT NULL DEFAULT 0,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_product_options_product_id` (`product_id`),
+    CONSTRAINT `fk_product_options_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `product_option_values` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `option_id` int unsigned NOT NULL,
+    `value` varchar(100) NOT NULL,
+    `position` int NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    KEY `idx_product_option_values_option_id` (`option_id`),
+    CONSTRAINT `fk_product_option_values_option` FOREIGN KEY (`option_id`) REFERENCES `product_options` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `product_option_values`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `product_options`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+DROP FOREIGN KEY `fk_products_parent`,
+DROP KEY `idx_products_parent_id`,
+DROP COLUMN `variant_name`,
+DROP COLUMN `parent_id`;
+-- +goose StatementEnd
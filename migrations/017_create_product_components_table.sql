@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `product_components` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` int unsigned NOT NULL,
+    `component_id` int unsigned NOT NULL,
+    `quantity` int NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_product_components_product_component` (`product_id`, `component_id`),
+    KEY `idx_product_components_component_id` (`component_id`),
+    CONSTRAINT `fk_product_components_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_components_component` FOREIGN KEY (`component_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+ADD COLUMN `deduct_own_stock` tinyint(1) NOT NULL DEFAULT 0 AFTER `variant_name`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+ADD COLUMN `cost` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `price`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Earlier sales are costed at the product's current harga_modal
+UPDATE `transaction_items` ti
+JOIN `products` p ON p.id = ti.product_id
+SET ti.cost = p.harga_modal;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_item_deductions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `transaction_item_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    `quantity` int NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_item_deductions_transaction_item_id` (`transaction_item_id`),
+    CONSTRAINT `fk_transaction_item_deductions_item` FOREIGN KEY (`transaction_item_id`) REFERENCES `transaction_items` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_transaction_item_deductions_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `transaction_item_deductions`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+DROP COLUMN `cost`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+DROP COLUMN `deduct_own_stock`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `product_components`;
+-- +goose StatementEnd
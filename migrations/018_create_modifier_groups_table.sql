@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `modifier_groups` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `min_select` int NOT NULL DEFAULT 0,
+    `max_select` int NOT NULL DEFAULT 0,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_modifier_groups_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_modifier_groups_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `modifier_options` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `group_id` int unsigned NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `price` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `position` int NOT NULL DEFAULT 0,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_modifier_options_group_id` (`group_id`),
+    CONSTRAINT `fk_modifier_options_group` FOREIGN KEY (`group_id`) REFERENCES `modifier_groups` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `product_modifier_groups` (
+    `product_id` int unsigned NOT NULL,
+    `modifier_group_id` int unsigned NOT NULL,
+    PRIMARY KEY (`product_id`, `modifier_group_id`),
+    KEY `idx_product_modifier_groups_modifier_group_id` (`modifier_group_id`),
+    CONSTRAINT `fk_product_modifier_groups_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_modifier_groups_group` FOREIGN KEY (`modifier_group_id`) REFERENCES `modifier_groups` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_item_modifiers` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `transaction_item_id` int unsigned NOT NULL,
+    `modifier_option_id` int unsigned NOT NULL,
+    `group_name` varchar(255) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `price` decimal(10,2) NOT NULL DEFAULT 0.00,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_item_modifiers_transaction_item_id` (`transaction_item_id`),
+    KEY `idx_transaction_item_modifiers_modifier_option_id` (`modifier_option_id`),
+    CONSTRAINT `fk_transaction_item_modifiers_item` FOREIGN KEY (`transaction_item_id`) REFERENCES `transaction_items` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `transaction_item_modifiers`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `product_modifier_groups`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `modifier_options`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `modifier_groups`;
+-- +goose StatementEnd
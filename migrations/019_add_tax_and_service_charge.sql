@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `tenants`
+ADD COLUMN `tax_rate` decimal(5,2) NOT NULL DEFAULT 0.00,
+ADD COLUMN `tax_inclusive` tinyint(1) NOT NULL DEFAULT 0,
+ADD COLUMN `service_charge_rate` decimal(5,2) NOT NULL DEFAULT 0.00;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+ADD COLUMN `tax_exempt` tinyint(1) NOT NULL DEFAULT 0 AFTER `reorder_qty`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `subtotal` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `discount`,
+ADD COLUMN `service_charge` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `subtotal`,
+ADD COLUMN `tax_base` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `service_charge`,
+ADD COLUMN `tax` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `tax_base`,
+ADD COLUMN `tax_rate` decimal(5,2) NOT NULL DEFAULT 0.00 AFTER `tax`,
+ADD COLUMN `tax_inclusive` tinyint(1) NOT NULL DEFAULT 0 AFTER `tax_rate`,
+ADD COLUMN `service_charge_rate` decimal(5,2) NOT NULL DEFAULT 0.00 AFTER `tax_inclusive`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Earlier sales had no tax or service charge; their subtotal is the sum of their items
+UPDATE `transactions` t
+JOIN (
+    SELECT transaction_id, SUM(price * quantity) AS subtotal
+    FROM transaction_items
+    GROUP BY transaction_id
+) ti ON ti.transaction_id = t.id
+SET t.subtotal = ti.subtotal;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+ADD COLUMN `tax_exempt` tinyint(1) NOT NULL DEFAULT 0 AFTER `cost`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_refund_items`
+ADD COLUMN `service_charge` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `amount`,
+ADD COLUMN `tax_base` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `service_charge`,
+ADD COLUMN `tax` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `tax_base`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transaction_refund_items`
+DROP COLUMN `tax`,
+DROP COLUMN `tax_base`,
+DROP COLUMN `service_charge`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+DROP COLUMN `tax_exempt`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP COLUMN `service_charge_rate`,
+DROP COLUMN `tax_inclusive`,
+DROP COLUMN `tax_rate`,
+DROP COLUMN `tax`,
+DROP COLUMN `tax_base`,
+DROP COLUMN `service_charge`,
+DROP COLUMN `subtotal`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `products`
+DROP COLUMN `tax_exempt`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `tenants`
+DROP COLUMN `service_charge_rate`,
+DROP COLUMN `tax_inclusive`,
+DROP COLUMN `tax_rate`;
+-- +goose StatementEnd
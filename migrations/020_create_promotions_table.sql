@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `promotions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `type` varchar(32) NOT NULL,
+    `code` varchar(64) NULL,
+    `active` tinyint(1) NOT NULL DEFAULT 1,
+    `buy_qty` int NOT NULL DEFAULT 0,
+    `get_qty` int NOT NULL DEFAULT 0,
+    `bundle_price` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `discount_amount` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `discount_percent` decimal(5,2) NOT NULL DEFAULT 0.00,
+    `min_spend` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `starts_at` timestamp NULL,
+    `ends_at` timestamp NULL,
+    `daily_start` varchar(5) NULL,
+    `daily_end` varchar(5) NULL,
+    `days` varchar(20) NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_promotions_code` (`code`),
+    KEY `idx_promotions_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_promotions_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `promotion_products` (
+    `promotion_id` int unsigned NOT NULL,
+    `product_id` int unsigned NOT NULL,
+    PRIMARY KEY (`promotion_id`, `product_id`),
+    KEY `idx_promotion_products_product_id` (`product_id`),
+    CONSTRAINT `fk_promotion_products_promotion` FOREIGN KEY (`promotion_id`) REFERENCES `promotions` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_promotion_products_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `transaction_item_promotions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `transaction_item_id` int unsigned NOT NULL,
+    `promotion_id` int unsigned NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `amount` decimal(10,2) NOT NULL DEFAULT 0.00,
+    PRIMARY KEY (`id`),
+    KEY `idx_transaction_item_promotions_transaction_item_id` (`transaction_item_id`),
+    KEY `idx_transaction_item_promotions_promotion_id` (`promotion_id`),
+    CONSTRAINT `fk_transaction_item_promotions_item` FOREIGN KEY (`transaction_item_id`) REFERENCES `transaction_items` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `promotion_discount` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `subtotal`,
+ADD COLUMN `voucher_code` varchar(64) NULL AFTER `promotion_discount`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+ADD COLUMN `discount` decimal(10,2) NOT NULL DEFAULT 0.00 AFTER `price`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transaction_items`
+DROP COLUMN `discount`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP COLUMN `voucher_code`,
+DROP COLUMN `promotion_discount`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `transaction_item_promotions`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `promotion_products`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `promotions`;
+-- +goose StatementEnd
@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `promotions`
+ADD COLUMN `voucher_only` tinyint(1) NOT NULL DEFAULT 0 AFTER `active`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `vouchers` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `promotion_id` int unsigned NOT NULL,
+    `code` varchar(64) NOT NULL,
+    `max_uses` int NOT NULL DEFAULT 1,
+    `max_uses_per_customer` int NOT NULL DEFAULT 0,
+    `used_count` int NOT NULL DEFAULT 0,
+    `min_basket` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `expires_at` timestamp NULL,
+    `active` tinyint(1) NOT NULL DEFAULT 1,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_vouchers_tenant_code` (`tenant_id`, `code`),
+    KEY `idx_vouchers_promotion_id` (`promotion_id`),
+    CONSTRAINT `fk_vouchers_promotion` FOREIGN KEY (`promotion_id`) REFERENCES `promotions` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_vouchers_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `voucher_redemptions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `voucher_id` int unsigned NOT NULL,
+    `transaction_id` int unsigned NOT NULL,
+    `customer_phone` varchar(32) NULL,
+    `discount` decimal(10,2) NOT NULL DEFAULT 0.00,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_voucher_redemptions_voucher_id` (`voucher_id`),
+    KEY `idx_voucher_redemptions_transaction_id` (`transaction_id`),
+    KEY `idx_voucher_redemptions_customer_phone` (`customer_phone`),
+    CONSTRAINT `fk_voucher_redemptions_voucher` FOREIGN KEY (`voucher_id`) REFERENCES `vouchers` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_voucher_redemptions_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `customer_phone` varchar(32) NULL AFTER `voucher_code`,
+ADD KEY `idx_transactions_customer_phone` (`customer_phone`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP KEY `idx_transactions_customer_phone`,
+DROP COLUMN `customer_phone`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `voucher_redemptions`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `vouchers`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `promotions`
+DROP COLUMN `voucher_only`;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `customers` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `phone` varchar(32) NOT NULL,
+    `email` varchar(255) NULL,
+    `notes` text NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_customers_tenant_phone` (`tenant_id`, `phone`),
+    CONSTRAINT `fk_customers_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `customer_id` int unsigned NULL AFTER `voucher_code`,
+ADD KEY `idx_transactions_customer_id` (`customer_id`),
+ADD CONSTRAINT `fk_transactions_customer` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP FOREIGN KEY `fk_transactions_customer`,
+DROP KEY `idx_transactions_customer_id`,
+DROP COLUMN `customer_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `customers`;
+-- +goose StatementEnd
@@ -0,0 +1,72 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `loyalty_programs` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `enabled` tinyint(1) NOT NULL DEFAULT 0,
+    `earn_spend` decimal(10,2) NOT NULL,
+    `redeem_value` decimal(10,2) NOT NULL,
+    `expiry_days` int NOT NULL DEFAULT 0,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_loyalty_programs_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_loyalty_programs_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `loyalty_tiers` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `program_id` int unsigned NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `min_points` int NOT NULL DEFAULT 0,
+    `earn_multiplier` double NOT NULL DEFAULT 1,
+    PRIMARY KEY (`id`),
+    KEY `idx_loyalty_tiers_program_id` (`program_id`),
+    CONSTRAINT `fk_loyalty_tiers_program` FOREIGN KEY (`program_id`) REFERENCES `loyalty_programs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `loyalty_ledger_entries` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `phone` varchar(32) NOT NULL,
+    `customer_id` int unsigned NULL,
+    `transaction_id` int unsigned NULL,
+    `type` varchar(20) NOT NULL,
+    `points` int NOT NULL,
+    `expires_at` timestamp NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_loyalty_ledger_tenant_phone` (`tenant_id`, `phone`),
+    KEY `idx_loyalty_ledger_entries_transaction_id` (`transaction_id`),
+    CONSTRAINT `fk_loyalty_ledger_entries_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `points_earned` int NOT NULL DEFAULT 0 AFTER `customer_id`,
+ADD COLUMN `points_redeemed` int NOT NULL DEFAULT 0 AFTER `points_earned`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP COLUMN `points_redeemed`,
+DROP COLUMN `points_earned`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `loyalty_ledger_entries`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `loyalty_tiers`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `loyalty_programs`;
+-- +goose StatementEnd
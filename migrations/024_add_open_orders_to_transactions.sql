@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `label` varchar(255) NULL AFTER `status`,
+ADD KEY `idx_transactions_status` (`status`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP KEY `idx_transactions_status`,
+DROP COLUMN `label`;
+-- +goose StatementEnd
@@ -0,0 +1,91 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `shifts` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `user_id` int unsigned NOT NULL,
+    `status` varchar(20) NOT NULL DEFAULT 'open',
+    `opening_float` decimal(10,2) NOT NULL DEFAULT 0,
+    `expected_cash` decimal(10,2) NOT NULL DEFAULT 0,
+    `counted_cash` decimal(10,2) NOT NULL DEFAULT 0,
+    `cash_difference` decimal(10,2) NOT NULL DEFAULT 0,
+    `notes` text NULL,
+    `closed_by` int unsigned NULL,
+    `closed_at` timestamp NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_shifts_user_id` (`user_id`),
+    KEY `idx_shifts_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_shifts_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+    CONSTRAINT `fk_shifts_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `shift_cash_events` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `shift_id` int unsigned NOT NULL,
+    `type` varchar(20) NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `reason` text NOT NULL,
+    `user_id` int unsigned NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_shift_cash_events_shift_id` (`shift_id`),
+    CONSTRAINT `fk_shift_cash_events_shift` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `shift_counts` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `shift_id` int unsigned NOT NULL,
+    `method` varchar(50) NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_shift_counts_shift_method` (`shift_id`, `method`),
+    CONSTRAINT `fk_shift_counts_shift` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+ADD COLUMN `shift_id` int unsigned NULL AFTER `label`,
+ADD KEY `idx_transactions_shift_id` (`shift_id`),
+ADD CONSTRAINT `fk_transactions_shift` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transaction_refunds`
+ADD COLUMN `shift_id` int unsigned NULL AFTER `user_id`,
+ADD KEY `idx_transaction_refunds_shift_id` (`shift_id`),
+ADD CONSTRAINT `fk_transaction_refunds_shift` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transaction_refunds`
+DROP FOREIGN KEY `fk_transaction_refunds_shift`,
+DROP KEY `idx_transaction_refunds_shift_id`,
+DROP COLUMN `shift_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `transactions`
+DROP FOREIGN KEY `fk_transactions_shift`,
+DROP KEY `idx_transactions_shift_id`,
+DROP COLUMN `shift_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `shift_counts`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `shift_cash_events`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `shifts`;
+-- +goose StatementEnd
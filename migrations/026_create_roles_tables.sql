@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `roles` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(50) NOT NULL,
+    `description` varchar(255) NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_roles_tenant_name` (`tenant_id`, `name`),
+    CONSTRAINT `fk_roles_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `role_permissions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `role_id` int unsigned NOT NULL,
+    `permission` varchar(50) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_role_permissions_role_permission` (`role_id`, `permission`),
+    CONSTRAINT `fk_role_permissions_role` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Existing accounts were provisioned per tenant before roles existed and keep full access
+UPDATE `users` SET `role` = 'owner' WHERE `role` = 'user';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `users` ALTER COLUMN `role` SET DEFAULT 'cashier';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `users` ALTER COLUMN `role` SET DEFAULT 'user';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `role_permissions`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `roles`;
+-- +goose StatementEnd
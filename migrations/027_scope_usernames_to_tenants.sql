@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `users`
+ADD COLUMN `active` tinyint(1) NOT NULL DEFAULT 1 AFTER `role`,
+ADD UNIQUE KEY `idx_users_tenant_username` (`tenant_id`, `username`),
+DROP KEY `idx_users_username`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `users`
+ADD UNIQUE KEY `idx_users_username` (`username`),
+DROP KEY `idx_users_tenant_username`,
+DROP COLUMN `active`;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sessions` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `user_id` int unsigned NOT NULL,
+    `refresh_token_hash` varchar(64) NOT NULL,
+    `previous_token_hash` varchar(64) NULL,
+    `access_token_id` varchar(64) NOT NULL,
+    `access_expires_at` datetime NOT NULL,
+    `user_agent` varchar(255) NULL,
+    `ip_address` varchar(45) NULL,
+    `expires_at` datetime NOT NULL,
+    `last_used_at` datetime NOT NULL,
+    `revoked_at` datetime NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_sessions_refresh_token_hash` (`refresh_token_hash`),
+    KEY `idx_sessions_previous_token_hash` (`previous_token_hash`),
+    KEY `idx_sessions_user_id` (`user_id`),
+    KEY `idx_sessions_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_sessions_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_sessions_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Access tokens revoked before they expire; rows are purged once the token would have expired
+CREATE TABLE `revoked_tokens` (
+    `jti` varchar(64) NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`jti`),
+    KEY `idx_revoked_tokens_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `revoked_tokens`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sessions`;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `terminals` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `token_hash` varchar(64) NOT NULL,
+    `last_seen_at` datetime NULL,
+    `revoked_at` datetime NULL,
+    `tenant_id` int unsigned NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_terminals_token_hash` (`token_hash`),
+    KEY `idx_terminals_tenant_id` (`tenant_id`),
+    CONSTRAINT `fk_terminals_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `users`
+ADD COLUMN `pin_hash` varchar(255) NOT NULL DEFAULT '' AFTER `password`,
+ADD COLUMN `pin_failed_attempts` int NOT NULL DEFAULT 0 AFTER `pin_hash`,
+ADD COLUMN `pin_locked_until` datetime NULL AFTER `pin_failed_attempts`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sessions`
+ADD COLUMN `terminal_id` int unsigned NULL AFTER `access_expires_at`,
+ADD KEY `idx_sessions_terminal_id` (`terminal_id`),
+ADD CONSTRAINT `fk_sessions_terminal` FOREIGN KEY (`terminal_id`) REFERENCES `terminals` (`id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sessions`
+DROP FOREIGN KEY `fk_sessions_terminal`,
+DROP KEY `idx_sessions_terminal_id`,
+DROP COLUMN `terminal_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `users`
+DROP COLUMN `pin_locked_until`,
+DROP COLUMN `pin_failed_attempts`,
+DROP COLUMN `pin_hash`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `terminals`;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Failed login counters, used when LOGIN_ATTEMPT_STORE=db
+CREATE TABLE `login_attempts` (
+    `attempt_key` varchar(320) NOT NULL,
+    `failures` int NOT NULL DEFAULT 0,
+    `last_failure_at` datetime NULL,
+    `blocked_until` datetime NULL,
+    `locked` tinyint(1) NOT NULL DEFAULT 0,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`attempt_key`),
+    KEY `idx_login_attempts_last_failure_at` (`last_failure_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Audit entries are kept after users are deleted, so user_id has no foreign key
+CREATE TABLE `auth_audit_logs` (
+    `id` int unsigned NOT NULL AUTO_INCREMENT,
+    `event` varchar(32) NOT NULL,
+    `username` varchar(255) NULL,
+    `user_id` int unsigned NULL,
+    `ip_address` varchar(45) NULL,
+    `user_agent` varchar(255) NULL,
+    `detail` varchar(255) NULL,
+    `tenant_id` int unsigned NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_auth_audit_logs_event` (`event`),
+    KEY `idx_auth_audit_logs_username` (`username`),
+    KEY `idx_auth_audit_logs_user_id` (`user_id`),
+    KEY `idx_auth_audit_logs_tenant_id` (`tenant_id`),
+    KEY `idx_auth_audit_logs_created_at` (`created_at`),
+    CONSTRAINT `fk_auth_audit_logs_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `auth_audit_logs`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `login_attempts`;
+-- +goose StatementEnd